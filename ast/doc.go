@@ -0,0 +1,13 @@
+// Package ast contains the FHIR Shorthand (FSH) types that are used to represent FHIR data types in FSH.
+// The types are custom types that makes it easier to work with FSH data, and are not defined in the FSH grammar.
+//
+// The types are part of the public API of the module and follow its semantic
+// versioning: within a major version, fields are only added, and existing fields
+// keep their names, types, and JSON names. Documents serialized to JSON can be
+// checked against SchemaVersion, which changes whenever the JSON representation
+// changes incompatibly. Use the parse package to parse FSH into these types.
+package ast
+
+// SchemaVersion is the version of the JSON representation of the types in this
+// package.
+const SchemaVersion = 1
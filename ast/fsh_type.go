@@ -1,4 +1,4 @@
-package types
+package ast
 
 import "fmt"
 
@@ -11,6 +11,8 @@ type FSHDocument struct {
 	CodeSystems []*CodeSystem `json:"codeSystems"`
 	Instances   []*Instance   `json:"instances"`
 	Extensions  []*Extension  `json:"extensions"`
+	RuleSets    []*RuleSet    `json:"ruleSets"`
+	Invariants  []*Invariant  `json:"invariants"`
 }
 
 func (doc *FSHDocument) String() string {
@@ -200,3 +202,34 @@ type Extension struct {
 	Contexts       []*ParsedElement[string] `json:"contexts"`
 	ExtensionRules *StructureDefRules       `json:"profileRules"`
 }
+
+// RuleSet represents a FSH RuleSet, with or without parameters. Only the name and
+// parameters are parsed, since the meaning of the rules depends on the entity the
+// rule set is inserted into.
+// See https://build.fhir.org/ig/HL7/fhir-shorthand/reference.html#defining-rule-sets for details.
+type RuleSet struct {
+	Name       *ParsedElement[string]   `json:"name"`
+	Parameters []*ParsedElement[string] `json:"parameters"`
+}
+
+func (rs *RuleSet) String() string {
+	return fmt.Sprintf("RuleSet{\n  Name: %v,\n  Parameters: %v\n}", rs.Name, rs.Parameters)
+}
+
+// Invariant represents a FSH Invariant. Severity is the code of the severity,
+// including the leading '#'.
+// See https://build.fhir.org/ig/HL7/fhir-shorthand/reference.html#defining-invariants for details.
+type Invariant struct {
+	Name        *ParsedElement[string] `json:"name"`
+	Description *ParsedElement[string] `json:"description"`
+	Expression  *ParsedElement[string] `json:"expression"`
+	XPath       *ParsedElement[string] `json:"xpath"`
+	Severity    *ParsedElement[string] `json:"severity"`
+}
+
+func (inv *Invariant) String() string {
+	return fmt.Sprintf(
+		"Invariant{\n  Name: %v,\n  Description: %v,\n  Expression: %v,\n  XPath: %v,\n  Severity: %v\n}",
+		inv.Name, inv.Description, inv.Expression, inv.XPath, inv.Severity,
+	)
+}
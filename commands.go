@@ -0,0 +1,296 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/pflag"
+	"github.com/verily-src/fsh-lint/internal/cli/format/wrap"
+	"github.com/verily-src/fsh-lint/internal/config"
+	"github.com/verily-src/fsh-lint/internal/docgen"
+	"github.com/verily-src/fsh-lint/internal/lsp"
+	"github.com/verily-src/fsh-lint/lint"
+)
+
+// command is a subcommand of the fsh-lint binary.
+type command struct {
+	// Name is the name used to invoke the command.
+	Name string
+
+	// Usage is the one line usage of the command.
+	Usage string
+
+	// Summary is a short description of what the command does.
+	Summary string
+
+	// Run runs the command with the given arguments, writing output to w.
+	Run func(w io.Writer, args []string) error
+}
+
+var (
+	lintCommand = &command{
+		Name:    "lint",
+		Usage:   "fsh-lint lint (--paths <paths> | --env <name>) [flags]",
+		Summary: "Lint FSH files. This is the default when no command is given.",
+		Run:     runLint,
+	}
+
+	commands []*command
+)
+
+func init() {
+	// commands is initialized in init to allow the help command to refer to it.
+	commands = []*command{
+		lintCommand,
+		{
+			Name:    "rules",
+			Usage:   "fsh-lint rules [--format text|json]",
+			Summary: "List the rules that the linter runs.",
+			Run:     runListRules,
+		},
+		{
+			Name:    "explain",
+			Usage:   "fsh-lint explain <rule-id>",
+			Summary: "Print the description and examples of a rule.",
+			Run:     runExplain,
+		},
+		{
+			Name:    "symbols",
+			Usage:   "fsh-lint symbols [--paths <paths>] [--format text|json]",
+			Summary: "List the entities defined in FSH files.",
+			Run:     runSymbols,
+		},
+		{
+			Name:    "refs",
+			Usage:   "fsh-lint refs <name> [--paths <paths>] [--format text|json]",
+			Summary: "Print the definition of an entity and every reference to it.",
+			Run:     runRefs,
+		},
+		{
+			Name:    "rename",
+			Usage:   "fsh-lint rename <name> <new-name> [--paths <paths>] [--dry-run]",
+			Summary: "Rename an entity and every reference to it.",
+			Run:     runRename,
+		},
+		{
+			Name:    "fmt",
+			Usage:   "fsh-lint fmt [--paths <paths>] [--check | --diff]",
+			Summary: "Format FSH files in a canonical style.",
+			Run:     runFmt,
+		},
+		{
+			Name:    "split",
+			Usage:   "fsh-lint split [--paths <paths>] [--dry-run]",
+			Summary: "Move the entities of FSH files into a file of their own each.",
+			Run:     runSplit,
+		},
+		{
+			Name:    "query",
+			Usage:   "fsh-lint query <query> [--paths <paths>] [--format text|json]",
+			Summary: "Print the elements of FSH files selected by a query.",
+			Run:     runQuery,
+		},
+		{
+			Name:    "lsp",
+			Usage:   "fsh-lint lsp [--config <path>]",
+			Summary: "Run a language server over stdio for editor integration.",
+			Run:     runLSP,
+		},
+		{
+			Name:    "help",
+			Usage:   "fsh-lint help",
+			Summary: "Print this help message.",
+			Run:     runHelp,
+		},
+	}
+}
+
+// findCommand returns the command with the given name, or nil if there is none.
+func findCommand(name string) *command {
+	for _, cmd := range commands {
+		if cmd.Name == name {
+			return cmd
+		}
+	}
+	return nil
+}
+
+// runHelp prints the usage of all commands.
+func runHelp(w io.Writer, _ []string) error {
+	_, _ = fmt.Fprintln(w, "Usage: fsh-lint <command> [arguments]")
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Commands:")
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, cmd := range commands {
+		_, _ = fmt.Fprintf(tw, "  %s\t%s\n", cmd.Name, cmd.Summary)
+	}
+	_ = tw.Flush()
+	_, _ = fmt.Fprintln(w)
+	_, _ = fmt.Fprintln(w, "Run 'fsh-lint <command> --help' for the flags of a command.")
+	return nil
+}
+
+// ruleInfo is the JSON representation of a rule listed by the rules command.
+type ruleInfo struct {
+	ID          string            `json:"id"`
+	Category    lint.Category     `json:"category"`
+	Severity    string            `json:"severity"`
+	Fixable     bool              `json:"fixable"`
+	UnsafeFix   bool              `json:"unsafeFix,omitempty"`
+	OptIn       bool              `json:"optIn,omitempty"`
+	EntityKinds []lint.EntityKind `json:"entityKinds,omitempty"`
+	DocsURL     string            `json:"docsUrl"`
+}
+
+// runListRules lists the ID, category, default severity, and fixability of
+// every rule the linter runs, in text or JSON format. Rules with unsafe fixes
+// are listed as unsafe, and opt-in rules as opt-in in JSON format.
+func runListRules(w io.Writer, args []string) error {
+	fs := pflag.NewFlagSet("rules", pflag.ContinueOnError)
+	fs.String("config", "", fmt.Sprintf("Path to the configuration file, whose custom rules are included. Defaults to %s in the working directory, if it exists.", config.FileName))
+	format := fs.String("format", "text", "The format to list the rules in, one of: text, json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	cfg, err := configFromFlags(fs)
+	if err != nil {
+		return err
+	}
+	if err := applyConfig(Linter, cfg); err != nil {
+		return err
+	}
+
+	var infos []*ruleInfo
+	for _, rule := range docgen.UniqueRules(Linter.Rules()) {
+		md := lint.MetadataOf(rule)
+		infos = append(infos, &ruleInfo{
+			ID:          rule.ID(),
+			Category:    md.Category,
+			Severity:    string(md.Severity),
+			Fixable:     md.Fixable,
+			UnsafeFix:   md.Fixable && md.UnsafeFix,
+			OptIn:       md.OptIn,
+			EntityKinds: md.EntityKinds,
+			DocsURL:     md.DocsURL,
+		})
+	}
+
+	switch *format {
+	case "json":
+		data, err := json.MarshalIndent(infos, "", "  ")
+		if err != nil {
+			return err
+		}
+		_, err = fmt.Fprintf(w, "%s\n", data)
+		return err
+	case "text":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "ID\tCATEGORY\tSEVERITY\tFIXABLE")
+		for _, info := range infos {
+			fixable := fmt.Sprint(info.Fixable)
+			if info.UnsafeFix {
+				fixable = "unsafe"
+			}
+			_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\t%s\n", info.ID, info.Category, info.Severity, fixable)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("invalid format: %s", *format)
+	}
+}
+
+// runExplain prints the description, examples, and documentation link of the
+// rule with the given ID.
+func runExplain(w io.Writer, args []string) error {
+	fs := pflag.NewFlagSet("explain", pflag.ContinueOnError)
+	fs.String("config", "", fmt.Sprintf("Path to the configuration file, whose custom rules are included. Defaults to %s in the working directory, if it exists.", config.FileName))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("explain requires exactly one rule ID, run 'fsh-lint rules' to list them")
+	}
+	cfg, err := configFromFlags(fs)
+	if err != nil {
+		return err
+	}
+	if err := applyConfig(Linter, cfg); err != nil {
+		return err
+	}
+
+	id := fs.Arg(0)
+	var rule lint.Rule
+	for _, r := range Linter.Rules() {
+		if r.ID() == id {
+			rule = r
+			break
+		}
+	}
+	if rule == nil {
+		return fmt.Errorf("unknown rule %q, run 'fsh-lint rules' to list them", id)
+	}
+
+	md := lint.MetadataOf(rule)
+	var sb strings.Builder
+	_, _ = fmt.Fprintf(&sb, "%s\n\n", rule.ID())
+	_, _ = fmt.Fprintf(&sb, "Category: %s\n", md.Category)
+	_, _ = fmt.Fprintf(&sb, "Severity: %s\n", md.Severity)
+	if md.Fixable && md.UnsafeFix {
+		_, _ = fmt.Fprintf(&sb, "Fixable:  %t, some fixes are unsafe\n\n", md.Fixable)
+	} else {
+		_, _ = fmt.Fprintf(&sb, "Fixable:  %t\n\n", md.Fixable)
+	}
+	wrapper := wrap.NewWrapper(80)
+	for _, line := range strings.Split(md.Description, "\n") {
+		if line != "" {
+			line = wrapper.String(line)
+		}
+		_, _ = fmt.Fprintln(&sb, line)
+	}
+	for _, example := range md.Examples {
+		verdict := "Incorrect"
+		if example.Good {
+			verdict = "Correct"
+		}
+		_, _ = fmt.Fprintf(&sb, "\n%s:", verdict)
+		if example.Description != "" {
+			_, _ = fmt.Fprintf(&sb, " %s", example.Description)
+		}
+		_, _ = fmt.Fprintln(&sb)
+		for _, line := range strings.Split(strings.TrimSpace(example.FSH), "\n") {
+			_, _ = fmt.Fprintf(&sb, "    %s\n", line)
+		}
+	}
+	_, _ = fmt.Fprintf(&sb, "\nSee: %s\n", md.DocsURL)
+
+	_, err = io.WriteString(w, sb.String())
+	return err
+}
+
+// runLSP serves the Language Server Protocol over stdin and stdout, linting the
+// documents opened in the editor. Logs are written to stderr, as stdout is used
+// by the protocol.
+func runLSP(_ io.Writer, args []string) error {
+	fs := pflag.NewFlagSet("lsp", pflag.ContinueOnError)
+	fs.String("config", "", fmt.Sprintf("Path to the configuration file. Defaults to %s in the working directory, if it exists.", config.FileName))
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	cfg, err := configFromFlags(fs)
+	if err != nil {
+		return err
+	}
+	if err := applyConfig(Linter, cfg); err != nil {
+		return err
+	}
+
+	server := lsp.NewServer(Linter, linterVersion())
+	server.Logger = log.New(os.Stderr, "fsh-lint lsp: ", log.LstdFlags)
+	return server.Serve(os.Stdin, os.Stdout)
+}
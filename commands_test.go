@@ -0,0 +1,541 @@
+package main
+
+import (
+	"bytes"
+	"encoding/json"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestRun(t *testing.T) {
+	for _, cmd := range commands {
+		if cmd == lintCommand {
+			// The lint command exits on --help, like the flag-only invocation.
+			continue
+		}
+		t.Run(cmd.Name, func(t *testing.T) {
+			args := []string{cmd.Name, "--help"}
+			if err := run(&bytes.Buffer{}, args); err != nil {
+				t.Errorf("run(%v) got error %v, want nil", args, err)
+			}
+		})
+	}
+
+	args := []string{"unknown"}
+	if err := run(&bytes.Buffer{}, args); err == nil {
+		t.Errorf("run(%v) got nil error, want error", args)
+	}
+}
+
+func TestRunListRules(t *testing.T) {
+	testCases := []struct {
+		name     string
+		args     []string
+		wantOut  string
+		wantErr  bool
+		wantJSON bool
+	}{
+		{
+			name:    "text format",
+			args:    nil,
+			wantOut: "profile-name-matches-id            Profile      notice    unsafe",
+		}, {
+			name:     "json format",
+			args:     []string{"--format", "json"},
+			wantOut:  `"id": "profile-name-matches-id"`,
+			wantJSON: true,
+		}, {
+			name:    "invalid format",
+			args:    []string{"--format", "yaml"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			err := runListRules(&out, tc.args)
+
+			if got, want := err != nil, tc.wantErr; got != want {
+				t.Fatalf("runListRules(%v) got error %v, want error %v", tc.args, err, want)
+			}
+			if got := out.String(); !strings.Contains(got, tc.wantOut) {
+				t.Errorf("runListRules(%v) got output %q, want it to contain %q", tc.args, got, tc.wantOut)
+			}
+			if tc.wantJSON {
+				var infos []*ruleInfo
+				if err := json.Unmarshal(out.Bytes(), &infos); err != nil {
+					t.Errorf("runListRules(%v) got invalid JSON: %v", tc.args, err)
+				}
+			}
+		})
+	}
+}
+
+func TestRunExplain(t *testing.T) {
+	testCases := []struct {
+		name    string
+		args    []string
+		wantOut []string
+		wantErr bool
+	}{
+		{
+			name: "known rule",
+			args: []string{"profile-name-matches-id"},
+			wantOut: []string{
+				"Category: Profile",
+				"Profile name (PascalCase) must match profile id in kebab-case.",
+				"Incorrect:\n    Profile: ExampleOne\n    Id: exampleone",
+				"See: https://github.com/verily-src/fsh-lint/blob/main/docs/rules.md#profile-name-matches-id",
+			},
+		}, {
+			name:    "unknown rule",
+			args:    []string{"not-a-rule"},
+			wantErr: true,
+		}, {
+			name:    "missing rule",
+			args:    nil,
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+
+			err := runExplain(&out, tc.args)
+
+			if got, want := err != nil, tc.wantErr; got != want {
+				t.Fatalf("runExplain(%v) got error %v, want error %v", tc.args, err, want)
+			}
+			for _, want := range tc.wantOut {
+				if got := out.String(); !strings.Contains(got, want) {
+					t.Errorf("runExplain(%v) got output %q, want it to contain %q", tc.args, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRunExplain_CustomRule(t *testing.T) {
+	t.Cleanup(func() {
+		Linter.Config = nil
+		Linter.CustomRules = nil
+	})
+
+	testCases := []struct {
+		name    string
+		config  string
+		args    []string
+		wantOut []string
+		wantErr bool
+	}{
+		{
+			name:   "custom rule",
+			config: "customRules:\n  - {id: verily-prefix, entity: Profile, field: name, pattern: ^Verily, severity: warning}\n",
+			args:   []string{"verily-prefix"},
+			wantOut: []string{
+				"Category: Profile",
+				"Severity: warning",
+				`Profile name must match "^Verily".`,
+			},
+		}, {
+			name:    "invalid field",
+			config:  "customRules:\n  - {id: verily-prefix, entity: Profile, field: version, required: true}\n",
+			args:    []string{"verily-prefix"},
+			wantErr: true,
+		}, {
+			name:    "ID of a built-in rule",
+			config:  "customRules:\n  - {id: profile-name-format, entity: Profile, field: name, required: true}\n",
+			args:    []string{"profile-name-format"},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			path := filepath.Join(t.TempDir(), ".fsh-lint.yaml")
+			if err := os.WriteFile(path, []byte(tc.config), 0644); err != nil {
+				t.Fatal(err)
+			}
+			args := append([]string{"--config", path}, tc.args...)
+			var out bytes.Buffer
+
+			err := runExplain(&out, args)
+
+			if got, want := err != nil, tc.wantErr; got != want {
+				t.Fatalf("runExplain(%v) got error %v, want error %v", args, err, want)
+			}
+			for _, want := range tc.wantOut {
+				if got := out.String(); !strings.Contains(got, want) {
+					t.Errorf("runExplain(%v) got output %q, want it to contain %q", args, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRunRename(t *testing.T) {
+	const profiles = "Profile: ExampleProfile\nParent: Patient\nId: example-profile\n* code from ExampleCodes (required)\n\n" +
+		"Instance: ExamplePatient\nInstanceOf: example-profile\n"
+	const terminology = "ValueSet: ExampleCodes\nId: example-codes\n* include codes from system ExampleSystem\n\n" +
+		"CodeSystem: ExampleSystem\n"
+
+	testCases := []struct {
+		name      string
+		args      []string
+		wantFiles map[string]string
+		wantOut   string
+		wantErr   bool
+	}{
+		{
+			name: "name",
+			args: []string{"ExampleCodes", "ExampleValueSet"},
+			wantFiles: map[string]string{
+				"Profiles.fsh":    strings.Replace(profiles, "ExampleCodes", "ExampleValueSet", 1),
+				"Terminology.fsh": strings.Replace(terminology, "ExampleCodes", "ExampleValueSet", 1),
+			},
+			wantOut: "Renamed ExampleCodes to ExampleValueSet in 2 files\n",
+		}, {
+			name: "ID",
+			args: []string{"example-profile", "patient-profile"},
+			wantFiles: map[string]string{
+				"Profiles.fsh":    strings.ReplaceAll(profiles, "example-profile", "patient-profile"),
+				"Terminology.fsh": terminology,
+			},
+			wantOut: "Renamed example-profile to patient-profile in 1 files\n",
+		}, {
+			name:      "dry run",
+			args:      []string{"ExampleSystem", "OtherSystem", "--dry-run"},
+			wantFiles: map[string]string{"Profiles.fsh": profiles, "Terminology.fsh": terminology},
+			wantOut:   "-* include codes from system ExampleSystem\n+* include codes from system OtherSystem\n",
+		}, {
+			name:      "undefined",
+			args:      []string{"Patient", "Person"},
+			wantFiles: map[string]string{"Profiles.fsh": profiles, "Terminology.fsh": terminology},
+			wantErr:   true,
+		}, {
+			name:      "already defined",
+			args:      []string{"ExampleCodes", "ExampleSystem"},
+			wantFiles: map[string]string{"Profiles.fsh": profiles, "Terminology.fsh": terminology},
+			wantErr:   true,
+		}, {
+			name:      "invalid name",
+			args:      []string{"ExampleCodes", "Example Codes"},
+			wantFiles: map[string]string{"Profiles.fsh": profiles, "Terminology.fsh": terminology},
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, data := range map[string]string{"Profiles.fsh": profiles, "Terminology.fsh": terminology} {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			args := append([]string{"--paths", dir}, tc.args...)
+			var out bytes.Buffer
+
+			err := runRename(&out, args)
+
+			if got, want := err != nil, tc.wantErr; got != want {
+				t.Fatalf("runRename(%v) got error %v, want error %v", args, err, want)
+			}
+			if got := out.String(); !strings.Contains(got, tc.wantOut) {
+				t.Errorf("runRename(%v) got output %q, want it to contain %q", args, got, tc.wantOut)
+			}
+			for name, want := range tc.wantFiles {
+				got, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != want {
+					t.Errorf("runRename(%v) got %s %q, want %q", args, name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteRenamed(t *testing.T) {
+	dir := t.TempDir()
+	// The temporary file of the second file has a name that is too long, so it
+	// cannot be written, although it can be read.
+	paths := []string{filepath.Join(dir, "A.fsh"), filepath.Join(dir, strings.Repeat("B", 250)+".fsh")}
+	original := map[string][]byte{
+		paths[0]: []byte("ValueSet: ExampleCodes\n"),
+		paths[1]: []byte("Profile: ExampleProfile\n* code from ExampleCodes\n"),
+	}
+	renamed := map[string][]byte{
+		paths[0]: []byte("ValueSet: OtherCodes\n"),
+		paths[1]: []byte("Profile: ExampleProfile\n* code from OtherCodes\n"),
+	}
+	for _, path := range paths {
+		if err := os.WriteFile(path, original[path], 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	if err := writeRenamed(paths, original, renamed); err == nil {
+		t.Fatal("writeRenamed() got nil error, want error")
+	}
+
+	// The first file is restored, so that no reference is left renamed.
+	for _, path := range paths {
+		if got, err := os.ReadFile(path); err != nil || string(got) != string(original[path]) {
+			t.Errorf("writeRenamed() changed %s to %q, %v", filepath.Base(path), got, err)
+		}
+	}
+}
+
+func TestRunFmt(t *testing.T) {
+	const formatted = "Profile: ExampleProfile\nParent: Patient\nId: example-profile\n* name 1..1\n"
+	const unformatted = "Profile: ExampleProfile\nId: example-profile\nParent: Patient\n*   name 1..1\n"
+
+	testCases := []struct {
+		name      string
+		args      []string
+		files     map[string]string
+		wantFiles map[string]string
+		wantOut   string
+		wantErr   bool
+		// wantErrText is the text of the error, if not empty.
+		wantErrText string
+	}{
+		{
+			name:      "formats files",
+			files:     map[string]string{"Formatted.fsh": formatted, "Unformatted.fsh": unformatted},
+			wantFiles: map[string]string{"Formatted.fsh": formatted, "Unformatted.fsh": formatted},
+			wantOut:   "Unformatted.fsh\n",
+		}, {
+			name:        "check",
+			args:        []string{"--check"},
+			files:       map[string]string{"Formatted.fsh": formatted, "Unformatted.fsh": unformatted},
+			wantFiles:   map[string]string{"Formatted.fsh": formatted, "Unformatted.fsh": unformatted},
+			wantOut:     "Unformatted.fsh\n",
+			wantErr:     true,
+			wantErrText: "1 file(s) not formatted",
+		}, {
+			name:      "check formatted",
+			args:      []string{"--check"},
+			files:     map[string]string{"Formatted.fsh": formatted},
+			wantFiles: map[string]string{"Formatted.fsh": formatted},
+		}, {
+			name:      "diff",
+			args:      []string{"--diff"},
+			files:     map[string]string{"Unformatted.fsh": unformatted},
+			wantFiles: map[string]string{"Unformatted.fsh": unformatted},
+			wantOut:   "-*   name 1..1\n+Id: example-profile\n+* name 1..1\n",
+			wantErr:   true,
+		}, {
+			name:      "syntax error",
+			files:     map[string]string{"Invalid.fsh": "Profile: A\n* #x, #y\n", "Unformatted.fsh": unformatted},
+			wantFiles: map[string]string{"Invalid.fsh": "Profile: A\n* #x, #y\n", "Unformatted.fsh": formatted},
+			wantErr:   true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, data := range tc.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			args := append([]string{"--paths", dir}, tc.args...)
+			var out bytes.Buffer
+
+			err := runFmt(&out, args)
+
+			if got, want := err != nil, tc.wantErr; got != want {
+				t.Fatalf("runFmt(%v) got error %v, want error %v", args, err, want)
+			}
+			if tc.wantErrText != "" && err.Error() != tc.wantErrText {
+				t.Errorf("runFmt(%v) got error %q, want %q", args, err, tc.wantErrText)
+			}
+			if got := out.String(); !strings.Contains(got, tc.wantOut) {
+				t.Errorf("runFmt(%v) got output %q, want it to contain %q", args, got, tc.wantOut)
+			}
+			for name, want := range tc.wantFiles {
+				got, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != want {
+					t.Errorf("runFmt(%v) got %s %q, want %q", args, name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestRunSplit(t *testing.T) {
+	const profile = "Profile: ExampleProfile\nParent: Patient\n"
+	const valueSet = "ValueSet: ExampleCodes\n* include codes from system http://example.org\n"
+
+	testCases := []struct {
+		name      string
+		args      []string
+		files     map[string]string
+		wantFiles map[string]string
+		wantOut   string
+		wantErr   bool
+	}{
+		{
+			name:  "splits files",
+			files: map[string]string{"ExampleProfile.fsh": "Alias: $ex = http://example.org\n\n" + profile + "\n" + valueSet},
+			wantFiles: map[string]string{
+				"ExampleProfile.fsh": "Alias: $ex = http://example.org\n\n" + profile,
+				"ExampleCodes.fsh":   valueSet,
+			},
+			wantOut: "Split 1 files into 1 new files\n",
+		}, {
+			name:  "removes emptied files",
+			files: map[string]string{"Entities.fsh": profile + "\n" + valueSet},
+			wantFiles: map[string]string{
+				"ExampleProfile.fsh": profile,
+				"ExampleCodes.fsh":   valueSet,
+			},
+			wantOut: "Split 1 files into 2 new files\n",
+		}, {
+			name:      "one entity",
+			files:     map[string]string{"Other.fsh": profile},
+			wantFiles: map[string]string{"Other.fsh": profile},
+			wantOut:   "Split 0 files into 0 new files\n",
+		}, {
+			name:      "dry run",
+			args:      []string{"--dry-run"},
+			files:     map[string]string{"ExampleProfile.fsh": profile + "\n" + valueSet},
+			wantFiles: map[string]string{"ExampleProfile.fsh": profile + "\n" + valueSet},
+			wantOut:   "+ValueSet: ExampleCodes\n",
+		}, {
+			name: "existing file",
+			files: map[string]string{
+				"ExampleProfile.fsh": profile + "\n" + valueSet,
+				"ExampleCodes.fsh":   "// Codes\n",
+			},
+			wantFiles: map[string]string{
+				"ExampleProfile.fsh": profile + "\n" + valueSet,
+				"ExampleCodes.fsh":   "// Codes\n",
+			},
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			for name, data := range tc.files {
+				if err := os.WriteFile(filepath.Join(dir, name), []byte(data), 0644); err != nil {
+					t.Fatal(err)
+				}
+			}
+			args := append([]string{"--paths", dir}, tc.args...)
+			var out bytes.Buffer
+
+			err := runSplit(&out, args)
+
+			if got, want := err != nil, tc.wantErr; got != want {
+				t.Fatalf("runSplit(%v) got error %v, want error %v", args, err, want)
+			}
+			if got := out.String(); !strings.Contains(got, tc.wantOut) {
+				t.Errorf("runSplit(%v) got output %q, want it to contain %q", args, got, tc.wantOut)
+			}
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if got, want := len(entries), len(tc.wantFiles); got != want {
+				t.Errorf("runSplit(%v) left %d files, want %d", args, got, want)
+			}
+			for name, want := range tc.wantFiles {
+				got, err := os.ReadFile(filepath.Join(dir, name))
+				if err != nil {
+					t.Fatal(err)
+				}
+				if string(got) != want {
+					t.Errorf("runSplit(%v) got %s %q, want %q", args, name, got, want)
+				}
+			}
+		})
+	}
+}
+
+func TestWriteSplits(t *testing.T) {
+	const source = "Profile: A\nParent: Patient\n\nProfile: B\nParent: Patient\n"
+
+	testCases := []struct {
+		name string
+		// data is the contents of the source when it was read.
+		data string
+		// newFile is the path of the new file, relative to the directory.
+		newFile string
+	}{
+		{
+			name:    "source changed",
+			data:    "Profile: A\n",
+			newFile: "B.fsh",
+		}, {
+			name:    "new file cannot be created",
+			data:    source,
+			newFile: filepath.Join("missing", "B.fsh"),
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			writeFile := func(name, data string) string {
+				path := filepath.Join(dir, name)
+				if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+					t.Fatal(err)
+				}
+				return path
+			}
+			splits := []*splitFile{
+				{
+					path:      writeFile("A.fsh", source),
+					data:      []byte(tc.data),
+					remaining: []byte("Profile: A\nParent: Patient\n"),
+					files:     map[string][]byte{filepath.Join(dir, "A1.fsh"): []byte("Profile: A1\n")},
+				},
+				{
+					path:      writeFile("C.fsh", source),
+					data:      []byte(source),
+					remaining: []byte("Profile: A\nParent: Patient\n"),
+					files:     map[string][]byte{filepath.Join(dir, tc.newFile): []byte("Profile: B\nParent: Patient\n")},
+				},
+			}
+
+			if err := writeSplits(splits); err == nil {
+				t.Fatal("writeSplits() got nil error, want error")
+			}
+
+			// Nothing changes, so that no entity is defined twice.
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			var names []string
+			for _, e := range entries {
+				names = append(names, e.Name())
+			}
+			if diff := cmp.Diff([]string{"A.fsh", "C.fsh"}, names); diff != "" {
+				t.Errorf("writeSplits() files mismatch (-want +got):\n%s", diff)
+			}
+			for _, s := range splits {
+				if got, err := os.ReadFile(s.path); err != nil || string(got) != source {
+					t.Errorf("writeSplits() changed %s to %q, %v", s.path, got, err)
+				}
+			}
+		})
+	}
+}
@@ -0,0 +1,66 @@
+package main
+
+import (
+	"bytes"
+	"flag"
+	"os"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/docgen"
+	"github.com/verily-src/fsh-lint/lint"
+)
+
+var update = flag.Bool("update", false, "update the generated documentation")
+
+const (
+	rulesDocPath = "docs/rules.md"
+	readmePath   = "README.md"
+)
+
+func TestRulesAreDocumented(t *testing.T) {
+	for _, rule := range Linter.Rules() {
+		if _, ok := rule.(lint.Documented); !ok {
+			t.Errorf("rule %s does not implement lint.Documented", rule.ID())
+		}
+	}
+}
+
+func TestGeneratedDocs(t *testing.T) {
+	rules := Linter.Rules()
+
+	readme, err := os.ReadFile(readmePath)
+	if err != nil {
+		t.Fatalf("os.ReadFile(%q): got error %v, want nil", readmePath, err)
+	}
+	wantReadme, err := docgen.ReplaceSection(readme, docgen.RuleList(rules, rulesDocPath))
+	if err != nil {
+		t.Fatalf("docgen.ReplaceSection(): got error %v, want nil", err)
+	}
+
+	testCases := []struct {
+		path string
+		want []byte
+	}{
+		{path: rulesDocPath, want: docgen.RulesMarkdown(rules)},
+		{path: readmePath, want: wantReadme},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.path, func(t *testing.T) {
+			if *update {
+				if err := os.WriteFile(tc.path, tc.want, 0644); err != nil {
+					t.Fatalf("os.WriteFile(%q): got error %v, want nil", tc.path, err)
+				}
+			}
+
+			got, err := os.ReadFile(tc.path)
+			if err != nil {
+				t.Fatalf("os.ReadFile(%q): got error %v, want nil", tc.path, err)
+			}
+			if !bytes.Equal(got, tc.want) {
+				t.Errorf("%s is out of date, run `go generate` (-got +want):\n%s", tc.path, cmp.Diff(string(got), string(tc.want)))
+			}
+		})
+	}
+}
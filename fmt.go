@@ -0,0 +1,87 @@
+package main
+
+import (
+	"bytes"
+	"fmt"
+	"io"
+	"log"
+	"os"
+
+	"github.com/spf13/pflag"
+	"github.com/verily-src/fsh-lint/internal/atomicfile"
+	"github.com/verily-src/fsh-lint/internal/format"
+	"github.com/verily-src/fsh-lint/internal/textdiff"
+)
+
+// runFmt formats the selected files in the canonical style of the format
+// package. With --check, the files that are not formatted are listed instead,
+// and with --diff, a unified diff of the changes is printed. Both return an
+// error if any file is not formatted, so that CI can check formatting.
+func runFmt(w io.Writer, args []string) error {
+	fs := pflag.NewFlagSet("fmt", pflag.ContinueOnError)
+	installSelectionFlags(fs)
+	check := fs.Bool("check", false, "List the files that are not formatted without modifying them.")
+	diff := fs.Bool("diff", false, "Print a unified diff of the changes without modifying files.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+	paths, err := inputPathsFromFlags(fs)
+	if err != nil {
+		return err
+	}
+	if paths == nil {
+		paths = []string{"."}
+	}
+	ignored, err := ignoreMatchersFromFlags(fs)
+	if err != nil {
+		return err
+	}
+
+	failed, unformatted := 0, 0
+	for _, path := range fshFilesFromPaths(paths, ignored) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Error reading %s: %v", path, err)
+			failed++
+			continue
+		}
+		formatted, err := format.Source(data)
+		if err != nil {
+			log.Printf("Error formatting %s: %v", path, err)
+			failed++
+			continue
+		}
+		if bytes.Equal(data, formatted) {
+			continue
+		}
+		unformatted++
+
+		switch {
+		case *diff:
+			_, err = io.WriteString(w, textdiff.Unified(path, data, formatted))
+		case *check:
+			_, err = fmt.Fprintln(w, path)
+		default:
+			if err := atomicfile.Write(path, data, formatted); err != nil {
+				log.Printf("Error writing %s: %v", path, err)
+				failed++
+				continue
+			}
+			_, err = fmt.Fprintf(w, "Formatted %s\n", path)
+		}
+		if err != nil {
+			return err
+		}
+	}
+
+	switch {
+	case failed > 0:
+		return fmt.Errorf("%d file(s) could not be formatted", failed)
+	case unformatted > 0 && (*check || *diff):
+		return fmt.Errorf("%d file(s) not formatted", unformatted)
+	}
+	return nil
+}
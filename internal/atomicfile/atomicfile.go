@@ -0,0 +1,101 @@
+// Package atomicfile replaces the contents of files atomically, so that an
+// interrupted write never leaves a truncated file behind.
+package atomicfile
+
+import (
+	"crypto/sha256"
+	"errors"
+	"fmt"
+	"os"
+	"path/filepath"
+)
+
+// ErrModified is returned by Write when the file changed on disk since its
+// original contents were read.
+var ErrModified = errors.New("file changed on disk since it was read")
+
+// Write replaces the contents of the existing file at path, whose contents were
+// original when they were read, with data. The data is written to a temporary
+// file in the same directory, with the mode of the original file, which is then
+// renamed over it. Symbolic links are followed, so that the link is kept.
+// Returns ErrModified, without writing, if the hash of the current contents of
+// the file does not match the hash of original.
+func Write(path string, original, data []byte) error {
+	target, err := filepath.EvalSymlinks(path)
+	if err != nil {
+		return err
+	}
+	info, err := os.Stat(target)
+	if err != nil {
+		return err
+	}
+	if err := Check(path, original); err != nil {
+		return err
+	}
+
+	tmp, err := os.CreateTemp(filepath.Dir(target), "."+filepath.Base(target)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	if err := writeTemp(tmp, data, info.Mode().Perm()); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	if err := os.Rename(tmp.Name(), target); err != nil {
+		_ = os.Remove(tmp.Name())
+		return err
+	}
+	return nil
+}
+
+// Check returns ErrModified if the hash of the current contents of the file at
+// path does not match the hash of original, its contents when they were read.
+func Check(path string, original []byte) error {
+	current, err := os.ReadFile(path)
+	if err != nil {
+		return err
+	}
+	if sha256.Sum256(current) != sha256.Sum256(original) {
+		return fmt.Errorf("%s: %w", path, ErrModified)
+	}
+	return nil
+}
+
+// Remove removes the file at path, whose contents were original when they were
+// read. Returns ErrModified, without removing it, if the hash of the current
+// contents of the file does not match the hash of original.
+func Remove(path string, original []byte) error {
+	if err := Check(path, original); err != nil {
+		return err
+	}
+	return os.Remove(path)
+}
+
+// Create creates the file at path with data and the given mode, atomically, so
+// that the file either does not exist or has all of data. Returns an error that
+// wraps fs.ErrExist, without writing, if the file already exists.
+func Create(path string, data []byte, mode os.FileMode) error {
+	tmp, err := os.CreateTemp(filepath.Dir(path), "."+filepath.Base(path)+".*.tmp")
+	if err != nil {
+		return err
+	}
+	defer os.Remove(tmp.Name())
+	if err := writeTemp(tmp, data, mode.Perm()); err != nil {
+		return err
+	}
+	// Unlike a rename, a link fails if the file exists.
+	return os.Link(tmp.Name(), path)
+}
+
+// writeTemp writes data to the temporary file, sets its mode, and flushes and
+// closes it.
+func writeTemp(tmp *os.File, data []byte, mode os.FileMode) error {
+	_, err := tmp.Write(data)
+	if err == nil {
+		err = tmp.Chmod(mode)
+	}
+	if err == nil {
+		err = tmp.Sync()
+	}
+	return errors.Join(err, tmp.Close())
+}
@@ -0,0 +1,148 @@
+package atomicfile_test
+
+import (
+	"errors"
+	"io/fs"
+	"os"
+	"path/filepath"
+	"runtime"
+	"testing"
+
+	"github.com/verily-src/fsh-lint/internal/atomicfile"
+)
+
+func TestWrite(t *testing.T) {
+	tests := []struct {
+		name    string
+		current string
+		wantErr error
+		want    string
+	}{
+		{
+			name:    "unchanged file is replaced",
+			current: "Id: old\n",
+			want:    "Id: new\n",
+		},
+		{
+			name:    "changed file is not replaced",
+			current: "Id: edited\n",
+			wantErr: atomicfile.ErrModified,
+			want:    "Id: edited\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			dir := t.TempDir()
+			path := filepath.Join(dir, "Example.fsh")
+			if err := os.WriteFile(path, []byte(tt.current), 0600); err != nil {
+				t.Fatal(err)
+			}
+
+			err := atomicfile.Write(path, []byte("Id: old\n"), []byte("Id: new\n"))
+			if !errors.Is(err, tt.wantErr) {
+				t.Errorf("Write() error = %v, want %v", err, tt.wantErr)
+			}
+
+			got, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if string(got) != tt.want {
+				t.Errorf("Write() contents = %q, want %q", got, tt.want)
+			}
+			if runtime.GOOS != "windows" {
+				info, err := os.Stat(path)
+				if err != nil {
+					t.Fatal(err)
+				}
+				if mode := info.Mode().Perm(); mode != 0600 {
+					t.Errorf("Write() mode = %v, want %v", mode, os.FileMode(0600))
+				}
+			}
+			// No temporary files are left behind.
+			entries, err := os.ReadDir(dir)
+			if err != nil {
+				t.Fatal(err)
+			}
+			if len(entries) != 1 {
+				t.Errorf("Write() left %d files in the directory, want 1", len(entries))
+			}
+		})
+	}
+}
+
+func TestWrite_Symlink(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("symbolic links require privileges on Windows")
+	}
+	dir := t.TempDir()
+	target := filepath.Join(dir, "Target.fsh")
+	link := filepath.Join(dir, "Link.fsh")
+	if err := os.WriteFile(target, []byte("Id: old\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Symlink(target, link); err != nil {
+		t.Fatal(err)
+	}
+
+	if err := atomicfile.Write(link, []byte("Id: old\n"), []byte("Id: new\n")); err != nil {
+		t.Fatalf("Write() error = %v", err)
+	}
+
+	if info, err := os.Lstat(link); err != nil || info.Mode()&os.ModeSymlink == 0 {
+		t.Errorf("Write() replaced the symbolic link")
+	}
+	if got, err := os.ReadFile(target); err != nil || string(got) != "Id: new\n" {
+		t.Errorf("Write() target contents = %q, %v, want %q", got, err, "Id: new\n")
+	}
+}
+
+func TestCreate(t *testing.T) {
+	dir := t.TempDir()
+	path := filepath.Join(dir, "New.fsh")
+
+	if err := atomicfile.Create(path, []byte("Id: new\n"), 0600); err != nil {
+		t.Fatalf("Create() error = %v", err)
+	}
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if got := info.Mode().Perm(); got != 0600 {
+		t.Errorf("Create() mode = %v, want %v", got, os.FileMode(0600))
+	}
+
+	err = atomicfile.Create(path, []byte("Id: other\n"), 0600)
+	if !errors.Is(err, fs.ErrExist) {
+		t.Errorf("Create() of existing file error = %v, want fs.ErrExist", err)
+	}
+	if got, _ := os.ReadFile(path); string(got) != "Id: new\n" {
+		t.Errorf("Create() overwrote the existing file with %q", got)
+	}
+	if entries, _ := os.ReadDir(dir); len(entries) != 1 {
+		t.Errorf("Create() left %d files in the directory, want 1", len(entries))
+	}
+}
+
+func TestRemove(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Example.fsh")
+	if err := os.WriteFile(path, []byte("Id: changed\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	err := atomicfile.Remove(path, []byte("Id: old\n"))
+	if !errors.Is(err, atomicfile.ErrModified) {
+		t.Errorf("Remove() of modified file error = %v, want ErrModified", err)
+	}
+	if _, err := os.Stat(path); err != nil {
+		t.Errorf("Remove() removed the modified file: %v", err)
+	}
+
+	if err := atomicfile.Remove(path, []byte("Id: changed\n")); err != nil {
+		t.Fatalf("Remove() error = %v", err)
+	}
+	if _, err := os.Stat(path); !errors.Is(err, fs.ErrNotExist) {
+		t.Errorf("Remove() left the file, stat error = %v", err)
+	}
+}
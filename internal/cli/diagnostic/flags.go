@@ -22,6 +22,7 @@ func MustInstallFlags(fs *pflag.FlagSet) {
 	}
 	fs.String("output-format", string(defaultFormat), "The format to use for printing diagnostic messages")
 	fs.Bool("debug", defaultDebug, "Print debug information")
+	fs.Bool("summary", false, "Print a summary of problems by rule and severity after linting")
 }
 
 // ReporterFromFlags returns the printer based on the flags.
@@ -48,5 +49,16 @@ func ReporterFromFlagsWithWriter(fs *pflag.FlagSet, w io.Writer) (*Reporter, err
 		return nil, err
 	}
 
-	return format.Reporter(w).ShowDebug(debug), nil
+	reporter := format.Reporter(w).ShowDebug(debug)
+
+	// GitHub Actions provides a job summary file that accepts markdown, which
+	// is only written to when a summary is requested.
+	summary, err := fs.GetBool("summary")
+	if err != nil {
+		return nil, err
+	}
+	if summary {
+		reporter.WithStepSummary(os.Getenv("GITHUB_STEP_SUMMARY"))
+	}
+	return reporter, nil
 }
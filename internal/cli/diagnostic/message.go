@@ -46,6 +46,16 @@ type Message struct {
 
 	// ColumnEnd is the end column number where the message originated (optional).
 	ColumnEnd int `json:"column-end,omitempty"`
+
+	// Rule is the ID of the lint rule that produced the message (optional).
+	Rule string `json:"rule,omitempty"`
+
+	// Fixable indicates whether the message can be automatically fixed (optional).
+	Fixable bool `json:"fixable,omitempty"`
+
+	// UnsafeFix indicates whether the fix of the message may break references,
+	// and is only applied when unsafe fixes are enabled (optional).
+	UnsafeFix bool `json:"unsafe-fix,omitempty"`
 }
 
 // Errorf creates a new error message with the given severity and body.
@@ -137,6 +147,29 @@ func ColumnRange(column, columnEnd int) Attachment {
 	})
 }
 
+// Rule returns an attachment that sets the rule ID of the message.
+func Rule(id string) Attachment {
+	return messageOption(func(m *Message) {
+		m.Rule = id
+	})
+}
+
+// Fixable returns an attachment that sets whether the message can be
+// automatically fixed.
+func Fixable(fixable bool) Attachment {
+	return messageOption(func(m *Message) {
+		m.Fixable = fixable
+	})
+}
+
+// UnsafeFix returns an attachment that sets whether the fix of the message is
+// unsafe.
+func UnsafeFix(unsafe bool) Attachment {
+	return messageOption(func(m *Message) {
+		m.UnsafeFix = unsafe
+	})
+}
+
 type messageOption func(*Message)
 
 func (o messageOption) set(m *Message) {
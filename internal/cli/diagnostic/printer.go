@@ -59,6 +59,16 @@ func (p *GitHubPrinter) Print(message *Message) {
 	)
 }
 
+// PrintSummary prints a single notice with the totals of the summary. The full
+// table is written to the job summary by the [Reporter] instead.
+func (p *GitHubPrinter) PrintSummary(summary *Summary) {
+	out := writerOrDefault(p.W)
+	_, _ = fmt.Fprintf(out, "::notice title=FSH Lint Summary::%d problem(s) in %d file(s), %d fixable, %d fixed\n",
+		summary.Total(), summary.Files, summary.Fixable, summary.Fixed)
+}
+
+var _ SummaryPrinter = (*GitHubPrinter)(nil)
+
 func writerOrDefault(w io.Writer) io.Writer {
 	if w == nil {
 		return os.Stdout
@@ -103,16 +113,29 @@ type JSONPrinter struct {
 
 // Print prints the given message in JSON format.
 func (p *JSONPrinter) Print(message *Message) {
+	p.print(message)
+}
+
+// PrintSummary prints the summary as a JSON object under the "summary" key.
+func (p *JSONPrinter) PrintSummary(summary *Summary) {
+	p.print(struct {
+		Summary *Summary `json:"summary"`
+	}{summary})
+}
+
+func (p *JSONPrinter) print(v any) {
 	out := writerOrDefault(p.W)
 	var data []byte
 	if p.Indent {
-		data, _ = json.MarshalIndent(message, "", "  ")
+		data, _ = json.MarshalIndent(v, "", "  ")
 	} else {
-		data, _ = json.Marshal(message)
+		data, _ = json.Marshal(v)
 	}
 	_, _ = fmt.Fprintf(out, "%s\n", string(data))
 }
 
+var _ SummaryPrinter = (*JSONPrinter)(nil)
+
 // TextPrinter prints diagnostic messages in plain text format.
 // This will ignore any source-location information from the message and only
 // print the severity and body.
@@ -132,7 +155,15 @@ func (p *TextPrinter) Print(message *Message) {
 	_, _ = fmt.Fprintln(out, sb.String())
 }
 
+// PrintSummary prints the summary as a plain text table.
+func (p *TextPrinter) PrintSummary(summary *Summary) {
+	out := writerOrDefault(p.W)
+	_, _ = fmt.Fprintln(out)
+	WriteSummaryTable(out, summary)
+}
+
 var _ Printer = (*TextPrinter)(nil)
+var _ SummaryPrinter = (*TextPrinter)(nil)
 
 // ANSIPrinter prints diagnostic messages in plain text format with ANSI color
 // codes, if the underlying writer is a terminal. If it is not a terminal, this
@@ -208,3 +239,4 @@ func indent(sb *strings.Builder, prefix, text string) {
 }
 
 var _ Printer = (*ANSIPrinter)(nil)
+var _ SummaryPrinter = (*ANSIPrinter)(nil)
@@ -1,15 +1,35 @@
 package diagnostic
 
-import "os"
-
-// Reporter represents an emitter that can emit diagnostic messages.
+import (
+	"os"
+	"sort"
+	"sync"
+)
+
+// Reporter represents an emitter that can emit diagnostic messages. It is safe
+// for concurrent use.
 type Reporter struct {
+	// mu guards the counts and held messages, and serializes printing.
+	mu sync.Mutex
+
 	printer Printer
 
 	// count of each emitted message type
 	errors, warnings, notices, debug int
 
+	// counts of emitted, fixable, and fixed messages by rule, file, and severity
+	counts, fixable, fixed map[countKey]int
+
 	enableDebug bool
+
+	// stepSummaryPath is the path of the GitHub job summary file to append the
+	// markdown summary to, if set.
+	stepSummaryPath string
+
+	// sorted indicates that messages are held until Flush, and then printed
+	// sorted by file and position.
+	sorted bool
+	held   []*Message
 }
 
 // NewReporter creates a new reporter with the given printer.
@@ -23,8 +43,45 @@ func (r *Reporter) ShowDebug(enable bool) *Reporter {
 	return r
 }
 
+// WithStepSummary sets the path of the GitHub job summary file that
+// PrintSummary appends a markdown summary to. An empty path disables it.
+func (r *Reporter) WithStepSummary(path string) *Reporter {
+	r.stepSummaryPath = path
+	return r
+}
+
+// WithSortedOutput sets whether messages are held until Flush is called, and
+// then printed sorted by file and position, so that the output does not depend
+// on the order in which files are linted.
+func (r *Reporter) WithSortedOutput(enable bool) *Reporter {
+	r.sorted = enable
+	return r
+}
+
+// Reset clears the counts and held messages, to reuse the reporter for another
+// run, such as in watch mode.
+func (r *Reporter) Reset() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	r.errors, r.warnings, r.notices, r.debug = 0, 0, 0, 0
+	r.counts, r.fixable, r.fixed = nil, nil, nil
+	r.held = nil
+}
+
 // Report emits the given message.
 func (r *Reporter) Report(message *Message) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	if message.Severity != SeverityDebug {
+		key := countKey{rule: message.Rule, file: message.File, severity: message.Severity}
+		increment(&r.counts, key)
+		if message.Fixable {
+			increment(&r.fixable, key)
+		}
+	}
+
 	switch message.Severity {
 	case SeverityError:
 		r.errors++
@@ -38,20 +95,59 @@ func (r *Reporter) Report(message *Message) {
 			return
 		}
 	}
+	if r.sorted {
+		r.held = append(r.held, message)
+		return
+	}
 	r.getPrinter().Print(message)
 }
 
+// Flush prints the messages held by a reporter with sorted output, sorted by
+// file, line, column, rule, and body.
+func (r *Reporter) Flush() {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+
+	held := r.held
+	r.held = nil
+	sort.SliceStable(held, func(i, j int) bool {
+		return lessMessage(held[i], held[j])
+	})
+	for _, message := range held {
+		r.getPrinter().Print(message)
+	}
+}
+
+// lessMessage returns true if a is sorted before b.
+func lessMessage(a, b *Message) bool {
+	if a.File != b.File {
+		return a.File < b.File
+	}
+	if a.Line != b.Line {
+		return a.Line < b.Line
+	}
+	if a.Column != b.Column {
+		return a.Column < b.Column
+	}
+	if a.Rule != b.Rule {
+		return a.Rule < b.Rule
+	}
+	return a.Body < b.Body
+}
+
 // ReportFatal emits a fatal error and exits the program.
 func (r *Reporter) ReportFatal(message *Message) {
 	msg := *message
 	msg.Severity = SeverityError
 	r.Report(&msg)
+	r.Flush()
 	os.Exit(1)
 }
 
 // Fatalf emits a fatal error and exits the program.
 func (r *Reporter) Fatalf(format string, args ...any) {
 	r.Report(Errorf(format, args...))
+	r.Flush()
 	os.Exit(1)
 }
 
@@ -75,6 +171,45 @@ func (r *Reporter) Debugf(format string, args ...any) {
 	r.Report(Debugf(format, args...))
 }
 
+// Fixed records that a problem reported for the given rule in the given file
+// was automatically fixed.
+func (r *Reporter) Fixed(rule, file string) {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	increment(&r.fixed, countKey{rule: rule, file: file})
+}
+
+// Summary returns the aggregate of all the messages reported so far.
+func (r *Reporter) Summary() *Summary {
+	r.mu.Lock()
+	defer r.mu.Unlock()
+	return summarize(r.counts, r.fixable, r.fixed)
+}
+
+// PrintSummary prints the summary of all the messages reported so far, if the
+// printer supports it, after any held messages. If a GitHub job summary path is
+// set, a markdown summary is also appended to it.
+func (r *Reporter) PrintSummary() {
+	r.Flush()
+	summary := r.Summary()
+	if p, ok := r.getPrinter().(SummaryPrinter); ok {
+		p.PrintSummary(summary)
+	}
+	if r.stepSummaryPath != "" {
+		if err := appendStepSummary(r.stepSummaryPath, summary); err != nil {
+			r.Warningf("Unable to write job summary to %s: %v", r.stepSummaryPath, err)
+			r.Flush()
+		}
+	}
+}
+
+func increment(m *map[countKey]int, key countKey) {
+	if *m == nil {
+		*m = make(map[countKey]int)
+	}
+	(*m)[key]++
+}
+
 func (r *Reporter) getPrinter() Printer {
 	if r.printer == nil {
 		return DefaultPrinter
@@ -84,15 +219,21 @@ func (r *Reporter) getPrinter() Printer {
 
 // ErrorCount returns the number of errors emitted.
 func (r *Reporter) ErrorCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	return r.errors
 }
 
 // WarningCount returns the number of warnings emitted.
 func (r *Reporter) WarningCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	return r.warnings
 }
 
 // NoticeCount returns the number of notices emitted.
 func (r *Reporter) NoticeCount() int {
+	r.mu.Lock()
+	defer r.mu.Unlock()
 	return r.notices
 }
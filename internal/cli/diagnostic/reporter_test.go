@@ -0,0 +1,65 @@
+package diagnostic_test
+
+import (
+	"fmt"
+	"sync"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic"
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic/diagnostictest"
+)
+
+func TestReporter_WithSortedOutput(t *testing.T) {
+	reporter, printer := diagnostictest.NewFakeReporter()
+	reporter.WithSortedOutput(true)
+
+	reporter.Report(diagnostic.Noticef("b3").With(diagnostic.File("B.fsh"), diagnostic.Line(3)))
+	reporter.Report(diagnostic.Noticef("a10").With(diagnostic.File("A.fsh"), diagnostic.Line(10)))
+	reporter.Report(diagnostic.Errorf("general"))
+	reporter.Report(diagnostic.Noticef("a2-col4").With(diagnostic.File("A.fsh"), diagnostic.Line(2), diagnostic.Column(4)))
+	reporter.Report(diagnostic.Noticef("a2-col1").With(diagnostic.File("A.fsh"), diagnostic.Line(2), diagnostic.Column(1)))
+
+	if len(printer.Messages) != 0 {
+		t.Fatalf("Reporter.Report() with sorted output: got %d printed messages before Flush, want 0", len(printer.Messages))
+	}
+
+	reporter.Flush()
+
+	var got []string
+	for _, message := range printer.Messages {
+		got = append(got, message.Body)
+	}
+	want := []string{"general", "a2-col1", "a2-col4", "a10", "b3"}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Reporter.Flush() order mismatch (-got +want):\n%s", diff)
+	}
+	if got, want := reporter.ErrorCount(), 1; got != want {
+		t.Errorf("Reporter.ErrorCount(): got %d, want %d", got, want)
+	}
+}
+
+func TestReporter_ConcurrentReport(t *testing.T) {
+	reporter, printer := diagnostictest.NewFakeReporter()
+
+	var wg sync.WaitGroup
+	for i := range 50 {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			file := diagnostic.File(fmt.Sprintf("%d.fsh", i))
+			reporter.Report(diagnostic.Errorf("error").With(file, diagnostic.Rule("rule-a")))
+			reporter.Report(diagnostic.Noticef("notice").With(file, diagnostic.Rule("rule-b"), diagnostic.Fixable(true)))
+			reporter.Fixed("rule-b", fmt.Sprintf("%d.fsh", i))
+		}()
+	}
+	wg.Wait()
+
+	if got, want := len(printer.Messages), 100; got != want {
+		t.Errorf("Reporter.Report(): got %d printed messages, want %d", got, want)
+	}
+	summary := reporter.Summary()
+	if summary.Errors != 50 || summary.Notices != 50 || summary.Files != 50 || summary.Fixed != 50 {
+		t.Errorf("Reporter.Summary(): got %+v, want 50 errors, notices, files, and fixed", summary)
+	}
+}
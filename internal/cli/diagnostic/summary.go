@@ -0,0 +1,188 @@
+package diagnostic
+
+import (
+	"fmt"
+	"io"
+	"os"
+	"sort"
+	"strings"
+	"text/tabwriter"
+)
+
+// Summary is an aggregate of all the messages emitted by a [Reporter], grouped
+// by rule ID.
+type Summary struct {
+	// Rules contains the per-rule counts, sorted by rule ID.
+	Rules []*RuleSummary `json:"rules"`
+
+	// Files is the number of distinct files that had at least one message.
+	Files int `json:"files"`
+
+	// Errors is the total number of error messages.
+	Errors int `json:"errors"`
+
+	// Warnings is the total number of warning messages.
+	Warnings int `json:"warnings"`
+
+	// Notices is the total number of notice messages.
+	Notices int `json:"notices"`
+
+	// Fixable is the total number of messages that could be automatically fixed.
+	Fixable int `json:"fixable"`
+
+	// Fixed is the total number of messages that were automatically fixed.
+	Fixed int `json:"fixed"`
+}
+
+// RuleSummary holds the counts of messages emitted for a single rule.
+type RuleSummary struct {
+	// Rule is the rule ID the counts belong to.
+	Rule string `json:"rule"`
+
+	// Files is the number of distinct files with a message from this rule.
+	Files int `json:"files"`
+
+	// Errors is the number of error messages from this rule.
+	Errors int `json:"errors"`
+
+	// Warnings is the number of warning messages from this rule.
+	Warnings int `json:"warnings"`
+
+	// Notices is the number of notice messages from this rule.
+	Notices int `json:"notices"`
+
+	// Fixable is the number of messages from this rule that could be
+	// automatically fixed.
+	Fixable int `json:"fixable"`
+
+	// Fixed is the number of messages from this rule that were automatically
+	// fixed.
+	Fixed int `json:"fixed"`
+}
+
+// Total returns the total number of problems in the summary.
+func (s *Summary) Total() int {
+	return s.Errors + s.Warnings + s.Notices
+}
+
+// SummaryPrinter is an optional interface that a [Printer] can implement to
+// print the end-of-run summary.
+type SummaryPrinter interface {
+	// PrintSummary prints the given summary.
+	PrintSummary(summary *Summary)
+}
+
+// countKey is the key used by the [Reporter] to count messages.
+type countKey struct {
+	rule, file string
+	severity   Severity
+}
+
+// summarize builds a summary from the given message, fixable, and fixed counts.
+func summarize(counts, fixable, fixed map[countKey]int) *Summary {
+	summary := &Summary{}
+	rules := make(map[string]*RuleSummary)
+	ruleFiles := make(map[string]map[string]struct{})
+	files := make(map[string]struct{})
+
+	ruleFor := func(id string) *RuleSummary {
+		rs, ok := rules[id]
+		if !ok {
+			rs = &RuleSummary{Rule: id}
+			rules[id] = rs
+			ruleFiles[id] = make(map[string]struct{})
+		}
+		return rs
+	}
+
+	for key, n := range counts {
+		rs := ruleFor(key.rule)
+		switch key.severity {
+		case SeverityError:
+			rs.Errors += n
+			summary.Errors += n
+		case SeverityWarning:
+			rs.Warnings += n
+			summary.Warnings += n
+		case SeverityNotice:
+			rs.Notices += n
+			summary.Notices += n
+		}
+		if key.file != "" {
+			ruleFiles[key.rule][key.file] = struct{}{}
+			files[key.file] = struct{}{}
+		}
+	}
+	for key, n := range fixable {
+		ruleFor(key.rule).Fixable += n
+		summary.Fixable += n
+	}
+	for key, n := range fixed {
+		ruleFor(key.rule).Fixed += n
+		summary.Fixed += n
+	}
+
+	for id, rs := range rules {
+		rs.Files = len(ruleFiles[id])
+		summary.Rules = append(summary.Rules, rs)
+	}
+	sort.Slice(summary.Rules, func(i, j int) bool {
+		return summary.Rules[i].Rule < summary.Rules[j].Rule
+	})
+	summary.Files = len(files)
+	return summary
+}
+
+// WriteSummaryTable writes the summary as a plain-text table to w.
+func WriteSummaryTable(w io.Writer, summary *Summary) {
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	_, _ = fmt.Fprintln(tw, "Rule\tFiles\tErrors\tWarnings\tNotices\tFixable\tFixed")
+	for _, rs := range summary.Rules {
+		_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%d\n",
+			ruleName(rs.Rule), rs.Files, rs.Errors, rs.Warnings, rs.Notices, rs.Fixable, rs.Fixed)
+	}
+	_, _ = fmt.Fprintf(tw, "%s\t%d\t%d\t%d\t%d\t%d\t%d\n",
+		"Total", summary.Files, summary.Errors, summary.Warnings, summary.Notices, summary.Fixable, summary.Fixed)
+	_ = tw.Flush()
+	_, _ = fmt.Fprintf(w, "%d problem(s) in %d file(s), %d fixable, %d fixed\n",
+		summary.Total(), summary.Files, summary.Fixable, summary.Fixed)
+}
+
+// WriteSummaryMarkdown writes the summary as a GitHub-flavored markdown table
+// to w, in the form expected by GitHub Actions job summaries.
+// See: https://docs.github.com/en/actions/writing-workflows/choosing-what-your-workflow-does/workflow-commands-for-github-actions#adding-a-job-summary
+func WriteSummaryMarkdown(w io.Writer, summary *Summary) {
+	var sb strings.Builder
+	sb.WriteString("## FSH Lint Summary\n\n")
+	_, _ = fmt.Fprintf(&sb, "%d problem(s) in %d file(s), %d fixable, %d fixed.\n\n",
+		summary.Total(), summary.Files, summary.Fixable, summary.Fixed)
+	sb.WriteString("| Rule | Files | Errors | Warnings | Notices | Fixable | Fixed |\n")
+	sb.WriteString("|:-----|------:|-------:|---------:|--------:|--------:|------:|\n")
+	for _, rs := range summary.Rules {
+		_, _ = fmt.Fprintf(&sb, "| `%s` | %d | %d | %d | %d | %d | %d |\n",
+			ruleName(rs.Rule), rs.Files, rs.Errors, rs.Warnings, rs.Notices, rs.Fixable, rs.Fixed)
+	}
+	_, _ = fmt.Fprintf(&sb, "| **Total** | %d | %d | %d | %d | %d | %d |\n",
+		summary.Files, summary.Errors, summary.Warnings, summary.Notices, summary.Fixable, summary.Fixed)
+	_, _ = io.WriteString(w, sb.String())
+}
+
+// appendStepSummary appends the markdown summary to the GitHub job summary
+// file at path.
+func appendStepSummary(path string, summary *Summary) error {
+	f, err := os.OpenFile(path, os.O_APPEND|os.O_CREATE|os.O_WRONLY, 0644)
+	if err != nil {
+		return err
+	}
+	WriteSummaryMarkdown(f, summary)
+	return f.Close()
+}
+
+// ruleName returns the display name for a rule ID in a summary. Messages that
+// are not attributed to any rule (e.g. parse errors) are grouped under "other".
+func ruleName(id string) string {
+	if id == "" {
+		return "other"
+	}
+	return id
+}
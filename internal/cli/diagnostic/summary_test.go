@@ -0,0 +1,88 @@
+package diagnostic_test
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic"
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic/diagnostictest"
+)
+
+func TestReporter_Summary(t *testing.T) {
+	reporter, _ := diagnostictest.NewFakeReporter()
+	reporter.Report(diagnostic.Noticef("a").With(diagnostic.File("A.fsh"), diagnostic.Rule("rule-b"), diagnostic.Fixable(true)))
+	reporter.Report(diagnostic.Noticef("b").With(diagnostic.File("B.fsh"), diagnostic.Rule("rule-b")))
+	reporter.Report(diagnostic.Warningf("c").With(diagnostic.File("A.fsh"), diagnostic.Rule("rule-a")))
+	reporter.Report(diagnostic.Errorf("d").With(diagnostic.File("C.fsh")))
+	reporter.Report(diagnostic.Debugf("ignored"))
+	reporter.Fixed("rule-b", "A.fsh")
+
+	want := &diagnostic.Summary{
+		Rules: []*diagnostic.RuleSummary{
+			{Rule: "", Files: 1, Errors: 1},
+			{Rule: "rule-a", Files: 1, Warnings: 1},
+			{Rule: "rule-b", Files: 2, Notices: 2, Fixable: 1, Fixed: 1},
+		},
+		Files:    3,
+		Errors:   1,
+		Warnings: 1,
+		Notices:  2,
+		Fixable:  1,
+		Fixed:    1,
+	}
+
+	got := reporter.Summary()
+
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Reporter.Summary() mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestReporter_PrintSummary(t *testing.T) {
+	testCases := []struct {
+		name    string
+		format  diagnostic.Format
+		wantOut string
+	}{
+		{
+			name:    "text format prints table",
+			format:  diagnostic.FormatText,
+			wantOut: "1 problem(s) in 1 file(s), 1 fixable, 0 fixed",
+		}, {
+			name:    "json format prints summary object",
+			format:  diagnostic.FormatJSON,
+			wantOut: `{"summary":{"rules":[{"rule":"rule-a","files":1,"errors":0,"warnings":0,"notices":1,"fixable":1,"fixed":0}],"files":1,"errors":0,"warnings":0,"notices":1,"fixable":1,"fixed":0}}`,
+		}, {
+			name:    "github format prints notice",
+			format:  diagnostic.FormatGitHub,
+			wantOut: "::notice title=FSH Lint Summary::1 problem(s) in 1 file(s), 1 fixable, 0 fixed",
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			var out bytes.Buffer
+			stepSummary := filepath.Join(t.TempDir(), "summary.md")
+			reporter := tc.format.Reporter(&out).WithStepSummary(stepSummary)
+			reporter.Report(diagnostic.Noticef("a").With(diagnostic.File("A.fsh"), diagnostic.Rule("rule-a"), diagnostic.Fixable(true)))
+			out.Reset()
+
+			reporter.PrintSummary()
+
+			if got := out.String(); !strings.Contains(got, tc.wantOut) {
+				t.Errorf("Reporter.PrintSummary() got output %q, want it to contain %q", got, tc.wantOut)
+			}
+			data, err := os.ReadFile(stepSummary)
+			if err != nil {
+				t.Fatalf("os.ReadFile(%q): got error %v, want nil", stepSummary, err)
+			}
+			if got, want := string(data), "| `rule-a` | 1 | 0 | 0 | 1 | 1 | 0 |"; !strings.Contains(got, want) {
+				t.Errorf("Reporter.PrintSummary() got job summary %q, want it to contain %q", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,272 @@
+// Package config contains the project configuration of the linter, read from a
+// YAML file in the project root.
+package config
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"io"
+	"os"
+	"path/filepath"
+	"regexp"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic"
+	"gopkg.in/yaml.v3"
+)
+
+// FileName is the name of the configuration file read from the project root.
+const FileName = ".fsh-lint.yaml"
+
+// RuleSetting configures a rule. It is either "off" to disable the rule, or the
+// severity to report the rule's problems with.
+type RuleSetting string
+
+// Off disables a rule.
+const Off RuleSetting = "off"
+
+// ruleIDPattern matches valid rule IDs.
+var ruleIDPattern = regexp.MustCompile(`^[a-z0-9]+(-[a-z0-9]+)*$`)
+
+// UnmarshalText unmarshals and validates the given text into a [RuleSetting].
+func (s *RuleSetting) UnmarshalText(text []byte) error {
+	switch v := RuleSetting(strings.ToLower(string(text))); v {
+	case Off, RuleSetting(diagnostic.SeverityError), RuleSetting(diagnostic.SeverityWarning), RuleSetting(diagnostic.SeverityNotice):
+		*s = v
+		return nil
+	}
+	return fmt.Errorf("invalid rule setting %q, must be one of: off, error, warning, notice", text)
+}
+
+// Severity returns the severity of the setting, or an empty severity if the
+// setting is Off.
+func (s RuleSetting) Severity() diagnostic.Severity {
+	if s == Off {
+		return ""
+	}
+	return diagnostic.Severity(s)
+}
+
+// Config is the project configuration.
+type Config struct {
+	// Exclude is a list of patterns, with .gitignore semantics, of files that
+	// should not be linted.
+	Exclude []string `yaml:"exclude"`
+
+	// Rules configures rules by rule ID for all files.
+	Rules map[string]RuleSetting `yaml:"rules"`
+
+	// Overrides configures rules for the files matching specific paths. Later
+	// overrides take precedence over earlier ones.
+	Overrides []*Override `yaml:"overrides"`
+
+	// CustomRules are rules defined in the configuration, such as the naming
+	// conventions of an organization, that run after the built-in rules.
+	CustomRules []*CustomRule `yaml:"customRules"`
+
+	// MetadataOrder configures the order checked by the entity-metadata-order
+	// rule. Optional, the canonical order is checked when nil.
+	MetadataOrder *MetadataOrder `yaml:"metadataOrder"`
+
+	// Dir is the directory that paths in the configuration are relative to.
+	// This is set to the directory of the configuration file by Load.
+	Dir string `yaml:"-"`
+
+	// Path is the path of the configuration file. This is set by Load.
+	Path string `yaml:"-"`
+}
+
+// CustomRule is a declarative rule that either checks a field of every entity
+// of a kind, or reports every element selected by a query. A field check must
+// set at least one of Required, Pattern, or Equals.
+type CustomRule struct {
+	// ID is the unique ID of the rule, in kebab-case. Required.
+	ID string `yaml:"id"`
+
+	// Query selects the elements to report, in the language of the query
+	// package, such as Profiles.ProfileRules.BindingRules[Strength == nil].
+	// It cannot be combined with a field check.
+	Query string `yaml:"query"`
+
+	// Entity is the kind of entity the rule checks, such as Profile. Required
+	// without a query.
+	Entity string `yaml:"entity"`
+
+	// Field is the dot-separated path of the checked field, using the JSON
+	// names of the ast package, such as title or profileRules.caretValueRules,
+	// or a caret path, such as ^url, for the value of a caret rule. Required
+	// without a query.
+	Field string `yaml:"field"`
+
+	// Required reports entities that do not set the field.
+	Required bool `yaml:"required"`
+
+	// Pattern is a regular expression that the values of the field must match.
+	Pattern string `yaml:"pattern"`
+
+	// Equals is the value that the values of the field must be equal to.
+	Equals string `yaml:"equals"`
+
+	// Message is the message of the problems reported by the rule. Optional, a
+	// message is built from the checks when empty.
+	Message string `yaml:"message"`
+
+	// Severity is the default severity of the problems reported by the rule.
+	// Optional, problems are reported as notices when empty.
+	Severity RuleSetting `yaml:"severity"`
+
+	// DocsURL is the URL of the documentation of the rule. Optional.
+	DocsURL string `yaml:"docsUrl"`
+}
+
+// MetadataOrder configures the order of the metadata and rules of entities.
+type MetadataOrder struct {
+	// Keywords is the order of the metadata keywords of every kind of entity,
+	// such as Parent, Id, Title, and Description. Optional, the canonical order
+	// of each kind of entity is used when empty.
+	Keywords []string `yaml:"keywords"`
+
+	// IgnoreRuleOrder disables the check that caret value rules come before
+	// the other rules.
+	IgnoreRuleOrder bool `yaml:"ignoreRuleOrder"`
+}
+
+// Override configures rules for files matching any of the paths.
+type Override struct {
+	// Paths are doublestar glob patterns, relative to the configuration file.
+	Paths []string `yaml:"paths"`
+
+	// Rules configures rules by rule ID for the matching files.
+	Rules map[string]RuleSetting `yaml:"rules"`
+}
+
+// Load reads the configuration file at the given path.
+func Load(path string) (*Config, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	cfg, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("parsing config %s: %w", path, err)
+	}
+	cfg.Dir = filepath.Dir(path)
+	cfg.Path = path
+	return cfg, nil
+}
+
+// Parse parses the given YAML configuration, validating override patterns.
+func Parse(data []byte) (*Config, error) {
+	cfg := &Config{}
+	decoder := yaml.NewDecoder(bytes.NewReader(data))
+	decoder.KnownFields(true)
+	if err := decoder.Decode(cfg); err != nil && !errors.Is(err, io.EOF) {
+		return nil, err
+	}
+
+	for _, override := range cfg.Overrides {
+		for _, pattern := range override.Paths {
+			if !doublestar.ValidatePattern(pattern) {
+				return nil, fmt.Errorf("invalid override path pattern %q", pattern)
+			}
+		}
+	}
+
+	ids := make(map[string]bool)
+	for i, rule := range cfg.CustomRules {
+		if err := rule.validate(); err != nil {
+			return nil, fmt.Errorf("invalid custom rule %d: %w", i+1, err)
+		}
+		if ids[rule.ID] {
+			return nil, fmt.Errorf("duplicate custom rule %q", rule.ID)
+		}
+		ids[rule.ID] = true
+	}
+	return cfg, nil
+}
+
+// validate returns an error if a required field of the rule is missing, or if
+// its pattern or severity is invalid. The query, entity, and field are
+// validated when the rule is built by the rules package.
+func (r *CustomRule) validate() error {
+	switch {
+	case !ruleIDPattern.MatchString(r.ID):
+		return fmt.Errorf("id %q must be kebab-case", r.ID)
+	case r.Severity == Off:
+		return fmt.Errorf("%s: severity must be one of: error, warning, notice", r.ID)
+	case r.Query != "":
+		if r.Entity != "" || r.Field != "" || r.Required || r.Pattern != "" || r.Equals != "" {
+			return fmt.Errorf("%s: query cannot be combined with entity, field, required, pattern, or equals", r.ID)
+		}
+		return nil
+	case r.Entity == "":
+		return fmt.Errorf("%s: entity is required", r.ID)
+	case r.Field == "":
+		return fmt.Errorf("%s: field is required", r.ID)
+	case !r.Required && r.Pattern == "" && r.Equals == "":
+		return fmt.Errorf("%s: one of required, pattern, or equals must be set", r.ID)
+	}
+	if _, err := regexp.Compile(r.Pattern); err != nil {
+		return fmt.Errorf("%s: invalid pattern: %w", r.ID, err)
+	}
+	return nil
+}
+
+// RuleSetting returns the setting of the rule with the given ID for the file at
+// the given path. Returns false if the rule is not configured for the path.
+func (c *Config) RuleSetting(path, ruleID string) (RuleSetting, bool) {
+	if c == nil {
+		return "", false
+	}
+
+	setting, ok := c.Rules[ruleID]
+	rel := c.relative(path)
+	for _, override := range c.Overrides {
+		s, configured := override.Rules[ruleID]
+		if !configured || !override.matches(rel) {
+			continue
+		}
+		setting, ok = s, true
+	}
+	return setting, ok
+}
+
+// matches returns true if the slash-separated path matches any of the paths of
+// the override.
+func (o *Override) matches(path string) bool {
+	for _, pattern := range o.Paths {
+		pattern = strings.TrimPrefix(pattern, "./")
+		if ok, _ := doublestar.Match(pattern, path); ok {
+			return true
+		}
+		// A pattern matching a directory applies to all files inside of it.
+		if ok, _ := doublestar.Match(strings.TrimSuffix(pattern, "/")+"/**", path); ok {
+			return true
+		}
+	}
+	return false
+}
+
+// relative returns path as a slash-separated path relative to the directory of
+// the configuration.
+func (c *Config) relative(path string) string {
+	dir := c.Dir
+	if dir == "" {
+		dir = "."
+	}
+	if filepath.IsAbs(path) != filepath.IsAbs(dir) {
+		if abs, err := filepath.Abs(path); err == nil {
+			path = abs
+		}
+		if abs, err := filepath.Abs(dir); err == nil {
+			dir = abs
+		}
+	}
+	if rel, err := filepath.Rel(dir, path); err == nil {
+		path = rel
+	}
+	return filepath.ToSlash(path)
+}
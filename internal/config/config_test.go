@@ -0,0 +1,233 @@
+package config_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/config"
+)
+
+func TestParse(t *testing.T) {
+	testCases := []struct {
+		name    string
+		input   string
+		want    *config.Config
+		wantErr bool
+	}{
+		{
+			name:  "empty config",
+			input: "",
+			want:  &config.Config{},
+		},
+		{
+			name: "full config",
+			input: `
+exclude:
+  - "**/*.generated.fsh"
+rules:
+  profile-name-format: off
+  profile-assignment-present: Error
+overrides:
+  - paths: ["examples/**"]
+    rules:
+      profile-name-matches-filename: off
+`,
+			want: &config.Config{
+				Exclude: []string{"**/*.generated.fsh"},
+				Rules: map[string]config.RuleSetting{
+					"profile-name-format":        config.Off,
+					"profile-assignment-present": "error",
+				},
+				Overrides: []*config.Override{{
+					Paths: []string{"examples/**"},
+					Rules: map[string]config.RuleSetting{
+						"profile-name-matches-filename": config.Off,
+					},
+				}},
+			},
+		},
+		{
+			name:  "metadata order",
+			input: "metadataOrder:\n  keywords: [Parent, Title, Id]\n  ignoreRuleOrder: true\n",
+			want: &config.Config{
+				MetadataOrder: &config.MetadataOrder{Keywords: []string{"Parent", "Title", "Id"}, IgnoreRuleOrder: true},
+			},
+		},
+		{
+			name:    "invalid rule setting",
+			input:   "rules:\n  profile-name-format: fatal\n",
+			wantErr: true,
+		},
+		{
+			name:    "unknown field",
+			input:   "rule:\n  profile-name-format: off\n",
+			wantErr: true,
+		},
+		{
+			name:    "invalid override pattern",
+			input:   "overrides:\n  - paths: [\"examples/[\"]\n",
+			wantErr: true,
+		},
+		{
+			name: "custom rules",
+			input: `
+customRules:
+  - id: verily-profile-prefix
+    entity: Profile
+    field: name
+    pattern: "^Verily"
+    message: Profile names must start with Verily.
+    severity: Warning
+  - id: profile-url-required
+    entity: Profile
+    field: ^url
+    required: true
+  - id: binding-strength-required
+    query: Profiles.ProfileRules.BindingRules[Strength == nil]
+`,
+			want: &config.Config{
+				CustomRules: []*config.CustomRule{
+					{
+						ID:       "verily-profile-prefix",
+						Entity:   "Profile",
+						Field:    "name",
+						Pattern:  "^Verily",
+						Message:  "Profile names must start with Verily.",
+						Severity: "warning",
+					},
+					{
+						ID:       "profile-url-required",
+						Entity:   "Profile",
+						Field:    "^url",
+						Required: true,
+					},
+					{
+						ID:    "binding-strength-required",
+						Query: "Profiles.ProfileRules.BindingRules[Strength == nil]",
+					},
+				},
+			},
+		},
+		{
+			name:    "custom rule without checks",
+			input:   "customRules:\n  - {id: no-checks, entity: Profile, field: name}\n",
+			wantErr: true,
+		},
+		{
+			name:    "custom rule with query and field",
+			input:   "customRules:\n  - {id: both, query: Profiles, entity: Profile, field: name, required: true}\n",
+			wantErr: true,
+		},
+		{
+			name:    "custom rule with invalid id",
+			input:   "customRules:\n  - {id: NoChecks, entity: Profile, field: name, required: true}\n",
+			wantErr: true,
+		},
+		{
+			name:    "custom rule with invalid pattern",
+			input:   "customRules:\n  - {id: bad-pattern, entity: Profile, field: name, pattern: \"[\"}\n",
+			wantErr: true,
+		},
+		{
+			name:    "custom rule turned off",
+			input:   "customRules:\n  - {id: off-rule, entity: Profile, field: name, required: true, severity: off}\n",
+			wantErr: true,
+		},
+		{
+			name:    "duplicate custom rules",
+			input:   "customRules:\n  - {id: dup, entity: Profile, field: name, required: true}\n  - {id: dup, entity: Profile, field: title, required: true}\n",
+			wantErr: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, err := config.Parse([]byte(tc.input))
+
+			if (err != nil) != tc.wantErr {
+				t.Fatalf("Parse() error = %v, wantErr %t", err, tc.wantErr)
+			}
+			if diff := cmp.Diff(tc.want, got); diff != "" {
+				t.Errorf("Parse() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestConfig_RuleSetting(t *testing.T) {
+	cfg := &config.Config{
+		Rules: map[string]config.RuleSetting{
+			"rule-a": config.Off,
+			"rule-b": "warning",
+		},
+		Overrides: []*config.Override{
+			{
+				Paths: []string{"examples"},
+				Rules: map[string]config.RuleSetting{"rule-a": "error"},
+			},
+			{
+				Paths: []string{"examples/legacy/*.fsh"},
+				Rules: map[string]config.RuleSetting{"rule-a": config.Off},
+			},
+		},
+		Dir: "project",
+	}
+
+	testCases := []struct {
+		name    string
+		path    string
+		ruleID  string
+		want    config.RuleSetting
+		wantSet bool
+	}{
+		{
+			name:    "global setting",
+			path:    "project/input/Profile.fsh",
+			ruleID:  "rule-b",
+			want:    "warning",
+			wantSet: true,
+		},
+		{
+			name:    "unconfigured rule",
+			path:    "project/input/Profile.fsh",
+			ruleID:  "rule-c",
+			wantSet: false,
+		},
+		{
+			name:    "directory override",
+			path:    "project/examples/Example.fsh",
+			ruleID:  "rule-a",
+			want:    "error",
+			wantSet: true,
+		},
+		{
+			name:    "later override takes precedence",
+			path:    "project/examples/legacy/Example.fsh",
+			ruleID:  "rule-a",
+			want:    config.Off,
+			wantSet: true,
+		},
+		{
+			name:    "override paths are relative to the config directory",
+			path:    "examples/Example.fsh",
+			ruleID:  "rule-a",
+			want:    config.Off,
+			wantSet: true,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got, ok := cfg.RuleSetting(tc.path, tc.ruleID)
+
+			if got != tc.want || ok != tc.wantSet {
+				t.Errorf("RuleSetting(%q, %q) = (%q, %t), want (%q, %t)", tc.path, tc.ruleID, got, ok, tc.want, tc.wantSet)
+			}
+		})
+	}
+
+	var nilConfig *config.Config
+	if _, ok := nilConfig.RuleSetting("Profile.fsh", "rule-a"); ok {
+		t.Errorf("RuleSetting() on nil config = true, want false")
+	}
+}
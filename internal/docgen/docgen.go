@@ -0,0 +1,178 @@
+// Package docgen generates the rule documentation from the metadata of the
+// registered lint rules, so that the documentation cannot drift from the code.
+package docgen
+
+import (
+	"bytes"
+	"fmt"
+	"sort"
+	"strings"
+
+	"github.com/verily-src/fsh-lint/lint"
+)
+
+const (
+	// BeginMarker marks the start of the generated rule list in the README.
+	BeginMarker = "<!-- BEGIN GENERATED RULES -->"
+
+	// EndMarker marks the end of the generated rule list in the README.
+	EndMarker = "<!-- END GENERATED RULES -->"
+)
+
+// UniqueRules returns the given rules with duplicate IDs removed, keeping the
+// first occurrence, sorted by ID. Rules such as required-field-present are
+// registered several times with different configurations but documented once.
+func UniqueRules(rules []lint.Rule) []lint.Rule {
+	seen := make(map[string]struct{})
+	var result []lint.Rule
+	for _, rule := range rules {
+		if _, ok := seen[rule.ID()]; ok {
+			continue
+		}
+		seen[rule.ID()] = struct{}{}
+		result = append(result, rule)
+	}
+	sort.Slice(result, func(i, j int) bool {
+		return result[i].ID() < result[j].ID()
+	})
+	return result
+}
+
+// RulesMarkdown returns the full rules documentation in markdown.
+func RulesMarkdown(rules []lint.Rule) []byte {
+	var buf bytes.Buffer
+	buf.WriteString("<!-- Code generated by `go generate`; DO NOT EDIT. -->\n\n")
+	buf.WriteString("# Rules\n")
+	for _, rule := range UniqueRules(rules) {
+		writeRule(&buf, rule)
+	}
+	return buf.Bytes()
+}
+
+// writeRule writes the documentation section of a single rule.
+func writeRule(buf *bytes.Buffer, rule lint.Rule) {
+	md := lint.MetadataOf(rule)
+
+	fmt.Fprintf(buf, "\n## %s\n", rule.ID())
+
+	fmt.Fprintf(buf, "\n### Description\n\n%s\n", md.Description)
+
+	if len(md.Examples) > 0 {
+		buf.WriteString("\n### Examples\n")
+		for _, example := range md.Examples {
+			verdict := "Incorrect"
+			if example.Good {
+				verdict = "Correct"
+			}
+			if example.Description != "" {
+				fmt.Fprintf(buf, "\n%s: %s\n", verdict, example.Description)
+			} else {
+				fmt.Fprintf(buf, "\n%s:\n", verdict)
+			}
+			fmt.Fprintf(buf, "\n```fsh\n%s\n```\n", strings.TrimSpace(example.FSH))
+		}
+	}
+
+	buf.WriteString("\n### Scope\n\n")
+	buf.WriteString(scope(md.EntityKinds))
+	buf.WriteString("\n")
+
+	buf.WriteString("\n### Details\n\n")
+	fmt.Fprintf(buf, "- Category: %s\n", md.Category)
+	fmt.Fprintf(buf, "- Default severity: %s\n", md.Severity)
+	if md.OptIn {
+		buf.WriteString("- Enabled by default: no, it runs when its severity is set in the configuration\n")
+	}
+	fixable := yesNo(md.Fixable)
+	if md.Fixable && md.UnsafeFix {
+		fixable += " (some fixes are unsafe, and only applied with `--fix-unsafe`)"
+	}
+	fmt.Fprintf(buf, "- Automatically fixable: %s\n", fixable)
+
+	if len(md.Resources) > 0 {
+		buf.WriteString("\n### Resources\n\n")
+		for _, resource := range md.Resources {
+			fmt.Fprintf(buf, "- %s\n", resource)
+		}
+	}
+}
+
+// scope returns a sentence describing which entities a rule applies to.
+func scope(kinds []lint.EntityKind) string {
+	if len(kinds) == 0 || len(kinds) == len(lint.EntityKinds) {
+		return "This rule can run against all entity kinds."
+	}
+	var names []string
+	for _, kind := range kinds {
+		names = append(names, pluralName(kind))
+	}
+	if len(names) == 1 {
+		return fmt.Sprintf("This rule applies to all %s.", names[0])
+	}
+	return fmt.Sprintf("This rule applies to all %s and %s.",
+		strings.Join(names[:len(names)-1], ", "), names[len(names)-1])
+}
+
+// pluralName returns the plural, human readable name of the entity kind.
+func pluralName(kind lint.EntityKind) string {
+	switch kind {
+	case lint.EntityCodeSystem:
+		return "code systems"
+	case lint.EntityValueSet:
+		return "value sets"
+	default:
+		return strings.ToLower(string(kind)) + "s"
+	}
+}
+
+func yesNo(b bool) string {
+	if b {
+		return "yes"
+	}
+	return "no"
+}
+
+// RuleList returns the markdown list of all rule IDs grouped by category, each
+// linking to its section in docsPath.
+func RuleList(rules []lint.Rule, docsPath string) []byte {
+	byCategory := make(map[lint.Category][]lint.Rule)
+	for _, rule := range UniqueRules(rules) {
+		category := lint.MetadataOf(rule).Category
+		byCategory[category] = append(byCategory[category], rule)
+	}
+
+	var buf bytes.Buffer
+	for _, category := range lint.Categories {
+		rules := byCategory[category]
+		if len(rules) == 0 {
+			continue
+		}
+		fmt.Fprintf(&buf, "\n### %s Rules\n\n", category)
+		for _, rule := range rules {
+			optIn := ""
+			if lint.MetadataOf(rule).OptIn {
+				optIn = " (opt-in)"
+			}
+			fmt.Fprintf(&buf, "* [%s](%s#%s)%s\n", rule.ID(), docsPath, rule.ID(), optIn)
+		}
+	}
+	return buf.Bytes()
+}
+
+// ReplaceSection replaces the content between BeginMarker and EndMarker in doc
+// with the given section. An error is returned if the markers are not found.
+func ReplaceSection(doc, section []byte) ([]byte, error) {
+	begin := bytes.Index(doc, []byte(BeginMarker))
+	end := bytes.Index(doc, []byte(EndMarker))
+	if begin < 0 || end < 0 || end < begin {
+		return nil, fmt.Errorf("generated section markers %q and %q not found", BeginMarker, EndMarker)
+	}
+
+	var buf bytes.Buffer
+	buf.Write(doc[:begin+len(BeginMarker)])
+	buf.WriteString("\n")
+	buf.Write(section)
+	buf.WriteString("\n")
+	buf.Write(doc[end:])
+	return buf.Bytes(), nil
+}
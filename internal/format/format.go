@@ -0,0 +1,462 @@
+// Package format prints FSH source in a canonical style, while preserving its
+// comments.
+//
+// The canonical style:
+//   - puts the metadata of each entity right after its declaration, in the
+//     order Parent or InstanceOf, Id, Title, Description, and then the others,
+//   - separates tokens on a line with a single space, including after "*" and
+//     around "=", and removes trailing whitespace,
+//   - aligns the displays and definitions of consecutive concept lines,
+//   - separates entities with a single blank line, and collapses other runs of
+//     blank lines.
+//
+// Indentation of rules is preserved, since it is significant in FSH, as are
+// line breaks inside multi-line strings, comments, and lists.
+package format
+
+import (
+	"cmp"
+	"errors"
+	"fmt"
+	"slices"
+	"strings"
+	"unicode/utf8"
+
+	"github.com/verily-src/fsh-lint/internal/fsh"
+)
+
+// ErrChangedContent is returned by Source if formatting would change more than
+// the layout of the source, which is a bug in the formatter.
+var ErrChangedContent = errors.New("formatting changed the content of the FSH")
+
+// Source formats the FSH source in the canonical style. Returns an error if the
+// source has syntax errors.
+func Source(data []byte) ([]byte, error) {
+	src := string(data)
+	if _, err := fsh.Parse(src); err != nil {
+		return nil, err
+	}
+	crlf := strings.Count(src, "\r\n")
+	if crlf > 0 && crlf == strings.Count(src, "\n") {
+		src = strings.ReplaceAll(src, "\r\n", "\n")
+	}
+
+	formatted := strings.Join(formatLines(splitLines(pieces(fsh.Tokens(src)))), "\n")
+	if formatted != "" {
+		formatted += "\n"
+	}
+
+	if _, err := fsh.Parse(formatted); err != nil {
+		return nil, fmt.Errorf("%w: %w", ErrChangedContent, err)
+	}
+	if !slices.Equal(fingerprint(src), fingerprint(formatted)) {
+		return nil, ErrChangedContent
+	}
+	if crlf > 0 && crlf == strings.Count(string(data), "\n") {
+		formatted = strings.ReplaceAll(formatted, "\n", "\r\n")
+	}
+	return []byte(formatted), nil
+}
+
+// Kinds of pieces that are not token kinds.
+const (
+	whitespace = "WHITESPACE"
+	newline    = "NEWLINE"
+)
+
+// metadataRanks are the positions of metadata keywords in the canonical order.
+// Other keywords come after them, in their original order.
+var metadataRanks = map[string]int{
+	"Parent":      0,
+	"InstanceOf":  0,
+	"Id":          1,
+	"Title":       2,
+	"Description": 3,
+}
+
+// piece is a token of a line, or a run of whitespace between tokens.
+type piece struct {
+	kind string
+	text string
+
+	// column is the 0-based column that a whitespace piece pads the next piece
+	// to, if it is greater than zero.
+	column int
+}
+
+// pieces splits the tokens into pieces. The whitespace in the "*" of rules and
+// before the names of rule sets is split into separate pieces, and line endings
+// become newline pieces, except inside lists.
+func pieces(tokens []*fsh.Token) []*piece {
+	var result []*piece
+	add := func(kind, text string) {
+		if kind == whitespace && len(result) > 0 && result[len(result)-1].kind == whitespace {
+			result[len(result)-1].text += text
+			return
+		}
+		result = append(result, &piece{kind: kind, text: text})
+	}
+	addSpace := func(text string) {
+		for _, r := range text {
+			if r == '\n' {
+				add(newline, "\n")
+			} else {
+				add(whitespace, string(r))
+			}
+		}
+	}
+
+	for _, t := range tokens {
+		switch t.Kind {
+		case "STAR":
+			add("STAR", "*")
+			add(whitespace, strings.TrimPrefix(t.Text, "*"))
+		case fsh.TokenWhitespace, fsh.TokenNewline:
+			addSpace(t.Text)
+		case "CONTEXT_WHITESPACE", "CODE_LIST_WHITESPACE":
+			// Line breaks inside lists do not end the line.
+			add(whitespace, t.Text)
+		case "RULESET_REFERENCE", "PARAM_RULESET_REFERENCE":
+			name := strings.TrimLeft(t.Text, " \t\r\n\f ")
+			if name != t.Text {
+				add(whitespace, t.Text[:len(t.Text)-len(name)])
+			}
+			add(t.Kind, name)
+		default:
+			add(t.Kind, t.Text)
+		}
+	}
+	return result
+}
+
+// lineKind is the kind of statement on a line.
+type lineKind int
+
+const (
+	blankLine lineKind = iota
+	commentLine
+	entityLine
+	metadataLine
+	ruleLine
+	otherLine
+)
+
+// line is a line of FSH, which continues over the line breaks inside multi-line
+// tokens, comments, and lists.
+type line struct {
+	kind lineKind
+
+	// keyword is the keyword of an entity or metadata line, such as "Profile"
+	// or "Id".
+	keyword string
+
+	indent string
+
+	// pieces are the pieces after the indentation, without trailing whitespace.
+	pieces []*piece
+}
+
+// splitLines splits the pieces into lines at newline pieces.
+func splitLines(pieces []*piece) []*line {
+	var lines []*line
+	start := 0
+	for i, p := range pieces {
+		if p.kind == newline {
+			lines = append(lines, newLine(pieces[start:i]))
+			start = i + 1
+		}
+	}
+	return append(lines, newLine(pieces[start:]))
+}
+
+// newLine returns the line of the pieces, classified by its first token.
+func newLine(pieces []*piece) *line {
+	l := &line{}
+	if len(pieces) > 0 && pieces[0].kind == whitespace {
+		l.indent = pieces[0].text
+		pieces = pieces[1:]
+	}
+	for len(pieces) > 0 && pieces[len(pieces)-1].kind == whitespace {
+		pieces = pieces[:len(pieces)-1]
+	}
+	l.pieces = pieces
+
+	if len(pieces) == 0 {
+		l.kind = blankLine
+		return l
+	}
+	first := pieces[0].kind
+	switch {
+	case fsh.IsEntityKeyword(first):
+		l.kind, l.indent = entityLine, ""
+		l.keyword = keyword(pieces[0].text)
+	case fsh.IsMetadataKeyword(first):
+		l.kind, l.indent = metadataLine, ""
+		l.keyword = keyword(pieces[0].text)
+	case first == "STAR":
+		l.kind = ruleLine
+	default:
+		l.kind = commentLine
+		for _, p := range pieces {
+			if !isComment(p) && p.kind != whitespace {
+				l.kind = otherLine
+			}
+		}
+	}
+	return l
+}
+
+// keyword returns the keyword of an entity or metadata keyword token, such as
+// "Id" for "Id :".
+func keyword(text string) string {
+	return strings.TrimSpace(strings.TrimSuffix(text, ":"))
+}
+
+// isComment returns true if the piece is a comment.
+func isComment(p *piece) bool {
+	return p.kind == fsh.TokenLineComment || p.kind == fsh.TokenBlockComment
+}
+
+// isKeyword returns true if the piece is a keyword token whose whitespace is
+// removed, such as "Id :" or "( required )".
+func isKeyword(p *piece) bool {
+	return strings.HasPrefix(p.kind, "KW_") && strings.ContainsAny(p.text, " \t\r\n\f ")
+}
+
+// render returns the line with canonical spacing, up to the piece at index n.
+func (l *line) render(n int) string {
+	var b strings.Builder
+	b.WriteString(l.indent)
+	for i, p := range l.pieces[:n] {
+		switch {
+		case p.kind == whitespace && strings.Contains(p.text, "\n"):
+			// Line breaks inside lists are kept, with the indentation after
+			// them.
+			b.WriteString(strings.TrimLeft(p.text, " \t "))
+		case p.kind == whitespace:
+			column := utf8.RuneCountInString(b.String()[strings.LastIndex(b.String(), "\n")+1:])
+			b.WriteString(strings.Repeat(" ", max(1, p.column-column)))
+		case isKeyword(p):
+			b.WriteString(strings.Join(strings.Fields(p.text), ""))
+		case isComment(p):
+			b.WriteString(strings.TrimRight(p.text, " \t\r\f "))
+		default:
+			b.WriteString(p.text)
+		}
+		// Keywords ending in ":" are followed by a space.
+		if strings.HasSuffix(p.text, ":") && strings.HasPrefix(p.kind, "KW_") &&
+			i+1 < len(l.pieces) && l.pieces[i+1].kind != whitespace {
+			b.WriteString(" ")
+		}
+	}
+	return b.String()
+}
+
+// String returns the line with canonical spacing.
+func (l *line) String() string {
+	return l.render(len(l.pieces))
+}
+
+// formatLines returns the formatted lines of the file.
+func formatLines(lines []*line) []string {
+	var entities []int
+	for i, l := range lines {
+		if l.kind == entityLine {
+			entities = append(entities, i)
+		}
+	}
+	// Comments right before an entity belong to it.
+	blockStart := func(entity int) int {
+		start := entity
+		for start > 0 && lines[start-1].kind == commentLine {
+			start--
+		}
+		return start
+	}
+
+	var out []string
+	// writeBody writes the lines, collapsing runs of blank lines, and dropping
+	// blank lines at the start of the file and at the end of the lines.
+	writeBody := func(body []*line) {
+		alignConcepts(body)
+		blank := false
+		for _, l := range body {
+			if l.kind == blankLine {
+				blank = true
+				continue
+			}
+			if blank && len(out) > 0 {
+				out = append(out, "")
+			}
+			blank = false
+			out = append(out, l.String())
+		}
+	}
+
+	end := len(lines)
+	if len(entities) > 0 {
+		end = blockStart(entities[0])
+	}
+	writeBody(lines[:end])
+
+	for i, entity := range entities {
+		start := blockStart(entity)
+		end := len(lines)
+		if i+1 < len(entities) {
+			end = blockStart(entities[i+1])
+		}
+
+		// Consecutive aliases are kept together, unless they were separated.
+		together := i > 0 && lines[entity].keyword == "Alias" && lines[entities[i-1]].keyword == "Alias" &&
+			start > 0 && lines[start-1].kind != blankLine
+		if len(out) > 0 && !together {
+			out = append(out, "")
+		}
+		for _, l := range lines[start : entity+1] {
+			out = append(out, l.String())
+		}
+
+		metadata, body := splitMetadata(lines[entity+1 : end])
+		slices.SortStableFunc(metadata, func(a, b []*line) int {
+			return cmp.Compare(metadataRank(a[len(a)-1].keyword), metadataRank(b[len(b)-1].keyword))
+		})
+		for _, group := range metadata {
+			for _, l := range group {
+				out = append(out, l.String())
+			}
+		}
+		writeBody(body)
+	}
+	return out
+}
+
+// metadataRank returns the position of the metadata keyword in the canonical
+// order.
+func metadataRank(keyword string) int {
+	if rank, ok := metadataRanks[keyword]; ok {
+		return rank
+	}
+	return len(metadataRanks)
+}
+
+// splitMetadata splits the lines after an entity declaration into its metadata
+// and the rest. Each group of metadata is a metadata line, after the comments
+// before it. Blank lines between metadata are dropped.
+func splitMetadata(lines []*line) ([][]*line, []*line) {
+	var metadata [][]*line
+	i := 0
+	for {
+		j := i
+		for j < len(lines) && (lines[j].kind == commentLine || lines[j].kind == blankLine) {
+			j++
+		}
+		if j == len(lines) || lines[j].kind != metadataLine {
+			return metadata, lines[i:]
+		}
+		var group []*line
+		for _, l := range lines[i:j] {
+			if l.kind == commentLine {
+				group = append(group, l)
+			}
+		}
+		metadata = append(metadata, append(group, lines[j]))
+		i = j + 1
+	}
+}
+
+// concept returns the indexes of the whitespace pieces before the display and
+// the definition of a concept line, such as `* #code "Display" "Definition"`,
+// on a single line. The definition is -1 if there is none. Returns false if the
+// line is not such a concept.
+func (l *line) concept() (display, definition int, ok bool) {
+	p := l.pieces
+	isSpace := func(i int) bool {
+		return i < len(p) && p[i].kind == whitespace && !strings.Contains(p[i].text, "\n")
+	}
+	isKind := func(i int, kinds ...string) bool {
+		return i < len(p) && slices.Contains(kinds, p[i].kind) && !strings.Contains(p[i].text, "\n")
+	}
+	if l.kind != ruleLine || !isSpace(1) || !isKind(2, "CODE") {
+		return 0, 0, false
+	}
+	i := 2
+	for isSpace(i+1) && isKind(i+2, "CODE") {
+		i += 2
+	}
+	if !isSpace(i+1) || !isKind(i+2, "STRING") {
+		return 0, 0, false
+	}
+	display, definition = i+1, -1
+	i += 2
+	if isSpace(i+1) && isKind(i+2, "STRING", "MULTILINE_STRING") {
+		definition = i + 1
+		i += 2
+	}
+	// Only a comment may follow.
+	for _, rest := range p[i+1:] {
+		if rest.kind != whitespace && !isComment(rest) {
+			return 0, 0, false
+		}
+	}
+	return display, definition, true
+}
+
+// alignConcepts aligns the displays, and the definitions, of each run of
+// consecutive concept lines.
+func alignConcepts(lines []*line) {
+	for i := 0; i < len(lines); {
+		j := i
+		for j < len(lines) {
+			if _, _, ok := lines[j].concept(); !ok {
+				break
+			}
+			j++
+		}
+		if j-i > 1 {
+			alignColumn(lines[i:j], func(l *line) int { display, _, _ := l.concept(); return display })
+			alignColumn(lines[i:j], func(l *line) int { _, definition, _ := l.concept(); return definition })
+		}
+		i = max(j, i+1)
+	}
+}
+
+// alignColumn pads the pieces after the whitespace pieces at the indexes that
+// index returns, or -1 for none, to the same column, if there are at least two.
+func alignColumn(lines []*line, index func(*line) int) {
+	column, count := 0, 0
+	for _, l := range lines {
+		if i := index(l); i >= 0 {
+			column = max(column, utf8.RuneCountInString(l.render(i))+1)
+			count++
+		}
+	}
+	if count < 2 {
+		return
+	}
+	for _, l := range lines {
+		if i := index(l); i >= 0 {
+			l.pieces[i].column = column
+		}
+	}
+}
+
+// fingerprint returns the sorted tokens of the source, other than whitespace,
+// with the whitespace inside keywords removed, to check that formatting only
+// changed the layout of the source.
+func fingerprint(src string) []string {
+	var result []string
+	for _, p := range pieces(fsh.Tokens(src)) {
+		switch {
+		case p.kind == whitespace || p.kind == newline:
+			continue
+		case isKeyword(p):
+			result = append(result, p.kind+" "+strings.Join(strings.Fields(p.text), ""))
+		case isComment(p):
+			result = append(result, p.kind+" "+strings.TrimRight(p.text, " \t\r\f "))
+		default:
+			result = append(result, p.kind+" "+p.text)
+		}
+	}
+	slices.Sort(result)
+	return result
+}
@@ -0,0 +1,89 @@
+package format_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/format"
+)
+
+func TestSource(t *testing.T) {
+	tests := []struct {
+		name    string
+		src     string
+		want    string
+		wantErr bool
+	}{
+		{
+			name: "formatted",
+			src:  "Profile: MyPatient\nParent: Patient\nId: my-patient\n* name 1..1 MS\n",
+			want: "Profile: MyPatient\nParent: Patient\nId: my-patient\n* name 1..1 MS\n",
+		},
+		{
+			name: "orders metadata",
+			src:  "Profile: MyPatient\nDescription: \"A patient.\"\nTitle: \"My Patient\"\nId: my-patient\nParent: Patient\n* name 1..1\n",
+			want: "Profile: MyPatient\nParent: Patient\nId: my-patient\nTitle: \"My Patient\"\nDescription: \"A patient.\"\n* name 1..1\n",
+		},
+		{
+			name: "keeps comments with metadata",
+			src:  "Profile: MyPatient\nId: my-patient\n\n// The parent.\nParent: Patient\n",
+			want: "Profile: MyPatient\n// The parent.\nParent: Patient\nId: my-patient\n",
+		},
+		{
+			name: "normalizes spacing",
+			src:  "Profile:   MyPatient  \nParent :   Patient\n*   name   1..1  MS   // comment   \n  * name.given   MS\n* gender from MyVS ( required )\n",
+			want: "Profile: MyPatient\nParent: Patient\n* name 1..1 MS // comment\n  * name.given MS\n* gender from MyVS (required)\n",
+		},
+		{
+			name: "normalizes insert rules",
+			src:  "Profile: MyPatient\nParent: Patient\n* insert   MyRules\n* insert   MyParams(a,  \"b  c\")\n",
+			want: "Profile: MyPatient\nParent: Patient\n* insert MyRules\n* insert MyParams(a,  \"b  c\")\n",
+		},
+		{
+			name: "aligns concepts",
+			src:  "CodeSystem: MyCS\n* #a \"A\" \"The A.\"\n* #bbb  \"Bbb\" \"The B.\"\n  * #c \"C\"\n* ^status = #draft\n* #d \"D\"\n",
+			want: "CodeSystem: MyCS\n* #a   \"A\"   \"The A.\"\n* #bbb \"Bbb\" \"The B.\"\n  * #c \"C\"\n* ^status = #draft\n* #d \"D\"\n",
+		},
+		{
+			name: "separates entities with a blank line",
+			src:  "\n\nAlias: $a = http://a\nAlias: $b = http://b\n\n\nAlias: $c = http://c\nProfile: A\nParent: Patient\n\n\n\n* name 1..1\n// B.\nProfile: B\nParent: Patient\n\n\n",
+			want: "Alias: $a = http://a\nAlias: $b = http://b\n\nAlias: $c = http://c\n\nProfile: A\nParent: Patient\n\n* name 1..1\n\n// B.\nProfile: B\nParent: Patient\n",
+		},
+		{
+			name: "keeps multi-line strings and block comments",
+			src:  "Profile: A\nParent: Patient\nDescription: \"\"\"\n  Line one.\n    Line two.\n\"\"\"\n/* Block\n   comment. */\n*  name 1..1\n",
+			want: "Profile: A\nParent: Patient\nDescription: \"\"\"\n  Line one.\n    Line two.\n\"\"\"\n/* Block\n   comment. */\n* name 1..1\n",
+		},
+		{
+			name: "keeps CRLF line endings",
+			src:  "Profile: A\r\nId: a\r\nParent: Patient\r\n",
+			want: "Profile: A\r\nParent: Patient\r\nId: a\r\n",
+		},
+		{
+			name:    "syntax error",
+			src:     "Profile: A\n* #x, #y\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, err := format.Source([]byte(tt.src))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("Source() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.want, string(got)); diff != "" {
+				t.Errorf("Source() mismatch (-want +got):\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+			again, err := format.Source(got)
+			if err != nil {
+				t.Fatalf("Source() of formatted source error = %v", err)
+			}
+			if diff := cmp.Diff(string(got), string(again)); diff != "" {
+				t.Errorf("Source() is not idempotent (-first +second):\n%s", diff)
+			}
+		})
+	}
+}
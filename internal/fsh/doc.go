@@ -1,3 +1,3 @@
 // Package fsh provides functions to parse FSH strings into FSH objects defined in
-// types package.
+// the ast package. Use the public parse package outside of this module.
 package fsh
@@ -3,15 +3,19 @@ package fsh
 import (
 	"fmt"
 
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/internal/fsh/internal/grammar"
 	"github.com/verily-src/fsh-lint/internal/fsh/internal/parser"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
 
 	"github.com/antlr4-go/antlr/v4"
 )
 
+// SyntaxError is an error in the syntax of a FSH document. The errors returned
+// by Parse join one SyntaxError for each error found.
+type SyntaxError = parser.SyntaxError
+
 // Parse parses a FSH doc to a FSHDocument object.
-func Parse(fshData string) (*types.FSHDocument, error) {
+func Parse(fshData string) (*ast.FSHDocument, error) {
 	stream := antlr.NewInputStream(fshData)
 
 	// Lex the input stream
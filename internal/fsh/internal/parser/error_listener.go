@@ -7,14 +7,25 @@ import (
 	"github.com/antlr4-go/antlr/v4"
 )
 
+// SyntaxError is an error in the syntax of a FSH document, at a 1-based line and
+// a 0-based column.
+type SyntaxError struct {
+	Line    int
+	Column  int
+	Message string
+}
+
+func (e *SyntaxError) Error() string {
+	return fmt.Sprintf("syntax error on line %d:%d - %s", e.Line, e.Column, e.Message)
+}
+
 type FSHErrorListener struct {
 	*antlr.DefaultErrorListener
 	errors []error
 }
 
 func (l *FSHErrorListener) SyntaxError(recognizer antlr.Recognizer, offendingSymbol interface{}, line, column int, msg string, e antlr.RecognitionException) {
-	err := fmt.Errorf("syntax error on line %d:%d - %s", line, column, msg)
-	l.errors = append(l.errors, err)
+	l.errors = append(l.errors, &SyntaxError{Line: line, Column: column, Message: msg})
 }
 
 func (l *FSHErrorListener) Error() error {
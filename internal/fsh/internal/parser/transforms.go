@@ -1,11 +1,11 @@
 package parser
 
 import (
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 )
 
 // keyValue is a simple struct to hold a key-value pair.
 type keyValue struct {
 	key   string
-	value *types.ParsedElement[string]
+	value *ast.ParsedElement[string]
 }
@@ -6,8 +6,8 @@ import (
 	"strconv"
 	"strings"
 
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/internal/fsh/internal/grammar"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
 
 	"github.com/antlr4-go/antlr/v4"
 )
@@ -27,8 +27,8 @@ func (v *FSHVisitor) Visit(tree antlr.ParseTree) any {
 	return tree.Accept(v)
 }
 
-func (v *FSHVisitor) VisitDoc(ctx grammar.IDocContext) (*types.FSHDocument, error) {
-	doc := &types.FSHDocument{}
+func (v *FSHVisitor) VisitDoc(ctx grammar.IDocContext) (*ast.FSHDocument, error) {
+	doc := &ast.FSHDocument{}
 	for _, entry := range ctx.AllEntity() {
 		if entry.Alias() != nil {
 			entry.Alias().Accept(v)
@@ -61,7 +61,7 @@ func (v *FSHVisitor) VisitDoc(ctx grammar.IDocContext) (*types.FSHDocument, erro
 			doc.Instances = append(doc.Instances, i)
 		}
 		if entry.Invariant() != nil {
-			entry.Invariant().Accept(v)
+			doc.Invariants = append(doc.Invariants, v.VisitInvariant(entry.Invariant()))
 		}
 		if entry.ValueSet() != nil {
 			vs, err := v.VisitValueSet(entry.ValueSet())
@@ -78,10 +78,10 @@ func (v *FSHVisitor) VisitDoc(ctx grammar.IDocContext) (*types.FSHDocument, erro
 			doc.CodeSystems = append(doc.CodeSystems, cs)
 		}
 		if entry.RuleSet() != nil {
-			entry.RuleSet().Accept(v)
+			doc.RuleSets = append(doc.RuleSets, v.VisitRuleSet(entry.RuleSet()))
 		}
 		if entry.ParamRuleSet() != nil {
-			entry.ParamRuleSet().Accept(v)
+			doc.RuleSets = append(doc.RuleSets, v.VisitParamRuleSet(entry.ParamRuleSet()))
 		}
 		if entry.Mapping() != nil {
 			entry.Mapping().Accept(v)
@@ -98,8 +98,8 @@ func (v *FSHVisitor) VisitAlias(ctx grammar.IAliasContext) interface{} {
 	return v.VisitChildren(ctx)
 }
 
-func (v *FSHVisitor) VisitProfile(ctx grammar.IProfileContext) (*types.Profile, error) {
-	p := &types.Profile{}
+func (v *FSHVisitor) VisitProfile(ctx grammar.IProfileContext) (*ast.Profile, error) {
+	p := &ast.Profile{}
 	p.Name = v.VisitName(ctx.Name())
 
 	for _, md := range ctx.AllSdMetadata() {
@@ -118,7 +118,7 @@ func (v *FSHVisitor) VisitProfile(ctx grammar.IProfileContext) (*types.Profile,
 		}
 	}
 
-	rules := &types.StructureDefRules{}
+	rules := &ast.StructureDefRules{}
 	for _, rule := range ctx.AllSdRule() {
 		v.VisitSDRule(rule, rules)
 	}
@@ -127,8 +127,8 @@ func (v *FSHVisitor) VisitProfile(ctx grammar.IProfileContext) (*types.Profile,
 	return p, nil
 }
 
-func (v *FSHVisitor) VisitExtension(ctx grammar.IExtensionContext) (*types.Extension, error) {
-	e := &types.Extension{}
+func (v *FSHVisitor) VisitExtension(ctx grammar.IExtensionContext) (*ast.Extension, error) {
+	e := &ast.Extension{}
 	e.Name = v.VisitName(ctx.Name())
 
 	for _, md := range ctx.AllSdMetadata() {
@@ -147,13 +147,13 @@ func (v *FSHVisitor) VisitExtension(ctx grammar.IExtensionContext) (*types.Exten
 		}
 	}
 
-	rules := &types.StructureDefRules{}
+	rules := &ast.StructureDefRules{}
 	for _, rule := range ctx.AllSdRule() {
 		v.VisitSDRule(rule, rules)
 	}
 	e.ExtensionRules = rules
 
-	var contexts []*types.ParsedElement[string]
+	var contexts []*ast.ParsedElement[string]
 	for _, context := range ctx.AllContext() {
 		v.VisitContext(context, &contexts)
 	}
@@ -190,7 +190,7 @@ func (v *FSHVisitor) VisitSDMetadata(ctx grammar.ISdMetadataContext) *keyValue {
 
 // VisitSDRule modifies rules by appending one rule to the appropriate rule type list. For more information on all possible profile rules,
 // see https://build.fhir.org/ig/HL7/fhir-shorthand/reference.html#defining-profiles:~:text=Rules%20types%20that%20apply%20to%20Profiles
-func (v *FSHVisitor) VisitSDRule(ctx grammar.ISdRuleContext, rules *types.StructureDefRules) {
+func (v *FSHVisitor) VisitSDRule(ctx grammar.ISdRuleContext, rules *ast.StructureDefRules) {
 	if ctx.CardRule() != nil {
 		c := v.VisitCardRule(ctx.CardRule())
 		rules.CardRules = append(rules.CardRules, c)
@@ -228,8 +228,8 @@ func (v *FSHVisitor) VisitLrRule(ctx grammar.ILrRuleContext) interface{} {
 	return v.VisitChildren(ctx)
 }
 
-func (v *FSHVisitor) VisitInstance(ctx grammar.IInstanceContext) (*types.Instance, error) {
-	i := &types.Instance{}
+func (v *FSHVisitor) VisitInstance(ctx grammar.IInstanceContext) (*ast.Instance, error) {
+	i := &ast.Instance{}
 	i.Name = v.VisitName(ctx.Name())
 
 	for _, md := range ctx.AllInstanceMetadata() {
@@ -248,7 +248,7 @@ func (v *FSHVisitor) VisitInstance(ctx grammar.IInstanceContext) (*types.Instanc
 		}
 	}
 
-	rules := &types.InstanceRules{}
+	rules := &ast.InstanceRules{}
 	for _, rule := range ctx.AllInstanceRule() {
 		v.VisitInstanceRule(rule, rules)
 	}
@@ -276,7 +276,7 @@ func (v *FSHVisitor) VisitInstanceMetadata(ctx grammar.IInstanceMetadataContext)
 	return kv
 }
 
-func (v *FSHVisitor) VisitInstanceRule(ctx grammar.IInstanceRuleContext, rules *types.InstanceRules) {
+func (v *FSHVisitor) VisitInstanceRule(ctx grammar.IInstanceRuleContext, rules *ast.InstanceRules) {
 	if ctx.FixedValueRule() != nil {
 		f := v.VisitFixedValueRule(ctx.FixedValueRule())
 		rules.AssignmentRules = append(rules.AssignmentRules, f)
@@ -289,20 +289,47 @@ func (v *FSHVisitor) VisitInstanceRule(ctx grammar.IInstanceRuleContext, rules *
 	}
 }
 
-func (v *FSHVisitor) VisitInvariant(ctx grammar.IInvariantContext) interface{} {
-	return v.VisitChildren(ctx)
+// VisitInvariant returns the invariant with its metadata. The rules of the
+// invariant are not parsed.
+func (v *FSHVisitor) VisitInvariant(ctx grammar.IInvariantContext) *ast.Invariant {
+	inv := &ast.Invariant{}
+	inv.Name = v.VisitName(ctx.Name())
+	for _, md := range ctx.AllInvariantMetadata() {
+		kv := v.VisitInvariantMetadata(md)
+		switch kv.key {
+		case "description":
+			inv.Description = kv.value
+		case "expression":
+			inv.Expression = kv.value
+		case "xpath":
+			inv.XPath = kv.value
+		case "severity":
+			inv.Severity = kv.value
+		}
+	}
+	return inv
 }
 
-func (v *FSHVisitor) VisitInvariantMetadata(ctx grammar.IInvariantMetadataContext) interface{} {
-	return v.VisitChildren(ctx)
+func (v *FSHVisitor) VisitInvariantMetadata(ctx grammar.IInvariantMetadataContext) *keyValue {
+	kv := &keyValue{}
+	if ctx.Description() != nil {
+		kv = &keyValue{"description", v.VisitDescription(ctx.Description())}
+	} else if ctx.Expression() != nil {
+		kv = &keyValue{"expression", v.VisitExpression(ctx.Expression())}
+	} else if ctx.Xpath() != nil {
+		kv = &keyValue{"xpath", v.VisitXpath(ctx.Xpath())}
+	} else if ctx.Severity() != nil {
+		kv = &keyValue{"severity", v.VisitSeverity(ctx.Severity())}
+	}
+	return kv
 }
 
 func (v *FSHVisitor) VisitInvariantRule(ctx grammar.IInvariantRuleContext) interface{} {
 	return v.VisitChildren(ctx)
 }
 
-func (v *FSHVisitor) VisitValueSet(ctx grammar.IValueSetContext) (*types.ValueSet, error) {
-	vs := &types.ValueSet{}
+func (v *FSHVisitor) VisitValueSet(ctx grammar.IValueSetContext) (*ast.ValueSet, error) {
+	vs := &ast.ValueSet{}
 	vs.Name = v.VisitName(ctx.Name())
 	for _, md := range ctx.AllVsMetadata() {
 		kv := v.VisitVSMetadata(md)
@@ -318,9 +345,9 @@ func (v *FSHVisitor) VisitValueSet(ctx grammar.IValueSetContext) (*types.ValueSe
 		}
 	}
 
-	vs.IncludeComponents = make([]*types.ValueSetComponent, 0)
-	vs.ExcludeComponents = make([]*types.ValueSetComponent, 0)
-	vs.ValueSetRules = &types.ValueSetRules{}
+	vs.IncludeComponents = make([]*ast.ValueSetComponent, 0)
+	vs.ExcludeComponents = make([]*ast.ValueSetComponent, 0)
+	vs.ValueSetRules = &ast.ValueSetRules{}
 	for _, rule := range ctx.AllVsRule() {
 		v.VisitVSRule(rule, vs)
 	}
@@ -346,7 +373,7 @@ func (v *FSHVisitor) VisitVSMetadata(ctx grammar.IVsMetadataContext) *keyValue {
 // VisitVSRule modifies rules by appending one rule to the appropriate rule type list, or appending to the list of either
 // IncludeComponents, or ExcludeComponents. For more information on all possible value set rules, see
 // https://build.fhir.org/ig/HL7/fhir-shorthand/reference.html#defining-value-sets:~:text=Rule%20types%20that%20apply%20to%20ValueSets
-func (v *FSHVisitor) VisitVSRule(ctx grammar.IVsRuleContext, vs *types.ValueSet) {
+func (v *FSHVisitor) VisitVSRule(ctx grammar.IVsRuleContext, vs *ast.ValueSet) {
 	if ctx.VsComponent() != nil {
 		v.VisitVSComponent(ctx.VsComponent(), &vs.IncludeComponents, &vs.ExcludeComponents)
 	} else if ctx.CaretValueRule() != nil {
@@ -364,8 +391,8 @@ func (v *FSHVisitor) VisitVSRule(ctx grammar.IVsRuleContext, vs *types.ValueSet)
 	}
 }
 
-func (v *FSHVisitor) VisitCodeSystem(ctx grammar.ICodeSystemContext) (*types.CodeSystem, error) {
-	cs := &types.CodeSystem{}
+func (v *FSHVisitor) VisitCodeSystem(ctx grammar.ICodeSystemContext) (*ast.CodeSystem, error) {
+	cs := &ast.CodeSystem{}
 	cs.Name = v.VisitName(ctx.Name())
 	for _, md := range ctx.AllCsMetadata() {
 		kv := v.VisitCSMetadata(md)
@@ -381,7 +408,7 @@ func (v *FSHVisitor) VisitCodeSystem(ctx grammar.ICodeSystemContext) (*types.Cod
 		}
 	}
 
-	concepts := make([]*types.Concept, 0)
+	concepts := make([]*ast.Concept, 0)
 
 	for _, rule := range ctx.AllCsRule() {
 
@@ -444,7 +471,7 @@ func (v *FSHVisitor) VisitCSMetadata(ctx grammar.ICsMetadataContext) *keyValue {
 	return kv
 }
 
-func (v *FSHVisitor) VisitCSRule(ctx grammar.ICsRuleContext) (*types.Concept, []string) {
+func (v *FSHVisitor) VisitCSRule(ctx grammar.ICsRuleContext) (*ast.Concept, []string) {
 	if ctx.Concept() != nil {
 		return v.VisitConcept(ctx.Concept())
 	} else {
@@ -452,19 +479,30 @@ func (v *FSHVisitor) VisitCSRule(ctx grammar.ICsRuleContext) (*types.Concept, []
 	}
 }
 
-func (v *FSHVisitor) VisitRuleSet(ctx grammar.IRuleSetContext) interface{} {
-	return v.VisitChildren(ctx)
+// VisitRuleSet returns the rule set with its name. The rules of the rule set
+// are not parsed.
+func (v *FSHVisitor) VisitRuleSet(ctx grammar.IRuleSetContext) *ast.RuleSet {
+	return &ast.RuleSet{
+		Name:       createParsedElementFromToken(ctx.RULESET_REFERENCE().GetSymbol()),
+		Parameters: make([]*ast.ParsedElement[string], 0),
+	}
 }
 
 func (v *FSHVisitor) VisitRuleSetRule(ctx grammar.IRuleSetRuleContext) interface{} {
 	return v.VisitChildren(ctx)
 }
 
-func (v *FSHVisitor) VisitParamRuleSet(ctx grammar.IParamRuleSetContext) interface{} {
-	return v.VisitChildren(ctx)
+// VisitParamRuleSet returns the parameterized rule set with its name and
+// parameters. The rules of the rule set are not parsed.
+func (v *FSHVisitor) VisitParamRuleSet(ctx grammar.IParamRuleSetContext) *ast.RuleSet {
+	rs := &ast.RuleSet{Parameters: make([]*ast.ParsedElement[string], 0)}
+	v.VisitParamRuleSetRef(ctx.ParamRuleSetRef(), &rs.Name, &rs.Parameters)
+	// Locate the name where it starts, rather than at the whitespace before it.
+	rs.Name.Location = createParsedElementFromToken(ctx.ParamRuleSetRef().PARAM_RULESET_REFERENCE().GetSymbol()).Location
+	return rs
 }
 
-func (v *FSHVisitor) VisitParamRuleSetRef(ctx grammar.IParamRuleSetRefContext, ruleSetName **types.ParsedElement[string], parameters *[]*types.ParsedElement[string]) {
+func (v *FSHVisitor) VisitParamRuleSetRef(ctx grammar.IParamRuleSetRefContext, ruleSetName **ast.ParsedElement[string], parameters *[]*ast.ParsedElement[string]) {
 	if ctx.PARAM_RULESET_REFERENCE() != nil {
 		name := ctx.PARAM_RULESET_REFERENCE().GetText()
 		trimmedName := strings.TrimSuffix(name, "(")
@@ -481,7 +519,7 @@ func (v *FSHVisitor) VisitParamRuleSetRef(ctx grammar.IParamRuleSetRefContext, r
 	}
 }
 
-func (v *FSHVisitor) VisitParameter(ctx grammar.IParameterContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitParameter(ctx grammar.IParameterContext) *ast.ParsedElement[string] {
 	s := ctx.GetText()
 	s = strings.Trim(s, ", ")
 	s = trimQuotes(s)
@@ -489,7 +527,7 @@ func (v *FSHVisitor) VisitParameter(ctx grammar.IParameterContext) *types.Parsed
 	return createParsedElement(s, ctx)
 }
 
-func (v *FSHVisitor) VisitLastParameter(ctx grammar.ILastParameterContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitLastParameter(ctx grammar.ILastParameterContext) *ast.ParsedElement[string] {
 	s := ctx.GetText()
 	s = strings.Trim(s, ") ")
 	s = trimQuotes(s)
@@ -516,21 +554,21 @@ func (v *FSHVisitor) VisitMappingEntityRule(ctx grammar.IMappingEntityRuleContex
 // VisitParent returns the name of the parent of the entity.
 // The parser sometimes returns the text enclosed with double quotes, so trimming that off
 // to be consistent with the rest of the fields.
-func (v *FSHVisitor) VisitParent(ctx grammar.IParentContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitParent(ctx grammar.IParentContext) *ast.ParsedElement[string] {
 	s := ctx.Name().GetText()
 	s = trimQuotes(s)
 
 	return createParsedElement(s, ctx)
 }
 
-func (v *FSHVisitor) VisitId(ctx grammar.IIdContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitId(ctx grammar.IIdContext) *ast.ParsedElement[string] {
 	return v.VisitName(ctx.Name())
 }
 
 // VisitName returns the name of the entity.
 // The parser sometimes returns the text enclosed with double quotes, so trimming that off
 // to be consistent with the rest of the fields.
-func (v *FSHVisitor) VisitTitle(ctx grammar.ITitleContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitTitle(ctx grammar.ITitleContext) *ast.ParsedElement[string] {
 	s := ctx.STRING().GetText()
 	s = trimQuotes(s)
 
@@ -540,7 +578,7 @@ func (v *FSHVisitor) VisitTitle(ctx grammar.ITitleContext) *types.ParsedElement[
 // VisitDescription returns the description of the entity.
 // The parser sometimes returns the text enclosed with double quotes, so trimming that off
 // to be consistent with the rest of the fields.
-func (v *FSHVisitor) VisitDescription(ctx grammar.IDescriptionContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitDescription(ctx grammar.IDescriptionContext) *ast.ParsedElement[string] {
 	s := ""
 	if ctx.MULTILINE_STRING() != nil {
 		s = ctx.MULTILINE_STRING().GetText()
@@ -552,26 +590,26 @@ func (v *FSHVisitor) VisitDescription(ctx grammar.IDescriptionContext) *types.Pa
 	return createParsedElement(s, ctx)
 }
 
-func (v *FSHVisitor) VisitExpression(ctx grammar.IExpressionContext) interface{} {
-	return v.VisitChildren(ctx)
+func (v *FSHVisitor) VisitExpression(ctx grammar.IExpressionContext) *ast.ParsedElement[string] {
+	return createParsedElement(trimQuotes(ctx.STRING().GetText()), ctx)
 }
 
-func (v *FSHVisitor) VisitXpath(ctx grammar.IXpathContext) interface{} {
-	return v.VisitChildren(ctx)
+func (v *FSHVisitor) VisitXpath(ctx grammar.IXpathContext) *ast.ParsedElement[string] {
+	return createParsedElement(trimQuotes(ctx.STRING().GetText()), ctx)
 }
 
-func (v *FSHVisitor) VisitSeverity(ctx grammar.ISeverityContext) interface{} {
-	return v.VisitChildren(ctx)
+func (v *FSHVisitor) VisitSeverity(ctx grammar.ISeverityContext) *ast.ParsedElement[string] {
+	return createParsedElement(ctx.CODE().GetText(), ctx)
 }
 
-func (v *FSHVisitor) VisitInstanceOf(ctx grammar.IInstanceOfContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitInstanceOf(ctx grammar.IInstanceOfContext) *ast.ParsedElement[string] {
 	if ctx.Name() != nil {
 		return v.VisitName(ctx.Name())
 	}
 	return createParsedElement("", ctx)
 }
 
-func (v *FSHVisitor) VisitUsage(ctx grammar.IUsageContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitUsage(ctx grammar.IUsageContext) *ast.ParsedElement[string] {
 	return createParsedElement(ctx.CODE().GetText(), ctx)
 }
 
@@ -585,7 +623,7 @@ func (v *FSHVisitor) VisitTarget(ctx grammar.ITargetContext) interface{} {
 
 // VisitContext modifies contexts by appending all context items. For more information on the context keyword,
 // see https://build.fhir.org/ig/HL7/fhir-shorthand/reference.html#defining-extensions
-func (v *FSHVisitor) VisitContext(ctx grammar.IContextContext, contexts *[]*types.ParsedElement[string]) {
+func (v *FSHVisitor) VisitContext(ctx grammar.IContextContext, contexts *[]*ast.ParsedElement[string]) {
 	for _, item := range ctx.AllContextItem() {
 		*contexts = append(*contexts, v.VisitContextItem(item))
 	}
@@ -595,7 +633,7 @@ func (v *FSHVisitor) VisitContext(ctx grammar.IContextContext, contexts *[]*type
 	}
 }
 
-func (v *FSHVisitor) VisitContextItem(ctx grammar.IContextItemContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitContextItem(ctx grammar.IContextItemContext) *ast.ParsedElement[string] {
 	item := ""
 
 	if ctx.QUOTED_CONTEXT() != nil {
@@ -609,7 +647,7 @@ func (v *FSHVisitor) VisitContextItem(ctx grammar.IContextItemContext) *types.Pa
 	return createParsedElement(item, ctx)
 }
 
-func (v *FSHVisitor) VisitLastContextItem(ctx grammar.ILastContextItemContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitLastContextItem(ctx grammar.ILastContextItemContext) *ast.ParsedElement[string] {
 	item := ""
 
 	if ctx.LAST_QUOTED_CONTEXT() != nil {
@@ -627,8 +665,8 @@ func (v *FSHVisitor) VisitCharacteristics(ctx grammar.ICharacteristicsContext) i
 	return v.VisitChildren(ctx)
 }
 
-func (v *FSHVisitor) VisitCardRule(ctx grammar.ICardRuleContext) *types.CardRule {
-	cardRule := &types.CardRule{}
+func (v *FSHVisitor) VisitCardRule(ctx grammar.ICardRuleContext) *ast.CardRule {
+	cardRule := &ast.CardRule{}
 
 	if ctx.Path() != nil {
 		cardRule.Element = v.VisitPath(ctx.Path())
@@ -637,7 +675,7 @@ func (v *FSHVisitor) VisitCardRule(ctx grammar.ICardRuleContext) *types.CardRule
 	card := ctx.CARD().GetText()
 	cardRule.Cardinality = createCardinality(card, ctx)
 
-	cardRule.Flags = types.NewFlags()
+	cardRule.Flags = ast.NewFlags()
 	for _, flag := range ctx.AllFlag() {
 		v.VisitFlag(flag, cardRule.Flags)
 	}
@@ -645,14 +683,14 @@ func (v *FSHVisitor) VisitCardRule(ctx grammar.ICardRuleContext) *types.CardRule
 	return cardRule
 }
 
-func (v *FSHVisitor) VisitFlagRule(ctx grammar.IFlagRuleContext) *types.FlagRule {
-	flagRule := &types.FlagRule{}
+func (v *FSHVisitor) VisitFlagRule(ctx grammar.IFlagRuleContext) *ast.FlagRule {
+	flagRule := &ast.FlagRule{}
 
 	for _, element := range ctx.AllPath() {
 		flagRule.Elements = append(flagRule.Elements, createParsedElement(element.GetText(), ctx))
 	}
 
-	flagRule.Flags = types.NewFlags()
+	flagRule.Flags = ast.NewFlags()
 	for _, flag := range ctx.AllFlag() {
 		v.VisitFlag(flag, flagRule.Flags)
 	}
@@ -660,8 +698,8 @@ func (v *FSHVisitor) VisitFlagRule(ctx grammar.IFlagRuleContext) *types.FlagRule
 	return flagRule
 }
 
-func (v *FSHVisitor) VisitValueSetRule(ctx grammar.IValueSetRuleContext) *types.BindingRule {
-	bindingRule := &types.BindingRule{}
+func (v *FSHVisitor) VisitValueSetRule(ctx grammar.IValueSetRuleContext) *ast.BindingRule {
+	bindingRule := &ast.BindingRule{}
 
 	if ctx.Path() != nil {
 		bindingRule.Bindable = v.VisitPath(ctx.Path())
@@ -676,8 +714,8 @@ func (v *FSHVisitor) VisitValueSetRule(ctx grammar.IValueSetRuleContext) *types.
 	return bindingRule
 }
 
-func (v *FSHVisitor) VisitFixedValueRule(ctx grammar.IFixedValueRuleContext) *types.AssignmentRule {
-	assignmentRule := &types.AssignmentRule{}
+func (v *FSHVisitor) VisitFixedValueRule(ctx grammar.IFixedValueRuleContext) *ast.AssignmentRule {
+	assignmentRule := &ast.AssignmentRule{}
 
 	if ctx.Path() != nil {
 		assignmentRule.Element = v.VisitPath(ctx.Path())
@@ -689,20 +727,20 @@ func (v *FSHVisitor) VisitFixedValueRule(ctx grammar.IFixedValueRuleContext) *ty
 	if ctx.KW_EXACTLY() != nil {
 		assignmentRule.Exactly = createParsedElement(true, ctx)
 	} else {
-		assignmentRule.Exactly = types.NewParsedElementWithoutLocation(false)
+		assignmentRule.Exactly = ast.NewParsedElementWithoutLocation(false)
 	}
 
 	return assignmentRule
 }
 
-func (v *FSHVisitor) VisitContainsRule(ctx grammar.IContainsRuleContext) *types.ContainsRule {
-	containsRule := &types.ContainsRule{}
+func (v *FSHVisitor) VisitContainsRule(ctx grammar.IContainsRuleContext) *ast.ContainsRule {
+	containsRule := &ast.ContainsRule{}
 
 	if ctx.Path() != nil {
 		containsRule.Name = v.VisitPath(ctx.Path())
 	}
 
-	items := make([]*types.Item, 0)
+	items := make([]*ast.Item, 0)
 	for _, item := range ctx.AllItem() {
 		items = append(items, v.VisitItem(item))
 	}
@@ -711,28 +749,28 @@ func (v *FSHVisitor) VisitContainsRule(ctx grammar.IContainsRuleContext) *types.
 	return containsRule
 }
 
-func (v *FSHVisitor) VisitOnlyRule(ctx grammar.IOnlyRuleContext) *types.TypeRule {
-	typeRule := &types.TypeRule{}
+func (v *FSHVisitor) VisitOnlyRule(ctx grammar.IOnlyRuleContext) *ast.TypeRule {
+	typeRule := &ast.TypeRule{}
 	if ctx.Path() != nil {
 		typeRule.Element = v.VisitPath(ctx.Path())
 	}
 
-	typeRule.Types = make([]*types.DataType, 0)
+	typeRule.Types = make([]*ast.DataType, 0)
 	for _, targetType := range ctx.AllTargetType() {
 		typeRule.Types = append(typeRule.Types, v.VisitTargetType(targetType))
 	}
 	return typeRule
 }
 
-func (v *FSHVisitor) VisitObeysRule(ctx grammar.IObeysRuleContext) *types.ObeysRule {
-	obeysRule := &types.ObeysRule{}
+func (v *FSHVisitor) VisitObeysRule(ctx grammar.IObeysRuleContext) *ast.ObeysRule {
+	obeysRule := &ast.ObeysRule{}
 
 	// nil path indicates that the obeys rule applies to the whole profile
 	if ctx.Path() != nil {
 		obeysRule.Element = v.VisitPath(ctx.Path())
 	}
 
-	obeysRule.Invariants = make([]*types.ParsedElement[string], 0)
+	obeysRule.Invariants = make([]*ast.ParsedElement[string], 0)
 
 	for _, invariant := range ctx.AllName() {
 		obeysRule.Invariants = append(obeysRule.Invariants, v.VisitName(invariant))
@@ -741,8 +779,8 @@ func (v *FSHVisitor) VisitObeysRule(ctx grammar.IObeysRuleContext) *types.ObeysR
 	return obeysRule
 }
 
-func (v *FSHVisitor) VisitCaretValueRule(ctx grammar.ICaretValueRuleContext) *types.CaretValueRule {
-	caretValueRule := &types.CaretValueRule{}
+func (v *FSHVisitor) VisitCaretValueRule(ctx grammar.ICaretValueRuleContext) *ast.CaretValueRule {
+	caretValueRule := &ast.CaretValueRule{}
 
 	// nil path indicates that the element is an element of the StructureDefinition
 	// non-nil path indicates that the element is an element of an ElementDefinition within a Profile
@@ -761,8 +799,8 @@ func (v *FSHVisitor) VisitCaretValueRule(ctx grammar.ICaretValueRuleContext) *ty
 	return caretValueRule
 }
 
-func (v *FSHVisitor) VisitCodeCaretValueRule(ctx grammar.ICodeCaretValueRuleContext) *types.CodeCaretValueRule {
-	codeCaretValueRule := &types.CodeCaretValueRule{}
+func (v *FSHVisitor) VisitCodeCaretValueRule(ctx grammar.ICodeCaretValueRuleContext) *ast.CodeCaretValueRule {
+	codeCaretValueRule := &ast.CodeCaretValueRule{}
 
 	for _, c := range ctx.AllCODE() {
 		code := createParsedElement(c.GetText(), ctx)
@@ -783,8 +821,8 @@ func (v *FSHVisitor) VisitMappingRule(ctx grammar.IMappingRuleContext) interface
 	return v.VisitChildren(ctx)
 }
 
-func (v *FSHVisitor) VisitInsertRule(ctx grammar.IInsertRuleContext) *types.InsertRule {
-	insertRule := &types.InsertRule{}
+func (v *FSHVisitor) VisitInsertRule(ctx grammar.IInsertRuleContext) *ast.InsertRule {
+	insertRule := &ast.InsertRule{}
 
 	// nil path indicates the rule applies to the context it appears in
 	// non-nil path indicates the insert rule is applied within the context of the path
@@ -793,12 +831,10 @@ func (v *FSHVisitor) VisitInsertRule(ctx grammar.IInsertRuleContext) *types.Inse
 	}
 
 	if ctx.RULESET_REFERENCE() != nil {
-		ruleSet := ctx.RULESET_REFERENCE().GetText()
-		ruleSet = strings.TrimSpace(ruleSet)
-		insertRule.RuleSetName = createParsedElement(ruleSet, ctx)
+		insertRule.RuleSetName = createParsedElementFromToken(ctx.RULESET_REFERENCE().GetSymbol())
 	}
 
-	insertRule.Parameters = make([]*types.ParsedElement[string], 0)
+	insertRule.Parameters = make([]*ast.ParsedElement[string], 0)
 	if ctx.ParamRuleSetRef() != nil {
 		v.VisitParamRuleSetRef(ctx.ParamRuleSetRef(), &insertRule.RuleSetName, &insertRule.Parameters)
 	}
@@ -806,8 +842,8 @@ func (v *FSHVisitor) VisitInsertRule(ctx grammar.IInsertRuleContext) *types.Inse
 	return insertRule
 }
 
-func (v *FSHVisitor) VisitCodeInsertRule(ctx grammar.ICodeInsertRuleContext) *types.CodeInsertRule {
-	codeInsertRule := &types.CodeInsertRule{}
+func (v *FSHVisitor) VisitCodeInsertRule(ctx grammar.ICodeInsertRuleContext) *ast.CodeInsertRule {
+	codeInsertRule := &ast.CodeInsertRule{}
 
 	for _, c := range ctx.AllCODE() {
 		code := createParsedElement(c.GetText(), ctx)
@@ -815,12 +851,10 @@ func (v *FSHVisitor) VisitCodeInsertRule(ctx grammar.ICodeInsertRuleContext) *ty
 	}
 
 	if ctx.RULESET_REFERENCE() != nil {
-		ruleSet := ctx.RULESET_REFERENCE().GetText()
-		ruleSet = strings.TrimSpace(ruleSet)
-		codeInsertRule.RuleSetName = createParsedElement(ruleSet, ctx)
+		codeInsertRule.RuleSetName = createParsedElementFromToken(ctx.RULESET_REFERENCE().GetSymbol())
 	}
 
-	codeInsertRule.Parameters = make([]*types.ParsedElement[string], 0)
+	codeInsertRule.Parameters = make([]*ast.ParsedElement[string], 0)
 	if ctx.ParamRuleSetRef() != nil {
 		v.VisitParamRuleSetRef(ctx.ParamRuleSetRef(), &codeInsertRule.RuleSetName, &codeInsertRule.Parameters)
 	}
@@ -836,8 +870,8 @@ func (v *FSHVisitor) VisitAddElementRule(ctx grammar.IAddElementRuleContext) int
 	return v.VisitChildren(ctx)
 }
 
-func (v *FSHVisitor) VisitPathRule(ctx grammar.IPathRuleContext) *types.PathRule {
-	pathRule := &types.PathRule{}
+func (v *FSHVisitor) VisitPathRule(ctx grammar.IPathRuleContext) *ast.PathRule {
+	pathRule := &ast.PathRule{}
 
 	if ctx.Path() != nil {
 		pathRule.Path = v.VisitPath(ctx.Path())
@@ -847,8 +881,8 @@ func (v *FSHVisitor) VisitPathRule(ctx grammar.IPathRuleContext) *types.PathRule
 }
 
 // VisitVSComponent appends to either includeComps or excludeComps with the component found in the context.
-func (v *FSHVisitor) VisitVSComponent(ctx grammar.IVsComponentContext, includeComps *[]*types.ValueSetComponent, excludeComps *[]*types.ValueSetComponent) {
-	component := &types.ValueSetComponent{}
+func (v *FSHVisitor) VisitVSComponent(ctx grammar.IVsComponentContext, includeComps *[]*ast.ValueSetComponent, excludeComps *[]*ast.ValueSetComponent) {
+	component := &ast.ValueSetComponent{}
 
 	// this is the code and path when including a single code (not using from syntax)
 	if ctx.VsConceptComponent() != nil {
@@ -871,7 +905,7 @@ func (v *FSHVisitor) VisitVSComponent(ctx grammar.IVsComponentContext, includeCo
 }
 
 // VisitVSConceptComponent updates component with the source(s) of the codes.
-func (v *FSHVisitor) VisitVSConceptComponent(ctx grammar.IVsConceptComponentContext, component *types.ValueSetComponent) {
+func (v *FSHVisitor) VisitVSConceptComponent(ctx grammar.IVsConceptComponentContext, component *ast.ValueSetComponent) {
 	if ctx.Code() != nil {
 		v.VisitCode(ctx.Code(), component)
 	}
@@ -882,7 +916,7 @@ func (v *FSHVisitor) VisitVSConceptComponent(ctx grammar.IVsConceptComponentCont
 }
 
 // VisitVSFilterComponent updates component with the source of the codes.
-func (v *FSHVisitor) VisitVSFilterComponent(ctx grammar.IVsFilterComponentContext, component *types.ValueSetComponent) {
+func (v *FSHVisitor) VisitVSFilterComponent(ctx grammar.IVsFilterComponentContext, component *ast.ValueSetComponent) {
 	if ctx.VsComponentFrom() != nil {
 		v.VisitVSComponentFrom(ctx.VsComponentFrom(), component)
 	}
@@ -893,7 +927,7 @@ func (v *FSHVisitor) VisitVSFilterComponent(ctx grammar.IVsFilterComponentContex
 }
 
 // VisitVSComponentFrom updates component with the source of the codes.
-func (v *FSHVisitor) VisitVSComponentFrom(ctx grammar.IVsComponentFromContext, component *types.ValueSetComponent) {
+func (v *FSHVisitor) VisitVSComponentFrom(ctx grammar.IVsComponentFromContext, component *ast.ValueSetComponent) {
 	if ctx.VsFromSystem() != nil {
 		v.VisitVSFromSystem(ctx.VsFromSystem(), component)
 	}
@@ -903,14 +937,14 @@ func (v *FSHVisitor) VisitVSComponentFrom(ctx grammar.IVsComponentFromContext, c
 }
 
 // VisitVSFromSystem updates component with the codesystems code source.
-func (v *FSHVisitor) VisitVSFromSystem(ctx grammar.IVsFromSystemContext, component *types.ValueSetComponent) {
+func (v *FSHVisitor) VisitVSFromSystem(ctx grammar.IVsFromSystemContext, component *ast.ValueSetComponent) {
 	if ctx.Name() != nil {
 		name := ctx.Name().GetText()
 		component.FromCodeSystem = createValueSetCodesSource(name, ctx)
 	}
 }
 
-func (v *FSHVisitor) VisitVSFromValueset(ctx grammar.IVsFromValuesetContext, component *types.ValueSetComponent) {
+func (v *FSHVisitor) VisitVSFromValueset(ctx grammar.IVsFromValuesetContext, component *ast.ValueSetComponent) {
 	for _, name := range ctx.AllName() {
 		codesSource := createValueSetCodesSource(name.GetText(), ctx)
 		component.FromValueSet = append(component.FromValueSet, codesSource)
@@ -918,15 +952,15 @@ func (v *FSHVisitor) VisitVSFromValueset(ctx grammar.IVsFromValuesetContext, com
 }
 
 // VisitVSFilterList updates filters with the filters found in the context.
-func (v *FSHVisitor) VisitVSFilterList(ctx grammar.IVsFilterListContext, filters *[]*types.ValueSetFilter) {
+func (v *FSHVisitor) VisitVSFilterList(ctx grammar.IVsFilterListContext, filters *[]*ast.ValueSetFilter) {
 	for _, filter := range ctx.AllVsFilterDefinition() {
 		f := v.VisitVSFilterDefinition(filter)
 		*filters = append(*filters, f)
 	}
 }
 
-func (v *FSHVisitor) VisitVSFilterDefinition(ctx grammar.IVsFilterDefinitionContext) *types.ValueSetFilter {
-	filter := &types.ValueSetFilter{}
+func (v *FSHVisitor) VisitVSFilterDefinition(ctx grammar.IVsFilterDefinitionContext) *ast.ValueSetFilter {
+	filter := &ast.ValueSetFilter{}
 
 	if ctx.Name() != nil {
 		filter.Property = v.VisitName(ctx.Name())
@@ -943,29 +977,29 @@ func (v *FSHVisitor) VisitVSFilterDefinition(ctx grammar.IVsFilterDefinitionCont
 	return filter
 }
 
-func (v *FSHVisitor) VisitVSFilterOperator(ctx grammar.IVsFilterOperatorContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitVSFilterOperator(ctx grammar.IVsFilterOperatorContext) *ast.ParsedElement[string] {
 	return createParsedElement(ctx.GetText(), ctx)
 }
 
-func (v *FSHVisitor) VisitVSFilterValue(ctx grammar.IVsFilterValueContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitVSFilterValue(ctx grammar.IVsFilterValueContext) *ast.ParsedElement[string] {
 	return createParsedElement(ctx.GetText(), ctx)
 }
 
-func (v *FSHVisitor) VisitName(ctx grammar.INameContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitName(ctx grammar.INameContext) *ast.ParsedElement[string] {
 	return createParsedElement(ctx.GetText(), ctx)
 }
 
-func (v *FSHVisitor) VisitPath(ctx grammar.IPathContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitPath(ctx grammar.IPathContext) *ast.ParsedElement[string] {
 	return createParsedElement(ctx.GetText(), ctx)
 }
 
-func (v *FSHVisitor) VisitCaretPath(ctx grammar.ICaretPathContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitCaretPath(ctx grammar.ICaretPathContext) *ast.ParsedElement[string] {
 	deCareted := strings.TrimPrefix(ctx.GetText(), "^")
 	return createParsedElement(deCareted, ctx)
 }
 
 // VisitFlag updates flags with the flags found in the context.
-func (v *FSHVisitor) VisitFlag(ctx grammar.IFlagContext, flags *types.Flags) {
+func (v *FSHVisitor) VisitFlag(ctx grammar.IFlagContext, flags *ast.Flags) {
 	if ctx.KW_MOD() != nil {
 		flags.Modifier = createParsedElement(true, ctx)
 	} else if ctx.KW_MS() != nil {
@@ -981,18 +1015,18 @@ func (v *FSHVisitor) VisitFlag(ctx grammar.IFlagContext, flags *types.Flags) {
 	}
 }
 
-func (v *FSHVisitor) VisitStrength(ctx grammar.IStrengthContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitStrength(ctx grammar.IStrengthContext) *ast.ParsedElement[string] {
 	return createParsedElement(ctx.GetText(), ctx)
 }
 
-func (v *FSHVisitor) VisitValue(ctx grammar.IValueContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitValue(ctx grammar.IValueContext) *ast.ParsedElement[string] {
 	s := ctx.GetText()
 	s = trimQuotes(s)
 	return createParsedElement(s, ctx)
 }
 
-func (v *FSHVisitor) VisitItem(ctx grammar.IItemContext) *types.Item {
-	item := &types.Item{}
+func (v *FSHVisitor) VisitItem(ctx grammar.IItemContext) *ast.Item {
+	item := &ast.Item{}
 
 	names := ctx.AllName()
 	if len(names) >= 1 {
@@ -1004,7 +1038,7 @@ func (v *FSHVisitor) VisitItem(ctx grammar.IItemContext) *types.Item {
 
 	item.Cardinality = createCardinality(ctx.CARD().GetText(), ctx)
 
-	item.Flags = types.NewFlags()
+	item.Flags = ast.NewFlags()
 	for _, flag := range ctx.AllFlag() {
 		v.VisitFlag(flag, item.Flags)
 	}
@@ -1013,7 +1047,7 @@ func (v *FSHVisitor) VisitItem(ctx grammar.IItemContext) *types.Item {
 }
 
 // VisitCode updates component's CodePath and CodeString field with the values in ctx.
-func (v *FSHVisitor) VisitCode(ctx grammar.ICodeContext, component *types.ValueSetComponent) {
+func (v *FSHVisitor) VisitCode(ctx grammar.ICodeContext, component *ast.ValueSetComponent) {
 	if ctx.CODE() != nil {
 		component.CodePath = createParsedElement(ctx.CODE().GetText(), ctx)
 	}
@@ -1024,9 +1058,9 @@ func (v *FSHVisitor) VisitCode(ctx grammar.ICodeContext, component *types.ValueS
 	}
 }
 
-func (v *FSHVisitor) VisitConcept(ctx grammar.IConceptContext) (*types.Concept, []string) {
-	concept := &types.Concept{
-		SubConcepts: make([]*types.Concept, 0),
+func (v *FSHVisitor) VisitConcept(ctx grammar.IConceptContext) (*ast.Concept, []string) {
+	concept := &ast.Concept{
+		SubConcepts: make([]*ast.Concept, 0),
 	}
 	ancestorPath := make([]string, 0)
 
@@ -1071,15 +1105,15 @@ func (v *FSHVisitor) VisitReference(ctx grammar.IReferenceContext) interface{} {
 	return v.VisitChildren(ctx)
 }
 
-func (v *FSHVisitor) VisitReferenceType(ctx grammar.IReferenceTypeContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitReferenceType(ctx grammar.IReferenceTypeContext) *ast.ParsedElement[string] {
 	return createParsedElement(ctx.GetText(), ctx)
 }
 
-func (v *FSHVisitor) VisitCodeableReferenceType(ctx grammar.ICodeableReferenceTypeContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitCodeableReferenceType(ctx grammar.ICodeableReferenceTypeContext) *ast.ParsedElement[string] {
 	return createParsedElement(ctx.GetText(), ctx)
 }
 
-func (v *FSHVisitor) VisitCanonical(ctx grammar.ICanonicalContext) *types.ParsedElement[string] {
+func (v *FSHVisitor) VisitCanonical(ctx grammar.ICanonicalContext) *ast.ParsedElement[string] {
 	return createParsedElement(ctx.GetText(), ctx)
 }
 
@@ -1091,8 +1125,8 @@ func (v *FSHVisitor) VisitBool(ctx grammar.IBoolContext) interface{} {
 	return v.VisitChildren(ctx)
 }
 
-func (v *FSHVisitor) VisitTargetType(ctx grammar.ITargetTypeContext) *types.DataType {
-	dataType := &types.DataType{}
+func (v *FSHVisitor) VisitTargetType(ctx grammar.ITargetTypeContext) *ast.DataType {
+	dataType := &ast.DataType{}
 
 	if ctx.Name() != nil {
 		dataType.Name = v.VisitName(ctx.Name())
@@ -1124,29 +1158,39 @@ func trimQuotes(s string) string {
 
 // createParsedElement returns a ParsedElement with the given value and tags it
 // with the location found from ctx.GetStart() and ctx.GetEnd().
-func createParsedElement[T any](value T, ctx antlr.ParserRuleContext) *types.ParsedElement[T] {
-	loc := &types.Location{
-		Start: &types.Position{
+func createParsedElement[T any](value T, ctx antlr.ParserRuleContext) *ast.ParsedElement[T] {
+	loc := &ast.Location{
+		Start: &ast.Position{
 			LineNumber:   ctx.GetStart().GetLine(),
 			ColumnNumber: ctx.GetStart().GetColumn(),
 		},
-		End: &types.Position{
+		End: &ast.Position{
 			LineNumber:   ctx.GetStop().GetLine(),
 			ColumnNumber: ctx.GetStop().GetColumn(),
 		},
 	}
 
-	return &types.ParsedElement[T]{
+	return &ast.ParsedElement[T]{
 		Value:    value,
 		Location: loc,
 	}
 
 }
 
+// createParsedElementFromToken returns a ParsedElement with the text of the
+// given token, without surrounding whitespace, located where the text starts.
+// Tokens such as rule set references include the whitespace before them.
+func createParsedElementFromToken(token antlr.Token) *ast.ParsedElement[string] {
+	text := token.GetText()
+	trimmed := strings.TrimLeft(text, " \t")
+	column := token.GetColumn() + len(text) - len(trimmed)
+	return ast.NewParsedElement(strings.TrimSpace(trimmed), token.GetLine(), column, token.GetLine(), column)
+}
+
 // createCardinality returns a Cardinality from the given cardinality string. Card should be
 // in the format "min..max".
-func createCardinality(card string, ctx antlr.ParserRuleContext) *types.Cardinality {
-	cardinality := &types.Cardinality{}
+func createCardinality(card string, ctx antlr.ParserRuleContext) *ast.Cardinality {
+	cardinality := &ast.Cardinality{}
 
 	parts := strings.Split(card, "..")
 
@@ -1170,8 +1214,8 @@ func createCardinality(card string, ctx antlr.ParserRuleContext) *types.Cardinal
 }
 
 // createValueSetCodesSource returns a ValueSetCodesSource and populates it with the name and version if found.
-func createValueSetCodesSource(sourceName string, ctx antlr.ParserRuleContext) *types.ValueSetCodesSource {
-	codesSource := &types.ValueSetCodesSource{}
+func createValueSetCodesSource(sourceName string, ctx antlr.ParserRuleContext) *ast.ValueSetCodesSource {
+	codesSource := &ast.ValueSetCodesSource{}
 
 	parts := strings.Split(sourceName, "|")
 
@@ -0,0 +1,256 @@
+package fsh
+
+import (
+	"strings"
+
+	"github.com/verily-src/fsh-lint/ast"
+)
+
+// NodeKind is the kind of a node of a SyntaxTree.
+type NodeKind string
+
+const (
+	// NodeEntity is the declaration of an entity, such as "Profile: A", whose
+	// children are its metadata and rules.
+	NodeEntity NodeKind = "Entity"
+
+	// NodeMetadata is a metadata keyword of an entity and its value, such as
+	// "Id: a".
+	NodeMetadata NodeKind = "Metadata"
+
+	// NodeRule is a rule of an entity, from the "*" to the end of the rule.
+	NodeRule NodeKind = "Rule"
+
+	// NodeOther is content that is not part of an entity, which only occurs in
+	// FSH with syntax errors.
+	NodeOther NodeKind = "Other"
+)
+
+// entityKinds are the kinds of the tokens of entity keywords.
+var entityKinds = map[string]bool{
+	"KW_ALIAS":      true,
+	"KW_CODESYSTEM": true,
+	"KW_EXTENSION":  true,
+	"KW_INSTANCE":   true,
+	"KW_INVARIANT":  true,
+	"KW_LOGICAL":    true,
+	"KW_MAPPING":    true,
+	"KW_PROFILE":    true,
+	"KW_RESOURCE":   true,
+	"KW_RULESET":    true,
+	"KW_VALUESET":   true,
+}
+
+// metadataKinds are the kinds of the tokens of metadata keywords.
+var metadataKinds = map[string]bool{
+	"KW_CHARACTERISTICS": true,
+	"KW_CONTEXT":         true,
+	"KW_DESCRIPTION":     true,
+	"KW_EXPRESSION":      true,
+	"KW_ID":              true,
+	"KW_INSTANCEOF":      true,
+	"KW_PARENT":          true,
+	"KW_SEVERITY":        true,
+	"KW_SOURCE":          true,
+	"KW_TARGET":          true,
+	"KW_TITLE":           true,
+	"KW_USAGE":           true,
+	"KW_XPATH":           true,
+}
+
+// IsEntityKeyword returns true if the token kind is the keyword that declares
+// an entity, such as "KW_PROFILE".
+func IsEntityKeyword(kind string) bool {
+	return entityKinds[kind]
+}
+
+// IsMetadataKeyword returns true if the token kind is a metadata keyword of an
+// entity, such as "KW_ID".
+func IsMetadataKeyword(kind string) bool {
+	return metadataKinds[kind]
+}
+
+// Node is a node of a SyntaxTree: an entity declaration, or a statement of an
+// entity. Its text is the text of its Leading, Tokens, Trailing, and Children,
+// in that order.
+type Node struct {
+	Kind NodeKind
+
+	// Leading are the whitespace and comments on the lines before the node,
+	// since the end of the previous node.
+	Leading []*Token
+
+	// Tokens are the tokens of the node, from its keyword or "*" to its last
+	// token, including the whitespace and comments between them.
+	Tokens []*Token
+
+	// Trailing are the whitespace and comments after the last token of the
+	// node, up to and including the line ending.
+	Trailing []*Token
+
+	// Children are the metadata and rules of an entity.
+	Children []*Node
+}
+
+// Keyword returns the keyword of an entity or metadata node, such as "Profile"
+// for "Profile: A", or "" for other nodes.
+func (n *Node) Keyword() string {
+	if len(n.Tokens) == 0 || (n.Kind != NodeEntity && n.Kind != NodeMetadata) {
+		return ""
+	}
+	return strings.TrimSpace(strings.TrimSuffix(n.Tokens[0].Text, ":"))
+}
+
+// Name returns the name of an entity node, such as "A" for "Profile: A", or ""
+// for other nodes.
+func (n *Node) Name() string {
+	if n.Kind != NodeEntity {
+		return ""
+	}
+	for _, t := range n.Tokens[1:] {
+		if !isTrivia(t) {
+			return strings.TrimSpace(t.Text)
+		}
+	}
+	return ""
+}
+
+// Start returns the byte offset of the start of the node, including its leading
+// whitespace and comments.
+func (n *Node) Start() int {
+	if len(n.Leading) > 0 {
+		return n.Leading[0].Start
+	}
+	return n.Tokens[0].Start
+}
+
+// End returns the byte offset of the end of the node, including its trailing
+// whitespace and comments, and its children.
+func (n *Node) End() int {
+	if len(n.Children) > 0 {
+		return n.Children[len(n.Children)-1].End()
+	}
+	if len(n.Trailing) > 0 {
+		return n.Trailing[len(n.Trailing)-1].End
+	}
+	return n.Tokens[len(n.Tokens)-1].End
+}
+
+// String returns the source of the node.
+func (n *Node) String() string {
+	var b strings.Builder
+	n.write(&b)
+	return b.String()
+}
+
+// write writes the source of the node to b.
+func (n *Node) write(b *strings.Builder) {
+	for _, tokens := range [][]*Token{n.Leading, n.Tokens, n.Trailing} {
+		for _, t := range tokens {
+			b.WriteString(t.Text)
+		}
+	}
+	for _, child := range n.Children {
+		child.write(b)
+	}
+}
+
+// SyntaxTree is the lossless concrete syntax tree of FSH source, whose String
+// is the source, byte for byte. Unlike the ast.FSHDocument, it keeps the
+// whitespace and comments, which are attached to the nodes around them.
+type SyntaxTree struct {
+	// Document is the parsed FSH.
+	Document *ast.FSHDocument
+
+	// Nodes are the entities of the source, in order.
+	Nodes []*Node
+
+	// Trailing are the whitespace and comments after the last node.
+	Trailing []*Token
+}
+
+// String returns the source of the tree.
+func (t *SyntaxTree) String() string {
+	var b strings.Builder
+	for _, n := range t.Nodes {
+		n.write(&b)
+	}
+	for _, token := range t.Trailing {
+		b.WriteString(token.Text)
+	}
+	return b.String()
+}
+
+// ParseSyntaxTree parses FSH into a document and its syntax tree. Returns an
+// error like Parse if the FSH has syntax errors.
+func ParseSyntaxTree(fshData string) (*SyntaxTree, error) {
+	doc, err := Parse(fshData)
+	if err != nil {
+		return nil, err
+	}
+	tree := NewSyntaxTree(Tokens(fshData))
+	tree.Document = doc
+	return tree, nil
+}
+
+// NewSyntaxTree returns the syntax tree of the tokens, without a Document. The
+// tree is lossless even if the tokens have syntax errors.
+func NewSyntaxTree(tokens []*Token) *SyntaxTree {
+	tree := &SyntaxTree{}
+	var entity, node *Node
+	// open is true until the line ending after the last token of node.
+	open := false
+	var pending []*Token
+
+	for _, t := range tokens {
+		if isTrivia(t) {
+			if node != nil && open {
+				node.Trailing = append(node.Trailing, t)
+				open = t.Kind != TokenNewline
+			} else {
+				pending = append(pending, t)
+			}
+			continue
+		}
+
+		var kind NodeKind
+		switch {
+		case entityKinds[t.Kind]:
+			kind = NodeEntity
+		case metadataKinds[t.Kind]:
+			kind = NodeMetadata
+		case t.Kind == tokenStar:
+			kind = NodeRule
+		case node == nil:
+			kind = NodeOther
+		default:
+			// The node continues, such as on the lines after an "obeys" rule
+			// that does not fit on one line.
+			node.Tokens = append(append(append(node.Tokens, node.Trailing...), pending...), t)
+			node.Trailing, pending, open = nil, nil, true
+			continue
+		}
+
+		node = &Node{Kind: kind, Leading: pending, Tokens: []*Token{t}}
+		pending, open = nil, true
+		switch {
+		case kind == NodeEntity || kind == NodeOther || entity == nil:
+			entity = node
+			tree.Nodes = append(tree.Nodes, node)
+		default:
+			entity.Children = append(entity.Children, node)
+		}
+	}
+	tree.Trailing = pending
+	return tree
+}
+
+// isTrivia returns true if the token is whitespace or a comment between the
+// tokens of a statement, or between statements.
+func isTrivia(t *Token) bool {
+	switch t.Kind {
+	case TokenWhitespace, TokenNewline, TokenLineComment, TokenBlockComment:
+		return true
+	}
+	return false
+}
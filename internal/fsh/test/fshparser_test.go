@@ -5,8 +5,8 @@ import (
 	"encoding/json"
 	"testing"
 
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/internal/fsh"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
@@ -30,6 +30,9 @@ var InstanceWant string
 //go:embed resources/TestExtension_Want.json
 var ExtensionWant string
 
+//go:embed resources/TestRuleSetAndInvariant_Want.json
+var RuleSetAndInvariantWant string
+
 //go:embed resources/TestValueSet.fsh
 var ValueSetFSHData string
 
@@ -48,11 +51,14 @@ var InstanceFSHData string
 //go:embed resources/TestExtension.fsh
 var ExtensionFSHData string
 
+//go:embed resources/TestRuleSetAndInvariant.fsh
+var RuleSetAndInvariantFSHData string
+
 func TestParse(t *testing.T) {
 	tests := []struct {
 		name    string
 		fshData string
-		want    *types.FSHDocument
+		want    *ast.FSHDocument
 		wantErr error
 	}{
 		{
@@ -85,6 +91,11 @@ func TestParse(t *testing.T) {
 			fshData: ExtensionFSHData,
 			want:    parseDocJSON(ExtensionWant, t),
 		},
+		{
+			name:    "valid rule sets and invariant",
+			fshData: RuleSetAndInvariantFSHData,
+			want:    parseDocJSON(RuleSetAndInvariantWant, t),
+		},
 	}
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
@@ -100,8 +111,8 @@ func TestParse(t *testing.T) {
 	}
 }
 
-func parseDocJSON(jsonData string, t *testing.T) *types.FSHDocument {
-	var fd types.FSHDocument
+func parseDocJSON(jsonData string, t *testing.T) *ast.FSHDocument {
+	var fd ast.FSHDocument
 
 	err := json.Unmarshal([]byte(jsonData), &fd)
 	if err != nil {
@@ -0,0 +1,161 @@
+package fsh_test
+
+import (
+	"testing"
+
+	"github.com/verily-src/fsh-lint/internal/fsh"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+// node is the summary of a fsh.Node compared by the tests.
+type node struct {
+	Kind     fsh.NodeKind
+	Leading  string
+	Text     string
+	Trailing string
+	Children []*node
+}
+
+func summarize(n *fsh.Node) *node {
+	text := func(tokens []*fsh.Token) string {
+		var s string
+		for _, t := range tokens {
+			s += t.Text
+		}
+		return s
+	}
+	result := &node{Kind: n.Kind, Leading: text(n.Leading), Text: text(n.Tokens), Trailing: text(n.Trailing)}
+	for _, child := range n.Children {
+		result.Children = append(result.Children, summarize(child))
+	}
+	return result
+}
+
+func TestNewSyntaxTree(t *testing.T) {
+	tests := []struct {
+		name         string
+		fshData      string
+		want         []*node
+		wantTrailing string
+	}{
+		{
+			name:    "entities",
+			fshData: "Alias: $a = http://a\n\n// The profile.\nProfile: A // A.\nId: a\n\n* name 1..1\n  * given MS\n",
+			want: []*node{
+				{Kind: fsh.NodeEntity, Text: "Alias: $a = http://a", Trailing: "\n"},
+				{
+					Kind:     fsh.NodeEntity,
+					Leading:  "\n// The profile.\n",
+					Text:     "Profile: A",
+					Trailing: " // A.\n",
+					Children: []*node{
+						{Kind: fsh.NodeMetadata, Text: "Id: a", Trailing: "\n"},
+						{Kind: fsh.NodeRule, Leading: "\n", Text: "* name 1..1", Trailing: "\n"},
+						{Kind: fsh.NodeRule, Leading: "  ", Text: "* given MS", Trailing: "\n"},
+					},
+				},
+			},
+		},
+		{
+			name:    "comments before rules",
+			fshData: "Profile: A\n// Name.\n/* Required. */\n* name 1..1\n// End.\n",
+			want: []*node{
+				{
+					Kind:     fsh.NodeEntity,
+					Text:     "Profile: A",
+					Trailing: "\n",
+					Children: []*node{
+						{Kind: fsh.NodeRule, Leading: "// Name.\n/* Required. */\n", Text: "* name 1..1", Trailing: "\n"},
+					},
+				},
+			},
+			wantTrailing: "// End.\n",
+		},
+		{
+			name:    "CRLF line endings",
+			fshData: "Profile: A\r\nId: a\r\n\r\n* name 1..1\r\n",
+			want: []*node{
+				{
+					Kind:     fsh.NodeEntity,
+					Text:     "Profile: A",
+					Trailing: "\r\n",
+					Children: []*node{
+						{Kind: fsh.NodeMetadata, Text: "Id: a", Trailing: "\r\n"},
+						{Kind: fsh.NodeRule, Leading: "\r\n", Text: "* name 1..1", Trailing: "\r\n"},
+					},
+				},
+			},
+		},
+		{
+			name:    "statements over several lines",
+			fshData: "Profile: A\n* obeys\n    inv-1\n* name 1..1\n",
+			want: []*node{
+				{
+					Kind:     fsh.NodeEntity,
+					Text:     "Profile: A",
+					Trailing: "\n",
+					Children: []*node{
+						{Kind: fsh.NodeRule, Text: "* obeys\n    inv-1", Trailing: "\n"},
+						{Kind: fsh.NodeRule, Text: "* name 1..1", Trailing: "\n"},
+					},
+				},
+			},
+		},
+		{
+			name:    "syntax errors",
+			fshData: "oops\n* name 1..1\n",
+			want: []*node{
+				{
+					Kind:     fsh.NodeOther,
+					Text:     "oops",
+					Trailing: "\n",
+					Children: []*node{
+						{Kind: fsh.NodeRule, Text: "* name 1..1", Trailing: "\n"},
+					},
+				},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			tree := fsh.NewSyntaxTree(fsh.Tokens(tt.fshData))
+
+			var got []*node
+			for _, n := range tree.Nodes {
+				got = append(got, summarize(n))
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("NewSyntaxTree() mismatch (-want +got):\n%s", diff)
+			}
+			var trailing string
+			for _, token := range tree.Trailing {
+				trailing += token.Text
+			}
+			if trailing != tt.wantTrailing {
+				t.Errorf("NewSyntaxTree() trailing = %q, want %q", trailing, tt.wantTrailing)
+			}
+			if got := tree.String(); got != tt.fshData {
+				t.Errorf("NewSyntaxTree().String() = %q, want %q", got, tt.fshData)
+			}
+		})
+	}
+}
+
+func TestNode_Keyword(t *testing.T) {
+	tree, err := fsh.ParseSyntaxTree("Profile : A\nId: a\n* name 1..1\n")
+	if err != nil {
+		t.Fatal(err)
+	}
+	entity := tree.Nodes[0]
+	got := []string{entity.Keyword(), entity.Children[0].Keyword(), entity.Children[1].Keyword()}
+	if diff := cmp.Diff([]string{"Profile", "Id", ""}, got); diff != "" {
+		t.Errorf("Keyword() mismatch (-want +got):\n%s", diff)
+	}
+	if got, want := entity.Name(), "A"; got != want {
+		t.Errorf("Name() = %q, want %q", got, want)
+	}
+	if got, want := entity.End(), len("Profile : A\nId: a\n* name 1..1\n"); got != want {
+		t.Errorf("End() = %d, want %d", got, want)
+	}
+}
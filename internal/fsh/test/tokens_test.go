@@ -0,0 +1,61 @@
+package fsh_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/verily-src/fsh-lint/internal/fsh"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func TestTokens(t *testing.T) {
+	tests := []struct {
+		name    string
+		fshData string
+	}{
+		{name: "valueset", fshData: ValueSetFSHData},
+		{name: "profile", fshData: ProfileFSHData},
+		{name: "multiline profile", fshData: MultilineFSHProfileData},
+		{name: "codesystem", fshData: CodeSystemFSHData},
+		{name: "instance", fshData: InstanceFSHData},
+		{name: "extension", fshData: ExtensionFSHData},
+		{name: "ruleset and invariant", fshData: RuleSetAndInvariantFSHData},
+		{
+			name:    "comments and unicode",
+			fshData: "// ünïcode\r\nProfile: A /* block\n comment */\n// last",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var b strings.Builder
+			end := 0
+			for _, token := range fsh.Tokens(tt.fshData) {
+				if token.Start != end {
+					t.Errorf("Tokens() token %q starts at %d, want %d", token.Text, token.Start, end)
+				}
+				if got := tt.fshData[token.Start:token.End]; got != token.Text {
+					t.Errorf("Tokens() token text = %q, want %q", token.Text, got)
+				}
+				b.WriteString(token.Text)
+				end = token.End
+			}
+			if diff := cmp.Diff(tt.fshData, b.String()); diff != "" {
+				t.Errorf("Tokens() did not join back into the source (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestTokens_Comments(t *testing.T) {
+	var got []string
+	for _, token := range fsh.Tokens("/* a */ Profile: A // b\n") {
+		if token.Kind == fsh.TokenBlockComment || token.Kind == fsh.TokenLineComment || token.Kind == fsh.TokenNewline {
+			got = append(got, token.Kind+" "+token.Text)
+		}
+	}
+	want := []string{"BLOCK_COMMENT /* a */", "LINE_COMMENT // b", "NEWLINE \n"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Tokens() comments mismatch (-want +got):\n%s", diff)
+	}
+}
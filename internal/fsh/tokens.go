@@ -0,0 +1,158 @@
+package fsh
+
+import (
+	"strings"
+	"unicode/utf8"
+
+	"github.com/verily-src/fsh-lint/internal/fsh/internal/grammar"
+
+	"github.com/antlr4-go/antlr/v4"
+)
+
+// Kinds of tokens that are not named by the lexer grammar.
+const (
+	// TokenLineComment is a "//" comment, without its line ending.
+	TokenLineComment = "LINE_COMMENT"
+
+	// TokenBlockComment is a "/* */" comment.
+	TokenBlockComment = "BLOCK_COMMENT"
+
+	// TokenNewline is a line ending. Line endings inside lists are whitespace
+	// of the list instead, and those inside strings and comments are part of
+	// them.
+	TokenNewline = "NEWLINE"
+
+	// TokenUnknown is text that the lexer does not recognize.
+	TokenUnknown = "UNKNOWN"
+
+	// TokenWhitespace is whitespace other than a line ending.
+	TokenWhitespace = "WHITESPACE"
+
+	// tokenStar is the "*" that starts a rule.
+	tokenStar = "STAR"
+)
+
+// spaces are the whitespace characters other than line endings.
+const spaces = " \t\f\u00a0"
+
+// Token is a token of FSH source, including the whitespace and comments that
+// the parser ignores, so that the text of the tokens of a document joins back
+// into the document.
+type Token struct {
+	// Kind is the symbolic name of the token in the lexer grammar, such as
+	// "KW_PROFILE", "STAR", or "WHITESPACE", or one of the Token constants.
+	Kind string
+
+	Text string
+
+	// Start and End are the byte offsets of the token in the source.
+	Start, End int
+}
+
+// Tokens returns the tokens of the FSH source. Comments, which the lexer skips,
+// are returned as TokenLineComment and TokenBlockComment tokens. The "*" of a
+// rule is a STAR token of the "*" and the space after it, without the line
+// ending and indentation before it, which are separate tokens. A "\r\n" line
+// ending is a single TokenNewline token.
+func Tokens(fshData string) []*Token {
+	lexer := grammar.NewFSHLexer(antlr.NewInputStream(fshData))
+	lexer.RemoveErrorListeners()
+
+	var tokens []*Token
+	offset := 0
+	runeIndex := 0
+	// byteOffset returns the byte offset of the rune index, which only
+	// increases between calls.
+	byteOffset := func(index int) int {
+		for runeIndex < index && offset < len(fshData) {
+			_, size := utf8.DecodeRuneInString(fshData[offset:])
+			offset += size
+			runeIndex++
+		}
+		return offset
+	}
+
+	end := 0
+	for _, t := range lexer.GetAllTokens() {
+		start := byteOffset(t.GetStart())
+		if start > end {
+			tokens = append(tokens, skippedTokens(fshData[end:start], end)...)
+		}
+		end = byteOffset(t.GetStop() + 1)
+		kind := TokenUnknown
+		if t.GetTokenType() >= 0 && t.GetTokenType() < len(lexer.SymbolicNames) {
+			kind = lexer.SymbolicNames[t.GetTokenType()]
+		}
+		if kind == TokenWhitespace && strings.Trim(fshData[start:end], "\r\n") == "" {
+			kind = TokenNewline
+		}
+		if kind == tokenStar {
+			// The lexer includes the end of the previous line, and any comment
+			// on it, in the "*" of a rule.
+			star := strings.LastIndex(fshData[start:end], "*")
+			tokens = append(tokens, skippedTokens(fshData[start:start+star], start)...)
+			start += star
+		}
+		tokens = append(tokens, &Token{Kind: kind, Text: fshData[start:end], Start: start, End: end})
+	}
+	if end < len(fshData) {
+		tokens = append(tokens, skippedTokens(fshData[end:], end)...)
+	}
+	return joinLineEndings(tokens)
+}
+
+// joinLineEndings joins the "\r" and "\n" of each "\r\n" that the lexer split
+// into two tokens into one TokenNewline token.
+func joinLineEndings(tokens []*Token) []*Token {
+	var result []*Token
+	for _, t := range tokens {
+		if last := len(result) - 1; last >= 0 && result[last].Text == "\r" && t.Text == "\n" &&
+			result[last].Kind == TokenNewline && t.Kind == TokenNewline {
+			result[last] = &Token{Kind: TokenNewline, Text: "\r\n", Start: result[last].Start, End: t.End}
+			continue
+		}
+		result = append(result, t)
+	}
+	return result
+}
+
+// skippedTokens returns the tokens of text that the lexer skipped or combined
+// with a "*", starting at the given byte offset: comments, line endings, and
+// whitespace.
+func skippedTokens(text string, offset int) []*Token {
+	var tokens []*Token
+	add := func(kind string, n int) {
+		tokens = append(tokens, &Token{Kind: kind, Text: text[:n], Start: offset, End: offset + n})
+		text, offset = text[n:], offset+n
+	}
+	for text != "" {
+		switch {
+		case strings.HasPrefix(text, "/*"):
+			n := strings.Index(text[2:], "*/")
+			if n < 0 {
+				add(TokenUnknown, len(text))
+			} else {
+				add(TokenBlockComment, n+4)
+			}
+		case strings.HasPrefix(text, "//"):
+			n := strings.IndexAny(text, "\r\n")
+			if n < 0 {
+				n = len(text)
+			}
+			add(TokenLineComment, n)
+		case strings.HasPrefix(text, "\r\n"):
+			add(TokenNewline, 2)
+		case text[0] == '\r' || text[0] == '\n':
+			add(TokenNewline, 1)
+		case strings.TrimLeft(text, spaces) != text:
+			add(TokenWhitespace, len(text)-len(strings.TrimLeft(text, spaces)))
+		default:
+			n := strings.IndexAny(text, "/\r\n"+spaces)
+			if n <= 0 {
+				n = len(text)
+			}
+			add(TokenUnknown, n)
+		}
+	}
+	return tokens
+}
@@ -1,3 +0,0 @@
-// Package types contains the FHIR Shorthand (FSH) types that are used to represent FHIR data types in FSH.
-// The types are custom types that makes it easier to work with FSH data, and are not defined in the FSH grammar.
-package types
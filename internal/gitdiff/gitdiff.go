@@ -0,0 +1,167 @@
+// Package gitdiff finds the FSH files and lines changed relative to a git ref,
+// using the git command line.
+package gitdiff
+
+import (
+	"bufio"
+	"bytes"
+	"fmt"
+	"io"
+	"os/exec"
+	"path/filepath"
+	"regexp"
+	"sort"
+	"strconv"
+	"strings"
+)
+
+// pathspec limits the diff to FSH files in any directory.
+const pathspec = "*.fsh"
+
+// LineRange is an inclusive range of 1-based line numbers.
+type LineRange struct {
+	Start int
+	End   int
+}
+
+// Changes holds the files and the lines within them that were changed relative
+// to a git ref. Paths are slash-separated and relative to the working directory.
+type Changes struct {
+	// files maps each changed file to its changed line ranges. A nil slice
+	// means that the whole file is new.
+	files map[string][]LineRange
+}
+
+// Since returns the FSH files and lines changed in the working tree relative to
+// the given ref, including untracked files that are not ignored by git. Deleted
+// files are not included.
+func Since(ref string) (*Changes, error) {
+	diff, err := git("diff", "--no-color", "--no-ext-diff", "--relative", "--unified=0",
+		"--diff-filter=d", ref, "--", pathspec)
+	if err != nil {
+		return nil, err
+	}
+	changes, err := Parse(bytes.NewReader(diff))
+	if err != nil {
+		return nil, err
+	}
+
+	untracked, err := git("ls-files", "--others", "--exclude-standard", "--", pathspec)
+	if err != nil {
+		return nil, err
+	}
+	for _, file := range strings.Split(string(untracked), "\n") {
+		if file != "" {
+			changes.files[file] = nil
+		}
+	}
+	return changes, nil
+}
+
+// git runs git with the given arguments and returns its standard output.
+func git(args ...string) ([]byte, error) {
+	args = append([]string{"-c", "core.quotePath=false"}, args...)
+	var stderr bytes.Buffer
+	cmd := exec.Command("git", args...)
+	cmd.Stderr = &stderr
+	out, err := cmd.Output()
+	if err != nil {
+		return nil, fmt.Errorf("git %s: %w: %s", strings.Join(args[2:], " "), err, strings.TrimSpace(stderr.String()))
+	}
+	return out, nil
+}
+
+// hunkHeader matches the new file range of a unified diff hunk header, such as
+// "@@ -1,2 +3,4 @@". The line count is optional and defaults to 1.
+var hunkHeader = regexp.MustCompile(`^@@ -\d+(?:,\d+)? \+(\d+)(?:,(\d+))? @@`)
+
+// Parse parses the output of git diff with zero lines of context, returning the
+// line ranges added or modified in each file.
+func Parse(r io.Reader) (*Changes, error) {
+	changes := &Changes{files: make(map[string][]LineRange)}
+	scanner := bufio.NewScanner(r)
+	scanner.Buffer(make([]byte, 0, 64*1024), 1024*1024)
+
+	var file string
+	for scanner.Scan() {
+		line := scanner.Text()
+		switch {
+		case strings.HasPrefix(line, "+++ "):
+			file = strings.TrimPrefix(strings.TrimPrefix(line, "+++ "), "b/")
+			if file == "/dev/null" {
+				file = ""
+				continue
+			}
+			// A file is changed even if only lines were removed from it.
+			if _, ok := changes.files[file]; !ok {
+				changes.files[file] = []LineRange{}
+			}
+		case strings.HasPrefix(line, "@@ "):
+			if file == "" {
+				continue
+			}
+			match := hunkHeader.FindStringSubmatch(line)
+			if match == nil {
+				return nil, fmt.Errorf("invalid hunk header in diff of %s: %q", file, line)
+			}
+			start, _ := strconv.Atoi(match[1])
+			count := 1
+			if match[2] != "" {
+				count, _ = strconv.Atoi(match[2])
+			}
+			// A hunk that only removes lines has no new lines.
+			if count == 0 {
+				continue
+			}
+			changes.files[file] = append(changes.files[file], LineRange{Start: start, End: start + count - 1})
+		}
+	}
+	return changes, scanner.Err()
+}
+
+// Files returns the sorted paths of the changed files.
+func (c *Changes) Files() []string {
+	var files []string
+	for file := range c.files {
+		files = append(files, file)
+	}
+	sort.Strings(files)
+	return files
+}
+
+// HasFile returns true if the file at the given path was changed.
+func (c *Changes) HasFile(path string) bool {
+	_, ok := c.files[normalize(path)]
+	return ok
+}
+
+// Overlaps returns true if any line from start to end, inclusive, in the file
+// at the given path was changed.
+func (c *Changes) Overlaps(path string, start, end int) bool {
+	ranges, ok := c.files[normalize(path)]
+	if !ok {
+		return false
+	}
+	if ranges == nil {
+		return true
+	}
+	for _, r := range ranges {
+		if start <= r.End && r.Start <= end {
+			return true
+		}
+	}
+	return false
+}
+
+// normalize returns path as a clean, slash-separated path relative to the
+// working directory, to match the paths reported by git.
+func normalize(path string) string {
+	if filepath.IsAbs(path) {
+		if abs, err := filepath.Abs("."); err == nil {
+			if rel, err := filepath.Rel(abs, path); err == nil {
+				path = rel
+			}
+		}
+	}
+	return filepath.ToSlash(filepath.Clean(path))
+}
@@ -0,0 +1,189 @@
+package gitdiff_test
+
+import (
+	"os"
+	"os/exec"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/gitdiff"
+)
+
+const diff = `diff --git a/input/fsh/Profile.fsh b/input/fsh/Profile.fsh
+index 1111111..2222222 100644
+--- a/input/fsh/Profile.fsh
++++ b/input/fsh/Profile.fsh
+@@ -2 +2 @@ Profile: Example
+-Id: old
++Id: new
+@@ -10,0 +11,3 @@ Description: "Example"
++* ^status = #active
++* ^abstract = false
++* ^version = "1.0.0"
+diff --git a/input/fsh/Removed.fsh b/input/fsh/Removed.fsh
+index 3333333..4444444 100644
+--- a/input/fsh/Removed.fsh
++++ b/input/fsh/Removed.fsh
+@@ -4,2 +3,0 @@ ValueSet: Example
+-* include codes from system Example
+-* exclude codes from system Other
+diff --git a/New.fsh b/New.fsh
+new file mode 100644
+index 0000000..5555555
+--- /dev/null
++++ b/New.fsh
+@@ -0,0 +1,2 @@
++CodeSystem: New
++Id: new
+`
+
+func TestParse(t *testing.T) {
+	changes, err := gitdiff.Parse(strings.NewReader(diff))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []string{"New.fsh", "input/fsh/Profile.fsh", "input/fsh/Removed.fsh"}
+	if diff := cmp.Diff(want, changes.Files()); diff != "" {
+		t.Errorf("Files() mismatch (-want +got):\n%s", diff)
+	}
+
+	testCases := []struct {
+		name       string
+		path       string
+		start, end int
+		want       bool
+	}{
+		{
+			name:  "single modified line",
+			path:  "input/fsh/Profile.fsh",
+			start: 2,
+			end:   2,
+			want:  true,
+		},
+		{
+			name:  "unchanged line",
+			path:  "input/fsh/Profile.fsh",
+			start: 5,
+			end:   5,
+			want:  false,
+		},
+		{
+			name:  "range overlapping an added hunk",
+			path:  "input/fsh/Profile.fsh",
+			start: 8,
+			end:   11,
+			want:  true,
+		},
+		{
+			name:  "line after an added hunk",
+			path:  "input/fsh/Profile.fsh",
+			start: 14,
+			end:   14,
+			want:  false,
+		},
+		{
+			name:  "file with only removed lines",
+			path:  "input/fsh/Removed.fsh",
+			start: 3,
+			end:   3,
+			want:  false,
+		},
+		{
+			name:  "new file",
+			path:  "./New.fsh",
+			start: 1,
+			end:   1,
+			want:  true,
+		},
+		{
+			name:  "unchanged file",
+			path:  "Other.fsh",
+			start: 1,
+			end:   1,
+			want:  false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := changes.Overlaps(tc.path, tc.start, tc.end)
+
+			if got != tc.want {
+				t.Errorf("Overlaps(%q, %d, %d) = %t, want %t", tc.path, tc.start, tc.end, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestSince(t *testing.T) {
+	if _, err := exec.LookPath("git"); err != nil {
+		t.Skip("git is not installed")
+	}
+
+	dir := t.TempDir()
+	wd, err := os.Getwd()
+	if err != nil {
+		t.Fatal(err)
+	}
+	if err := os.Chdir(dir); err != nil {
+		t.Fatal(err)
+	}
+	t.Cleanup(func() { _ = os.Chdir(wd) })
+
+	run := func(args ...string) {
+		t.Helper()
+		cmd := exec.Command("git", args...)
+		cmd.Env = append(os.Environ(),
+			"GIT_AUTHOR_NAME=test", "GIT_AUTHOR_EMAIL=test@example.com",
+			"GIT_COMMITTER_NAME=test", "GIT_COMMITTER_EMAIL=test@example.com")
+		if out, err := cmd.CombinedOutput(); err != nil {
+			t.Fatalf("git %s: %v: %s", strings.Join(args, " "), err, out)
+		}
+	}
+	write := func(path, content string) {
+		t.Helper()
+		if err := os.MkdirAll(filepath.Dir(path), 0755); err != nil {
+			t.Fatal(err)
+		}
+		if err := os.WriteFile(path, []byte(content), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	run("init", "--quiet")
+	write("input/Changed.fsh", "Profile: A\nId: a\n")
+	write("input/Unchanged.fsh", "Profile: B\nId: b\n")
+	write("input/Deleted.fsh", "Profile: C\nId: c\n")
+	run("add", "-A")
+	run("commit", "--quiet", "-m", "initial")
+
+	write("input/Changed.fsh", "Profile: A\nId: changed\n")
+	write("input/Untracked.fsh", "Profile: D\nId: d\n")
+	write("README.md", "not FSH\n")
+	if err := os.Remove("input/Deleted.fsh"); err != nil {
+		t.Fatal(err)
+	}
+
+	changes, err := gitdiff.Since("HEAD")
+	if err != nil {
+		t.Fatalf("Since() error = %v", err)
+	}
+
+	want := []string{"input/Changed.fsh", "input/Untracked.fsh"}
+	if diff := cmp.Diff(want, changes.Files()); diff != "" {
+		t.Errorf("Files() mismatch (-want +got):\n%s", diff)
+	}
+	if changes.Overlaps("input/Changed.fsh", 1, 1) {
+		t.Errorf("Overlaps(%q, 1, 1) = true, want false", "input/Changed.fsh")
+	}
+	if !changes.Overlaps("input/Changed.fsh", 2, 2) {
+		t.Errorf("Overlaps(%q, 2, 2) = false, want true", "input/Changed.fsh")
+	}
+
+	if _, err := gitdiff.Since("does-not-exist"); err == nil {
+		t.Errorf("Since() with unknown ref: got nil error, want error")
+	}
+}
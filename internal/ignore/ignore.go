@@ -0,0 +1,197 @@
+// Package ignore implements matching of paths against ignore patterns that
+// follow the semantics of .gitignore files.
+// See: https://git-scm.com/docs/gitignore#_pattern_format
+package ignore
+
+import (
+	"bufio"
+	"io"
+	"os"
+	"path"
+	"path/filepath"
+	"strings"
+
+	"github.com/bmatcuk/doublestar/v4"
+)
+
+// FileName is the name of the ignore file read from the project root.
+const FileName = ".fshlintignore"
+
+// pattern is a single parsed ignore pattern.
+type pattern struct {
+	// glob is the doublestar glob that the pattern matches.
+	glob string
+
+	// negate indicates that the pattern re-includes paths excluded by earlier
+	// patterns.
+	negate bool
+
+	// dirOnly indicates that the pattern only matches directories.
+	dirOnly bool
+}
+
+// Matcher matches slash-separated paths, relative to its root, against a list
+// of ignore patterns. The zero value ignores nothing.
+type Matcher struct {
+	// Root is the directory that the patterns are relative to.
+	Root string
+
+	patterns []*pattern
+}
+
+// New returns a Matcher for the given patterns, relative to root.
+func New(root string, patterns ...string) *Matcher {
+	m := &Matcher{Root: root}
+	m.Add(patterns...)
+	return m
+}
+
+// Load reads the ignore file at the given path, returning a Matcher relative to
+// the directory of the file. If the file does not exist, an empty Matcher is
+// returned.
+func Load(file string) (*Matcher, error) {
+	m := &Matcher{Root: filepath.Dir(file)}
+	f, err := os.Open(file)
+	if os.IsNotExist(err) {
+		return m, nil
+	}
+	if err != nil {
+		return nil, err
+	}
+	defer f.Close()
+
+	patterns, err := Parse(f)
+	if err != nil {
+		return nil, err
+	}
+	m.Add(patterns...)
+	return m, nil
+}
+
+// Parse reads the non-empty, non-comment lines from r.
+func Parse(r io.Reader) ([]string, error) {
+	var patterns []string
+	scanner := bufio.NewScanner(r)
+	for scanner.Scan() {
+		line := strings.TrimRight(scanner.Text(), " \t\r")
+		if line == "" || strings.HasPrefix(line, "#") {
+			continue
+		}
+		patterns = append(patterns, line)
+	}
+	return patterns, scanner.Err()
+}
+
+// Add adds the given patterns to the matcher. Later patterns take precedence
+// over earlier ones.
+func (m *Matcher) Add(patterns ...string) {
+	for _, p := range patterns {
+		if parsed := parsePattern(p); parsed != nil {
+			m.patterns = append(m.patterns, parsed)
+		}
+	}
+}
+
+// parsePattern parses a single gitignore pattern, returning nil if it matches
+// nothing.
+func parsePattern(p string) *pattern {
+	result := &pattern{}
+	if strings.HasPrefix(p, "!") {
+		result.negate = true
+		p = p[1:]
+	} else if strings.HasPrefix(p, `\!`) || strings.HasPrefix(p, `\#`) {
+		p = p[1:]
+	}
+
+	if strings.HasSuffix(p, "/") {
+		result.dirOnly = true
+		p = strings.TrimSuffix(p, "/")
+	}
+	if p == "" {
+		return nil
+	}
+
+	// A pattern with a separator at the beginning or middle is relative to the
+	// root, otherwise it may match at any level below the root.
+	if strings.Contains(p, "/") {
+		p = strings.TrimPrefix(p, "/")
+	} else {
+		p = "**/" + p
+	}
+	result.glob = p
+	return result
+}
+
+// Ignored returns true if the given path, relative to the matcher's root or
+// absolute, is ignored. Paths within an ignored directory are also ignored.
+func (m *Matcher) Ignored(p string, isDir bool) bool {
+	if m == nil || len(m.patterns) == 0 {
+		return false
+	}
+	rel, ok := m.relative(p)
+	if !ok {
+		return false
+	}
+
+	// Check each parent directory first, since ignoring a directory ignores
+	// everything inside of it.
+	parts := strings.Split(rel, "/")
+	for i := 1; i < len(parts); i++ {
+		if m.match(path.Join(parts[:i]...), true) {
+			return true
+		}
+	}
+	return m.match(rel, isDir)
+}
+
+// match returns true if the last pattern that matches p is not negated.
+func (m *Matcher) match(p string, isDir bool) bool {
+	ignored := false
+	for _, pattern := range m.patterns {
+		if pattern.dirOnly && !isDir {
+			continue
+		}
+		if ok, _ := doublestar.Match(pattern.glob, p); ok {
+			ignored = !pattern.negate
+		}
+	}
+	return ignored
+}
+
+// relative returns p as a slash-separated path relative to the matcher's root.
+// Returns false if p is outside of the root.
+func (m *Matcher) relative(p string) (string, bool) {
+	root := m.Root
+	if root == "" {
+		root = "."
+	}
+	if filepath.IsAbs(p) != filepath.IsAbs(root) {
+		abs, err := filepath.Abs(p)
+		if err != nil {
+			return "", false
+		}
+		absRoot, err := filepath.Abs(root)
+		if err != nil {
+			return "", false
+		}
+		p, root = abs, absRoot
+	}
+	rel, err := filepath.Rel(root, p)
+	if err != nil || rel == ".." || strings.HasPrefix(rel, ".."+string(filepath.Separator)) {
+		return "", false
+	}
+	return filepath.ToSlash(rel), true
+}
+
+// Matchers is a list of matchers, each with its own root.
+type Matchers []*Matcher
+
+// Ignored returns true if any of the matchers ignores the given path.
+func (ms Matchers) Ignored(p string, isDir bool) bool {
+	for _, m := range ms {
+		if m.Ignored(p, isDir) {
+			return true
+		}
+	}
+	return false
+}
@@ -0,0 +1,139 @@
+package ignore_test
+
+import (
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/ignore"
+)
+
+func TestMatcher_Ignored(t *testing.T) {
+	testCases := []struct {
+		name     string
+		patterns []string
+		path     string
+		isDir    bool
+		want     bool
+	}{
+		{
+			name:     "no patterns",
+			patterns: nil,
+			path:     "input/fsh/Profile.fsh",
+			want:     false,
+		},
+		{
+			name:     "pattern without separator matches at any level",
+			patterns: []string{"*.generated.fsh"},
+			path:     "input/fsh/Profile.generated.fsh",
+			want:     true,
+		},
+		{
+			name:     "pattern with separator is anchored to the root",
+			patterns: []string{"fsh/*.fsh"},
+			path:     "input/fsh/Profile.fsh",
+			want:     false,
+		},
+		{
+			name:     "leading separator is anchored to the root",
+			patterns: []string{"/input/fsh/Profile.fsh"},
+			path:     "input/fsh/Profile.fsh",
+			want:     true,
+		},
+		{
+			name:     "ignored directory ignores its files",
+			patterns: []string{"examples/"},
+			path:     "input/examples/Example.fsh",
+			want:     true,
+		},
+		{
+			name:     "directory only pattern does not match files",
+			patterns: []string{"examples/"},
+			path:     "input/examples",
+			isDir:    false,
+			want:     false,
+		},
+		{
+			name:     "negated pattern re-includes a file",
+			patterns: []string{"*.fsh", "!Keep.fsh"},
+			path:     "input/Keep.fsh",
+			want:     false,
+		},
+		{
+			name:     "later pattern takes precedence",
+			patterns: []string{"!Keep.fsh", "*.fsh"},
+			path:     "input/Keep.fsh",
+			want:     true,
+		},
+		{
+			name:     "escaped exclamation mark matches literally",
+			patterns: []string{`\!Important.fsh`},
+			path:     "!Important.fsh",
+			want:     true,
+		},
+		{
+			name:     "doublestar matches any number of directories",
+			patterns: []string{"input/**/drafts"},
+			path:     "input/fsh/profiles/drafts/Draft.fsh",
+			want:     true,
+		},
+		{
+			name:     "path outside of the root is not ignored",
+			patterns: []string{"*.fsh"},
+			path:     "../Profile.fsh",
+			want:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			m := ignore.New(".", tc.patterns...)
+
+			got := m.Ignored(tc.path, tc.isDir)
+
+			if got != tc.want {
+				t.Errorf("Ignored(%q, %t) = %t, want %t", tc.path, tc.isDir, got, tc.want)
+			}
+		})
+	}
+}
+
+func TestMatcher_Ignored_RelativeToRoot(t *testing.T) {
+	m := ignore.New("input", "/fsh/Profile.fsh")
+
+	if !m.Ignored("input/fsh/Profile.fsh", false) {
+		t.Errorf("Ignored(%q) = false, want true", "input/fsh/Profile.fsh")
+	}
+	if m.Ignored("fsh/Profile.fsh", false) {
+		t.Errorf("Ignored(%q) = true, want false", "fsh/Profile.fsh")
+	}
+}
+
+func TestMatchers_Ignored(t *testing.T) {
+	ms := ignore.Matchers{ignore.New(".", "a.fsh"), nil, ignore.New("sub", "b.fsh")}
+
+	got := []bool{
+		ms.Ignored("a.fsh", false),
+		ms.Ignored("sub/b.fsh", false),
+		ms.Ignored("b.fsh", false),
+	}
+
+	want := []bool{true, true, false}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Ignored() mismatch (-want +got):\n%s", diff)
+	}
+}
+
+func TestParse(t *testing.T) {
+	input := "# comment\n\n*.generated.fsh  \n!Keep.fsh\r\n"
+
+	got, err := ignore.Parse(strings.NewReader(input))
+	if err != nil {
+		t.Fatalf("Parse() error = %v", err)
+	}
+
+	want := []string{"*.generated.fsh", "!Keep.fsh"}
+	if diff := cmp.Diff(want, got); diff != "" {
+		t.Errorf("Parse() mismatch (-want +got):\n%s", diff)
+	}
+}
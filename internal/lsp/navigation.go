@@ -0,0 +1,217 @@
+package lsp
+
+import (
+	"io/fs"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/verily-src/fsh-lint/ast"
+	"github.com/verily-src/fsh-lint/internal/fsh"
+	"github.com/verily-src/fsh-lint/internal/symbols"
+)
+
+// symbolKinds are the LSP kinds of the kinds of symbols.
+var symbolKinds = map[symbols.Kind]SymbolKind{
+	symbols.KindCodeSystem: SymbolKindEnum,
+	symbols.KindExtension:  SymbolKindClass,
+	symbols.KindInstance:   SymbolKindObject,
+	symbols.KindInvariant:  SymbolKindConstant,
+	symbols.KindProfile:    SymbolKindClass,
+	symbols.KindRuleSet:    SymbolKindModule,
+	symbols.KindValueSet:   SymbolKindEnum,
+}
+
+// indexWorkspace indexes the FSH files in the root directory, skipping hidden
+// directories, such as .git, and files that cannot be parsed.
+func (s *Server) indexWorkspace(root string) {
+	err := filepath.WalkDir(root, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return nil
+		}
+		if d.IsDir() {
+			if path != root && strings.HasPrefix(d.Name(), ".") {
+				return filepath.SkipDir
+			}
+			return nil
+		}
+		if filepath.Ext(path) == ".fsh" {
+			s.indexFile(path)
+		}
+		return nil
+	})
+	if err != nil {
+		s.Logger.Printf("Error indexing %s: %v", root, err)
+	}
+}
+
+// indexFile indexes the FSH file at the given path as saved, or removes it from
+// the index if it no longer exists.
+func (s *Server) indexFile(path string) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		s.index.Remove(path)
+		return
+	}
+	s.indexText(path, string(data))
+}
+
+// indexDocument indexes the current text of an open document.
+func (s *Server) indexDocument(doc *document) {
+	s.indexText(doc.path, doc.text)
+}
+
+// indexText indexes the text as the contents of the file at the given path. If
+// the text cannot be parsed, the previous symbols of the file are kept, so that
+// navigation keeps working while a change is being typed.
+func (s *Server) indexText(path, text string) {
+	parsed, err := fsh.Parse(text)
+	if err != nil {
+		return
+	}
+	s.index.Add(path, parsed)
+}
+
+// documentSymbols returns the entities defined in a document.
+func (s *Server) documentSymbols(params *DocumentSymbolParams) []*DocumentSymbol {
+	result := []*DocumentSymbol{}
+	for _, sym := range s.index.Symbols(uriToPath(params.TextDocument.URI)) {
+		r := s.nameRange(sym.Path, sym.Name, sym.Location)
+		detail := string(sym.Kind)
+		if sym.ID != "" {
+			detail += " " + sym.ID
+		}
+		result = append(result, &DocumentSymbol{
+			Name:           sym.Name,
+			Detail:         detail,
+			Kind:           symbolKinds[sym.Kind],
+			Range:          r,
+			SelectionRange: r,
+		})
+	}
+	return result
+}
+
+// definition returns the definitions of the entity referenced at the position.
+// If the position is not on a reference, such as on an alias or a name in a
+// rule that is not indexed, the word at the position is looked up instead.
+func (s *Server) definition(params *TextDocumentPositionParams) []*Location {
+	result := []*Location{}
+	path := uriToPath(params.TextDocument.URI)
+	word := s.wordAt(params.TextDocument.URI, params.Position)
+	if word == "" {
+		return result
+	}
+
+	var definitions []*symbols.Symbol
+	for _, ref := range s.index.ReferencesAt(path, params.Position.Line+1) {
+		if ref.Name == word {
+			definitions = s.index.Resolve(ref)
+			break
+		}
+	}
+	if definitions == nil {
+		definitions = s.index.Lookup(word)
+	}
+	for _, sym := range definitions {
+		result = append(result, &Location{URI: s.uriOf(sym.Path), Range: s.nameRange(sym.Path, sym.Name, sym.Location)})
+	}
+	return result
+}
+
+// references returns the references to the entity named at the position.
+func (s *Server) references(params *ReferenceParams) []*Location {
+	result := []*Location{}
+	word := s.wordAt(params.TextDocument.URI, params.Position)
+	if word == "" {
+		return result
+	}
+
+	if params.Context.IncludeDeclaration {
+		for _, sym := range s.index.Lookup(word) {
+			result = append(result, &Location{URI: s.uriOf(sym.Path), Range: s.nameRange(sym.Path, sym.Name, sym.Location)})
+		}
+	}
+	for _, ref := range s.index.References(word) {
+		result = append(result, &Location{URI: s.uriOf(ref.Path), Range: s.nameRange(ref.Path, ref.Name, ref.Location)})
+	}
+	return result
+}
+
+// wordAt returns the FSH name at the position of an open document, or an empty
+// string if there is none.
+func (s *Server) wordAt(uri string, position Position) string {
+	doc, ok := s.docs[uri]
+	if !ok {
+		return ""
+	}
+	lines := strings.Split(doc.text, "\n")
+	if position.Line < 0 || position.Line >= len(lines) {
+		return ""
+	}
+	line := lines[position.Line]
+
+	// Convert the UTF-16 character offset to a byte offset.
+	offset, units := 0, 0
+	for offset < len(line) && units < position.Character {
+		r, size := utf8.DecodeRuneInString(line[offset:])
+		offset += size
+		units += len(utf16.Encode([]rune{r}))
+	}
+
+	start := offset
+	for start > 0 && isNameByte(line[start-1]) {
+		start--
+	}
+	end := offset
+	for end < len(line) && isNameByte(line[end]) {
+		end++
+	}
+	return line[start:end]
+}
+
+// isNameByte returns true if the byte can be part of a FSH name or ID.
+func isNameByte(b byte) bool {
+	return b >= 0x80 || !strings.ContainsRune(" \t\r\n()[],:=*#^\"'{}<>|", rune(b))
+}
+
+// nameRange returns the range of a name at the given location of the file at
+// the given path. Some locations start at the keyword before the name, such as
+// Parent, so the name is searched for on the line if the document is open.
+func (s *Server) nameRange(path, name string, location *ast.Location) Range {
+	line := location.Start.LineNumber - 1
+	column := location.Start.ColumnNumber
+	for _, doc := range s.docs {
+		if doc.path != path {
+			continue
+		}
+		lines := strings.Split(doc.text, "\n")
+		if line < len(lines) {
+			text := lines[line]
+			// Columns count characters, while strings are indexed by bytes.
+			from := len(string([]rune(text)[:min(column, utf8.RuneCountInString(text))]))
+			if i := strings.Index(text[from:], name); i >= 0 {
+				column = utf16Len(text[:from+i])
+			}
+		}
+		break
+	}
+	return Range{Start: Position{Line: line, Character: column}, End: Position{Line: line, Character: column + utf16Len(name)}}
+}
+
+// uriOf returns the URI of the file at the given path, preferring the URI of
+// the open document, if any.
+func (s *Server) uriOf(path string) string {
+	for _, doc := range s.docs {
+		if doc.path == path {
+			return doc.uri
+		}
+	}
+	if !filepath.IsAbs(path) {
+		return path
+	}
+	return (&url.URL{Scheme: "file", Path: filepath.ToSlash(path)}).String()
+}
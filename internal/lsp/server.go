@@ -0,0 +1,515 @@
+// Package lsp implements a Language Server Protocol server over stdio, which
+// publishes the problems found by the linter as diagnostics, offers quick fixes
+// for fixable problems, describes rules on hover, and navigates between the
+// entities of the workspace.
+package lsp
+
+import (
+	"encoding/json"
+	"errors"
+	"fmt"
+	"io"
+	"log"
+	"net/url"
+	"path/filepath"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode/utf16"
+	"unicode/utf8"
+
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic"
+	"github.com/verily-src/fsh-lint/internal/symbols"
+	"github.com/verily-src/fsh-lint/lint"
+)
+
+// source is the source of the diagnostics published by the server.
+const source = "fsh-lint"
+
+// document is a document opened in the editor.
+type document struct {
+	uri     string
+	path    string
+	version int
+	text    string
+
+	// problems are the problems found in the current text of the document.
+	problems []*lint.Problem
+}
+
+// Server is a Language Server Protocol server backed by a linter.
+type Server struct {
+	linter  *lint.Linter
+	version string
+
+	conn        *conn
+	docs        map[string]*document
+	index       *symbols.Index
+	initialized bool
+	shutdown    bool
+
+	// Logger logs errors that cannot be sent to the client. Defaults to the
+	// standard logger.
+	Logger *log.Logger
+}
+
+// NewServer returns a server that lints documents with the given linter. The
+// version is reported to the client.
+func NewServer(linter *lint.Linter, version string) *Server {
+	return &Server{
+		linter:  linter,
+		version: version,
+		docs:    make(map[string]*document),
+		index:   symbols.NewIndex(),
+		Logger:  log.Default(),
+	}
+}
+
+// Serve reads requests from r and writes responses and notifications to w
+// until the client sends the exit notification or closes r. Returns an error if
+// the client exits without shutting down the server first.
+func (s *Server) Serve(r io.Reader, w io.Writer) error {
+	s.conn = newConn(r, w)
+	for {
+		msg, err := s.conn.read()
+		if errors.Is(err, io.EOF) {
+			return nil
+		}
+		var respErr *responseError
+		if errors.As(err, &respErr) {
+			_ = s.conn.reply(nil, nil, respErr)
+			continue
+		}
+		if err != nil {
+			return err
+		}
+
+		if msg.Method == "exit" {
+			if !s.shutdown {
+				return fmt.Errorf("exit before shutdown")
+			}
+			return nil
+		}
+		s.handle(msg)
+	}
+}
+
+// handle dispatches a single request or notification.
+func (s *Server) handle(msg *message) {
+	isRequest := msg.ID != nil
+	if !s.initialized && msg.Method != "initialize" {
+		if isRequest {
+			s.reply(msg, nil, &responseError{Code: codeServerNotInitialized, Message: "server not initialized"})
+		}
+		return
+	}
+
+	var result any
+	var err error
+	switch msg.Method {
+	case "initialize":
+		var params InitializeParams
+		if err = unmarshalParams(msg, &params); err == nil {
+			result = s.initialize(&params)
+		}
+	case "initialized":
+	case "shutdown":
+		s.shutdown = true
+	case "textDocument/didOpen":
+		var params DidOpenTextDocumentParams
+		if err = unmarshalParams(msg, &params); err == nil {
+			doc := &document{
+				uri:     params.TextDocument.URI,
+				path:    uriToPath(params.TextDocument.URI),
+				version: params.TextDocument.Version,
+				text:    params.TextDocument.Text,
+			}
+			s.docs[doc.uri] = doc
+			s.lint(doc)
+			s.indexDocument(doc)
+		}
+	case "textDocument/didChange":
+		var params DidChangeTextDocumentParams
+		if err = unmarshalParams(msg, &params); err == nil {
+			if doc, ok := s.docs[params.TextDocument.URI]; ok && len(params.ContentChanges) > 0 {
+				doc.version = params.TextDocument.Version
+				doc.text = params.ContentChanges[len(params.ContentChanges)-1].Text
+				s.lint(doc)
+				s.indexDocument(doc)
+			}
+		}
+	case "textDocument/didSave":
+		var params DidSaveTextDocumentParams
+		if err = unmarshalParams(msg, &params); err == nil {
+			if doc, ok := s.docs[params.TextDocument.URI]; ok {
+				if params.Text != nil {
+					doc.text = *params.Text
+				}
+				s.lint(doc)
+				s.indexDocument(doc)
+			}
+		}
+	case "textDocument/didClose":
+		var params DidCloseTextDocumentParams
+		if err = unmarshalParams(msg, &params); err == nil {
+			delete(s.docs, params.TextDocument.URI)
+			// Unsaved changes are discarded, so index the file as saved.
+			s.indexFile(uriToPath(params.TextDocument.URI))
+			s.publish(&PublishDiagnosticsParams{URI: params.TextDocument.URI, Diagnostics: []*Diagnostic{}})
+		}
+	case "textDocument/codeAction":
+		var params CodeActionParams
+		if err = unmarshalParams(msg, &params); err == nil {
+			result = s.codeActions(&params)
+		}
+	case "textDocument/hover":
+		var params TextDocumentPositionParams
+		if err = unmarshalParams(msg, &params); err == nil {
+			if hover := s.hover(&params); hover != nil {
+				result = hover
+			}
+		}
+	case "textDocument/documentSymbol":
+		var params DocumentSymbolParams
+		if err = unmarshalParams(msg, &params); err == nil {
+			result = s.documentSymbols(&params)
+		}
+	case "textDocument/definition":
+		var params TextDocumentPositionParams
+		if err = unmarshalParams(msg, &params); err == nil {
+			result = s.definition(&params)
+		}
+	case "textDocument/references":
+		var params ReferenceParams
+		if err = unmarshalParams(msg, &params); err == nil {
+			result = s.references(&params)
+		}
+	default:
+		if isRequest {
+			err = &responseError{Code: codeMethodNotFound, Message: fmt.Sprintf("method not found: %s", msg.Method)}
+		}
+	}
+
+	if isRequest {
+		s.reply(msg, result, err)
+	} else if err != nil {
+		s.Logger.Printf("Error handling %s: %v", msg.Method, err)
+	}
+}
+
+// reply sends the response to the given request.
+func (s *Server) reply(msg *message, result any, err error) {
+	if err := s.conn.reply(msg.ID, result, err); err != nil {
+		s.Logger.Printf("Error replying to %s: %v", msg.Method, err)
+	}
+}
+
+// unmarshalParams unmarshals the params of msg into v.
+func unmarshalParams(msg *message, v any) error {
+	if err := json.Unmarshal(msg.Params, v); err != nil {
+		return &responseError{Code: codeInvalidParams, Message: err.Error()}
+	}
+	return nil
+}
+
+// initialize indexes the workspace, and returns the capabilities of the
+// server.
+func (s *Server) initialize(params *InitializeParams) *InitializeResult {
+	s.initialized = true
+	roots := []string{params.RootURI}
+	for _, folder := range params.WorkspaceFolders {
+		roots = append(roots, folder.URI)
+	}
+	for _, root := range roots {
+		if root != "" {
+			s.indexWorkspace(uriToPath(root))
+		}
+	}
+
+	return &InitializeResult{
+		Capabilities: ServerCapabilities{
+			TextDocumentSync: TextDocumentSyncOptions{
+				OpenClose: true,
+				Change:    textDocumentSyncFull,
+				Save:      SaveOptions{IncludeText: true},
+			},
+			CodeActionProvider: CodeActionOptions{CodeActionKinds: []string{codeActionKindQuickFix}},
+			HoverProvider:      true,
+
+			DefinitionProvider:     true,
+			ReferencesProvider:     true,
+			DocumentSymbolProvider: true,
+		},
+		ServerInfo: ServerInfo{Name: source, Version: s.version},
+	}
+}
+
+// lint lints the document and publishes its diagnostics.
+func (s *Server) lint(doc *document) {
+	problems, err := s.linter.Problems(doc.path, []byte(doc.text))
+	doc.problems = problems
+
+	lines := strings.Split(doc.text, "\n")
+	diagnostics := []*Diagnostic{}
+	for _, problem := range problems {
+		diagnostics = append(diagnostics, s.diagnostic(doc, lines, problem))
+	}
+	if err != nil {
+		diagnostics = append(diagnostics, errorDiagnostics(lines, err)...)
+	}
+
+	version := doc.version
+	s.publish(&PublishDiagnosticsParams{URI: doc.uri, Version: &version, Diagnostics: diagnostics})
+}
+
+// publish sends the diagnostics of a document to the client.
+func (s *Server) publish(params *PublishDiagnosticsParams) {
+	if err := s.conn.notify("textDocument/publishDiagnostics", params); err != nil {
+		s.Logger.Printf("Error publishing diagnostics for %s: %v", params.URI, err)
+	}
+}
+
+// diagnostic returns the diagnostic of the given problem.
+func (s *Server) diagnostic(doc *document, lines []string, problem *lint.Problem) *Diagnostic {
+	message := problem.Message
+	if problem.Diff != nil {
+		message = fmt.Sprintf("%s %s", problem.Message, problem.Diff)
+	}
+
+	d := &Diagnostic{
+		Range:    problemRange(lines, problem),
+		Severity: diagnosticSeverity(s.linter.Severity(doc.path, problem.RuleID)),
+		Code:     problem.RuleID,
+		Source:   source,
+		Message:  message,
+	}
+	if rule := s.rule(problem.RuleID); rule != nil {
+		d.CodeDescription = &CodeDescription{Href: lint.MetadataOf(rule).DocsURL}
+	}
+	return d
+}
+
+// syntaxError matches the errors of the FSH parser.
+var syntaxError = regexp.MustCompile(`^syntax error on line (\d+):(\d+) - (.*)$`)
+
+// errorDiagnostics returns the diagnostics of an error returned while linting,
+// such as syntax errors.
+func errorDiagnostics(lines []string, err error) []*Diagnostic {
+	var diagnostics []*Diagnostic
+	for _, text := range strings.Split(err.Error(), "\n") {
+		d := &Diagnostic{Severity: SeverityError, Source: source, Message: text}
+		if match := syntaxError.FindStringSubmatch(text); match != nil {
+			line, _ := strconv.Atoi(match[1])
+			column, _ := strconv.Atoi(match[2])
+			start := position(lines, line-1, column)
+			d.Range = Range{Start: start, End: lineEnd(lines, start.Line)}
+			d.Message = match[3]
+		} else if i := strings.Index(text, "syntax error on line"); i > 0 {
+			// The first syntax error is prefixed with the file that failed to parse.
+			return errorDiagnostics(lines, errors.New(strings.TrimSpace(text[i:])))
+		}
+		diagnostics = append(diagnostics, d)
+	}
+	return diagnostics
+}
+
+// codeActions returns the quick fixes of the fixable problems on the lines of
+// the range, so that fixes are offered wherever the cursor is on those lines.
+func (s *Server) codeActions(params *CodeActionParams) []*CodeAction {
+	actions := []*CodeAction{}
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok {
+		return actions
+	}
+
+	lines := strings.Split(doc.text, "\n")
+	for _, problem := range doc.problems {
+		if !problem.IsFixable {
+			continue
+		}
+		r := problemRange(lines, problem)
+		if r.End.Line < params.Range.Start.Line || r.Start.Line > params.Range.End.Line {
+			continue
+		}
+		edits, ok := fixEdits(doc.text, problem)
+		if !ok {
+			continue
+		}
+		title := fmt.Sprintf("Fix %s", problem.RuleID)
+		if problem.Diff != nil && problem.Diff.Got == "" {
+			title = fmt.Sprintf("Fix %s: insert '%s'", problem.RuleID, problem.Diff.Want)
+		} else if problem.Diff != nil {
+			title = fmt.Sprintf("Fix %s: replace '%s' with '%s'", problem.RuleID, problem.Diff.Got, problem.Diff.Want)
+		}
+		if problem.UnsafeFix {
+			// Unsafe fixes may break references, so they are offered but never
+			// preferred, and not applied by fix-all commands.
+			title += " (unsafe)"
+		}
+		actions = append(actions, &CodeAction{
+			Title:       title,
+			Kind:        codeActionKindQuickFix,
+			Diagnostics: []*Diagnostic{s.diagnostic(doc, lines, problem)},
+			IsPreferred: !problem.UnsafeFix,
+			Edit:        &WorkspaceEdit{Changes: map[string][]*TextEdit{doc.uri: edits}},
+		})
+	}
+	return actions
+}
+
+// fixEdits returns the edits that fix the problem in text, like the linter's
+// --fix. Returns false if the problem cannot be fixed in text.
+func fixEdits(text string, problem *lint.Problem) ([]*TextEdit, bool) {
+	edits, err := problem.TextEdits([]byte(text))
+	if err != nil {
+		return nil, false
+	}
+	var result []*TextEdit
+	for _, edit := range edits {
+		if edit.Start < 0 || edit.End < edit.Start || edit.End > len(text) {
+			return nil, false
+		}
+		result = append(result, &TextEdit{
+			Range:   Range{Start: offsetPosition(text, edit.Start), End: offsetPosition(text, edit.End)},
+			NewText: edit.NewText,
+		})
+	}
+	return result, true
+}
+
+// offsetPosition returns the LSP position of the byte offset in text.
+func offsetPosition(text string, offset int) Position {
+	line := strings.Count(text[:offset], "\n")
+	lineStart := strings.LastIndexByte(text[:offset], '\n') + 1
+	return Position{Line: line, Character: utf16Len(text[lineStart:offset])}
+}
+
+// hover returns the descriptions of the rules of the problems at the position,
+// or nil if there are none.
+func (s *Server) hover(params *TextDocumentPositionParams) *Hover {
+	doc, ok := s.docs[params.TextDocument.URI]
+	if !ok {
+		return nil
+	}
+
+	lines := strings.Split(doc.text, "\n")
+	var sections []string
+	var hoverRange *Range
+	for _, problem := range doc.problems {
+		r := problemRange(lines, problem)
+		if !contains(r, params.Position) {
+			continue
+		}
+		rule := s.rule(problem.RuleID)
+		if rule == nil {
+			continue
+		}
+		md := lint.MetadataOf(rule)
+		sections = append(sections, fmt.Sprintf("**%s** (%s)\n\n%s\n\n[Documentation](%s)", rule.ID(), md.Category, md.Description, md.DocsURL))
+		hoverRange = &r
+	}
+	if len(sections) == 0 {
+		return nil
+	}
+	return &Hover{
+		Contents: MarkupContent{Kind: "markdown", Value: strings.Join(sections, "\n\n---\n\n")},
+		Range:    hoverRange,
+	}
+}
+
+// rule returns the rule of the linter with the given ID, or nil.
+func (s *Server) rule(id string) lint.Rule {
+	for _, rule := range s.linter.Rules() {
+		if rule.ID() == id {
+			return rule
+		}
+	}
+	return nil
+}
+
+// diagnosticSeverity returns the LSP severity of a linter severity.
+func diagnosticSeverity(severity diagnostic.Severity) DiagnosticSeverity {
+	switch severity {
+	case diagnostic.SeverityError:
+		return SeverityError
+	case diagnostic.SeverityWarning:
+		return SeverityWarning
+	case diagnostic.SeverityDebug:
+		return SeverityHint
+	default:
+		return SeverityInformation
+	}
+}
+
+// problemRange returns the range of a problem. Locations end at the start of
+// their last token, so the range is extended to the end of the last line. A
+// problem without a location spans the first line.
+func problemRange(lines []string, problem *lint.Problem) Range {
+	start, end := problem.StartPosition(), problem.EndPosition()
+	if start == nil {
+		return Range{Start: Position{}, End: lineEnd(lines, 0)}
+	}
+	if end == nil || end.LineNumber < start.LineNumber {
+		end = start
+	}
+	return Range{
+		Start: position(lines, start.LineNumber-1, start.ColumnNumber),
+		End:   lineEnd(lines, end.LineNumber-1),
+	}
+}
+
+// position returns the LSP position of the given zero-based line and column in
+// characters, as counted by the parser.
+func position(lines []string, line, column int) Position {
+	if line < 0 || len(lines) == 0 {
+		return Position{}
+	}
+	if line >= len(lines) {
+		line = len(lines) - 1
+	}
+	text := lines[line]
+	offset := 0
+	for i := 0; i < column && offset < len(text); i++ {
+		_, size := utf8.DecodeRuneInString(text[offset:])
+		offset += size
+	}
+	return Position{Line: line, Character: utf16Len(text[:offset])}
+}
+
+// lineEnd returns the position after the last non-whitespace character of the
+// given zero-based line.
+func lineEnd(lines []string, line int) Position {
+	if line < 0 || len(lines) == 0 {
+		return Position{}
+	}
+	if line >= len(lines) {
+		line = len(lines) - 1
+	}
+	return Position{Line: line, Character: utf16Len(strings.TrimRight(lines[line], " \t\r"))}
+}
+
+// utf16Len returns the length of s in UTF-16 code units, which LSP positions
+// count by default.
+func utf16Len(s string) int {
+	return len(utf16.Encode([]rune(s)))
+}
+
+// before returns true if a is before b.
+func before(a, b Position) bool {
+	return a.Line < b.Line || (a.Line == b.Line && a.Character < b.Character)
+}
+
+// contains returns true if the range contains the position, including its end.
+func contains(r Range, p Position) bool {
+	return !before(p, r.Start) && !before(r.End, p)
+}
+
+// uriToPath returns the file path of a file URI, or the URI itself if it is not
+// a file URI.
+func uriToPath(uri string) string {
+	u, err := url.Parse(uri)
+	if err != nil || u.Scheme != "file" {
+		return uri
+	}
+	return filepath.FromSlash(u.Path)
+}
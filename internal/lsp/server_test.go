@@ -0,0 +1,360 @@
+package lsp_test
+
+import (
+	"bufio"
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"net/textproto"
+	"net/url"
+	"os"
+	"path/filepath"
+	"strconv"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/lsp"
+	"github.com/verily-src/fsh-lint/lint"
+	"github.com/verily-src/fsh-lint/rules"
+)
+
+// client is a scripted JSON-RPC client of a server running in-process.
+type client struct {
+	t      *testing.T
+	w      io.WriteCloser
+	r      *textproto.Reader
+	nextID int
+	done   chan error
+}
+
+func newClient(t *testing.T, server *lsp.Server) *client {
+	t.Helper()
+	clientR, serverW := io.Pipe()
+	serverR, clientW := io.Pipe()
+	c := &client{t: t, w: clientW, r: textproto.NewReader(bufio.NewReader(clientR)), done: make(chan error, 1)}
+	go func() {
+		err := server.Serve(serverR, serverW)
+		_ = serverW.Close()
+		c.done <- err
+	}()
+	return c
+}
+
+// send sends a message to the server.
+func (c *client) send(msg map[string]any) {
+	c.t.Helper()
+	msg["jsonrpc"] = "2.0"
+	body, err := json.Marshal(msg)
+	if err != nil {
+		c.t.Fatal(err)
+	}
+	if _, err := fmt.Fprintf(c.w, "Content-Length: %d\r\n\r\n%s", len(body), body); err != nil {
+		c.t.Fatal(err)
+	}
+}
+
+// receive reads the next message from the server.
+func (c *client) receive() map[string]json.RawMessage {
+	c.t.Helper()
+	header, err := c.r.ReadMIMEHeader()
+	if err != nil {
+		c.t.Fatalf("reading header: %v", err)
+	}
+	length, err := strconv.Atoi(header.Get("Content-Length"))
+	if err != nil {
+		c.t.Fatalf("invalid Content-Length: %v", err)
+	}
+	body := make([]byte, length)
+	if _, err := io.ReadFull(c.r.R, body); err != nil {
+		c.t.Fatalf("reading body: %v", err)
+	}
+	var msg map[string]json.RawMessage
+	if err := json.Unmarshal(body, &msg); err != nil {
+		c.t.Fatal(err)
+	}
+	return msg
+}
+
+// notify sends a notification.
+func (c *client) notify(method string, params any) {
+	c.t.Helper()
+	c.send(map[string]any{"method": method, "params": params})
+}
+
+// call sends a request and unmarshals the result of its response into result.
+// Returns the error of the response, if any.
+func (c *client) call(method string, params any, result any) *rpcError {
+	c.t.Helper()
+	c.nextID++
+	c.send(map[string]any{"id": c.nextID, "method": method, "params": params})
+	msg := c.receive()
+	if string(msg["id"]) != strconv.Itoa(c.nextID) {
+		c.t.Fatalf("%s: got response id %s, want %d", method, msg["id"], c.nextID)
+	}
+	if data, ok := msg["error"]; ok {
+		rpcErr := &rpcError{}
+		if err := json.Unmarshal(data, rpcErr); err != nil {
+			c.t.Fatal(err)
+		}
+		return rpcErr
+	}
+	if result != nil {
+		if err := json.Unmarshal(msg["result"], result); err != nil {
+			c.t.Fatal(err)
+		}
+	}
+	return nil
+}
+
+// diagnostics reads the next notification, which must publish diagnostics.
+func (c *client) diagnostics() *lsp.PublishDiagnosticsParams {
+	c.t.Helper()
+	msg := c.receive()
+	if method := string(msg["method"]); method != `"textDocument/publishDiagnostics"` {
+		c.t.Fatalf("got method %s, want textDocument/publishDiagnostics", method)
+	}
+	params := &lsp.PublishDiagnosticsParams{}
+	if err := json.Unmarshal(msg["params"], params); err != nil {
+		c.t.Fatal(err)
+	}
+	return params
+}
+
+// exit shuts the server down and waits for it to return.
+func (c *client) exit() error {
+	c.t.Helper()
+	if err := c.call("shutdown", nil, nil); err != nil {
+		c.t.Fatalf("shutdown: %v", err)
+	}
+	c.notify("exit", nil)
+	err := <-c.done
+	_ = c.w.Close()
+	return err
+}
+
+type rpcError struct {
+	Code    int    `json:"code"`
+	Message string `json:"message"`
+}
+
+const uri = "file:///project/input/fsh/Example.fsh"
+
+func newServer() *lsp.Server {
+	linter := lint.NewLinter(nil, []lint.Rule{&rules.ValueSetNameMatchesIDRule{}})
+	server := lsp.NewServer(linter, "test")
+	server.Logger = log.New(io.Discard, "", 0)
+	return server
+}
+
+func TestServer(t *testing.T) {
+	c := newClient(t, newServer())
+
+	if err := c.call("textDocument/hover", map[string]any{}, nil); err == nil || err.Code != -32002 {
+		t.Errorf("hover before initialize: got error %v, want code -32002", err)
+	}
+
+	var initResult lsp.InitializeResult
+	if err := c.call("initialize", map[string]any{"capabilities": map[string]any{}}, &initResult); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	if !initResult.Capabilities.HoverProvider || initResult.ServerInfo.Name != "fsh-lint" || initResult.ServerInfo.Version != "test" {
+		t.Errorf("initialize: got %+v", initResult)
+	}
+	c.notify("initialized", map[string]any{})
+
+	c.notify("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{
+			"uri":        uri,
+			"languageId": "fsh",
+			"version":    1,
+			"text":       "ValueSet: ExampleVS\nId: wrong-id\nTitle: \"Example VS\"\n",
+		},
+	})
+	published := c.diagnostics()
+	wantRange := lsp.Range{Start: lsp.Position{Line: 1, Character: 4}, End: lsp.Position{Line: 1, Character: 12}}
+	if got, want := len(published.Diagnostics), 1; got != want {
+		t.Fatalf("didOpen: got %d diagnostics, want %d", got, want)
+	}
+	got := published.Diagnostics[0]
+	if got.Code != "value-set-name-matches-id" || got.Severity != lsp.SeverityInformation || got.Range != wantRange || got.CodeDescription == nil {
+		t.Errorf("didOpen: got diagnostic %+v", got)
+	}
+	if published.Version == nil || *published.Version != 1 {
+		t.Errorf("didOpen: got version %v, want 1", published.Version)
+	}
+
+	var actions []*lsp.CodeAction
+	if err := c.call("textDocument/codeAction", map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"range":        lsp.Range{Start: lsp.Position{Line: 1}, End: lsp.Position{Line: 1}},
+		"context":      map[string]any{"diagnostics": []any{}},
+	}, &actions); err != nil {
+		t.Fatalf("codeAction: %v", err)
+	}
+	wantEdits := map[string][]*lsp.TextEdit{
+		uri: {{Range: wantRange, NewText: "example-vs"}},
+	}
+	if len(actions) != 1 {
+		t.Fatalf("codeAction: got %d actions, want 1", len(actions))
+	}
+	if diff := cmp.Diff(actions[0].Edit.Changes, wantEdits); diff != "" {
+		t.Errorf("codeAction edits mismatch (-got +want):\n%s", diff)
+	}
+
+	var hover lsp.Hover
+	if err := c.call("textDocument/hover", map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"position":     lsp.Position{Line: 1, Character: 6},
+	}, &hover); err != nil {
+		t.Fatalf("hover: %v", err)
+	}
+	md := lint.MetadataOf(&rules.ValueSetNameMatchesIDRule{})
+	if hover.Contents.Kind != "markdown" || !contains(hover.Contents.Value, "**value-set-name-matches-id**", md.DocsURL) {
+		t.Errorf("hover: got %+v", hover.Contents)
+	}
+
+	c.notify("textDocument/didChange", map[string]any{
+		"textDocument":   map[string]any{"uri": uri, "version": 2},
+		"contentChanges": []any{map[string]any{"text": "ValueSet: ExampleVS\nId: example-vs\n"}},
+	})
+	if published := c.diagnostics(); len(published.Diagnostics) != 0 {
+		t.Errorf("didChange: got diagnostics %+v, want none", published.Diagnostics)
+	}
+
+	c.notify("textDocument/didSave", map[string]any{
+		"textDocument": map[string]any{"uri": uri},
+		"text":         "ValueSet: ExampleVS\nId: example-vs\nTitle: ",
+	})
+	published = c.diagnostics()
+	if len(published.Diagnostics) == 0 || published.Diagnostics[0].Range.Start.Line != 2 {
+		t.Errorf("didSave: got diagnostics %+v, want a syntax error on line 2", published.Diagnostics)
+	}
+
+	c.notify("textDocument/didClose", map[string]any{"textDocument": map[string]any{"uri": uri}})
+	if published := c.diagnostics(); len(published.Diagnostics) != 0 {
+		t.Errorf("didClose: got diagnostics %+v, want none", published.Diagnostics)
+	}
+
+	if err := c.call("workspace/unknown", map[string]any{}, nil); err == nil || err.Code != -32601 {
+		t.Errorf("unknown method: got error %v, want code -32601", err)
+	}
+
+	if err := c.exit(); err != nil {
+		t.Errorf("Serve() returned error: %v", err)
+	}
+}
+
+func TestServer_Navigation(t *testing.T) {
+	root := t.TempDir()
+	terminology := "RuleSet: MetadataRules\n* ^status = #active\n\nInvariant: dp-1\nSeverity: #error\n"
+	if err := os.WriteFile(filepath.Join(root, "Terminology.fsh"), []byte(terminology), 0644); err != nil {
+		t.Fatal(err)
+	}
+	rootURI := (&url.URL{Scheme: "file", Path: filepath.ToSlash(root)}).String()
+	terminologyURI := rootURI + "/Terminology.fsh"
+	profilesURI := rootURI + "/Profiles.fsh"
+
+	c := newClient(t, newServer())
+	if err := c.call("initialize", map[string]any{"rootUri": rootURI}, nil); err != nil {
+		t.Fatalf("initialize: %v", err)
+	}
+	c.notify("textDocument/didOpen", map[string]any{
+		"textDocument": map[string]any{
+			"uri":     profilesURI,
+			"version": 1,
+			"text":    "Profile: BaseProfile\nParent: Patient\n* insert MetadataRules\n\nProfile: DerivedProfile\nParent: BaseProfile\n* obeys dp-1\n",
+		},
+	})
+	c.diagnostics()
+
+	var symbols []*lsp.DocumentSymbol
+	if err := c.call("textDocument/documentSymbol", map[string]any{"textDocument": map[string]any{"uri": profilesURI}}, &symbols); err != nil {
+		t.Fatalf("documentSymbol: %v", err)
+	}
+	var names []string
+	for _, s := range symbols {
+		names = append(names, fmt.Sprintf("%s %d", s.Name, s.Range.Start.Line))
+	}
+	if diff := cmp.Diff(names, []string{"BaseProfile 0", "DerivedProfile 4"}); diff != "" {
+		t.Errorf("documentSymbol mismatch (-got +want):\n%s", diff)
+	}
+
+	tests := []struct {
+		method   string
+		position lsp.Position
+		want     []*lsp.Location
+	}{
+		{
+			method:   "textDocument/definition",
+			position: lsp.Position{Line: 2, Character: 12},
+			want: []*lsp.Location{
+				{URI: terminologyURI, Range: lsp.Range{Start: lsp.Position{Line: 0, Character: 9}, End: lsp.Position{Line: 0, Character: 22}}},
+			},
+		},
+		{
+			method:   "textDocument/definition",
+			position: lsp.Position{Line: 6, Character: 9},
+			want: []*lsp.Location{
+				{URI: terminologyURI, Range: lsp.Range{Start: lsp.Position{Line: 3, Character: 11}, End: lsp.Position{Line: 3, Character: 15}}},
+			},
+		},
+		{
+			method:   "textDocument/definition",
+			position: lsp.Position{Line: 5, Character: 10},
+			want: []*lsp.Location{
+				{URI: profilesURI, Range: lsp.Range{Start: lsp.Position{Line: 0, Character: 9}, End: lsp.Position{Line: 0, Character: 20}}},
+			},
+		},
+		{
+			// Base FHIR resources are not defined in the workspace.
+			method:   "textDocument/definition",
+			position: lsp.Position{Line: 1, Character: 10},
+			want:     []*lsp.Location{},
+		},
+		{
+			method:   "textDocument/references",
+			position: lsp.Position{Line: 0, Character: 12},
+			want: []*lsp.Location{
+				{URI: profilesURI, Range: lsp.Range{Start: lsp.Position{Line: 0, Character: 9}, End: lsp.Position{Line: 0, Character: 20}}},
+				{URI: profilesURI, Range: lsp.Range{Start: lsp.Position{Line: 5, Character: 8}, End: lsp.Position{Line: 5, Character: 19}}},
+			},
+		},
+	}
+	for _, tt := range tests {
+		var got []*lsp.Location
+		if err := c.call(tt.method, map[string]any{
+			"textDocument": map[string]any{"uri": profilesURI},
+			"position":     tt.position,
+			"context":      map[string]any{"includeDeclaration": true},
+		}, &got); err != nil {
+			t.Fatalf("%s: %v", tt.method, err)
+		}
+		if diff := cmp.Diff(got, tt.want); diff != "" {
+			t.Errorf("%s at %v mismatch (-got +want):\n%s", tt.method, tt.position, diff)
+		}
+	}
+
+	if err := c.exit(); err != nil {
+		t.Errorf("Serve() returned error: %v", err)
+	}
+}
+
+func TestServer_ExitWithoutShutdown(t *testing.T) {
+	c := newClient(t, newServer())
+	c.notify("exit", nil)
+	if err := <-c.done; err == nil {
+		t.Error("Serve() returned no error, want an error for exit before shutdown")
+	}
+}
+
+// contains returns true if s contains all of the substrings.
+func contains(s string, substrings ...string) bool {
+	for _, sub := range substrings {
+		if !strings.Contains(s, sub) {
+			return false
+		}
+	}
+	return true
+}
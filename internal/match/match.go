@@ -3,10 +3,39 @@ package match
 import (
 	"regexp"
 	"strings"
+	"unicode"
+	"unicode/utf8"
 
 	"github.com/iancoleman/strcase"
 )
 
+// NameToID returns the kebab-case ID of a PascalCase name, such as
+// "example-profile" for "ExampleProfile".
+func NameToID(name string) string {
+	return strcase.ToKebab(name)
+}
+
+// NameToTitle returns the Title Case title of a PascalCase name, such as
+// "Example Profile" for "ExampleProfile". The words are those that
+// IsNameMatchWithTitle matches, and acronyms keep their case, such as "HTTP
+// Status" for "HTTPStatus".
+func NameToTitle(name string) string {
+	words := strings.Fields(strcase.ToDelimited(name, ' '))
+	lower := strings.ToLower(name)
+	offset := 0
+	for i, word := range words {
+		// Restore the case of the word from the name, whose offsets are those
+		// of lower unless lowering it changed its length.
+		if j := strings.Index(lower[offset:], word); j >= 0 && len(lower) == len(name) {
+			start := offset + j
+			word, offset = name[start:start+len(word)], start+len(word)
+		}
+		r, size := utf8.DecodeRuneInString(word)
+		words[i] = string(unicode.ToUpper(r)) + word[size:]
+	}
+	return strings.Join(words, " ")
+}
+
 // IsNameMatchWithTitle returns true if a PascalCase name matches a Title Case title,
 // and false otherwise. If relaxNums is true, spaces in the Title are optional when
 // adjacent to a number. Otherwise, the PascalCase name converted to Title Case must
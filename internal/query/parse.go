@@ -0,0 +1,303 @@
+package query
+
+import (
+	"fmt"
+	"regexp"
+	"strconv"
+	"strings"
+	"unicode"
+)
+
+// tokenKind is the kind of a token of a query.
+type tokenKind int
+
+const (
+	tokenEOF tokenKind = iota
+	tokenIdent
+	tokenNumber
+	tokenString
+	tokenOperator
+)
+
+// token is a token of a query, at a byte offset.
+type token struct {
+	kind   tokenKind
+	text   string
+	offset int
+}
+
+// operators are the operators of the language, longest first so that they are
+// matched greedily.
+var operators = []string{"==", "!=", "<=", ">=", "=~", "!~", "&&", "||", "<", ">", "!", ".", "[", "]", "(", ")", "*"}
+
+// lex splits the query into tokens.
+func lex(src string) ([]token, error) {
+	var tokens []token
+	for i := 0; i < len(src); {
+		c := src[i]
+		switch {
+		case c == ' ' || c == '\t' || c == '\n' || c == '\r':
+			i++
+		case c == '"' || c == '`':
+			end := i + 1
+			for end < len(src) && src[end] != c {
+				if c == '"' && src[end] == '\\' {
+					end++
+				}
+				end++
+			}
+			if end >= len(src) {
+				return nil, fmt.Errorf("unterminated string at offset %d", i)
+			}
+			text, err := strconv.Unquote(src[i : end+1])
+			if err != nil {
+				return nil, fmt.Errorf("invalid string at offset %d: %w", i, err)
+			}
+			tokens = append(tokens, token{kind: tokenString, text: text, offset: i})
+			i = end + 1
+		case c >= '0' && c <= '9' || c == '-' && i+1 < len(src) && src[i+1] >= '0' && src[i+1] <= '9':
+			end := i + 1
+			for end < len(src) && (src[end] >= '0' && src[end] <= '9' || src[end] == '.') {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenNumber, text: src[i:end], offset: i})
+			i = end
+		case c == '_' || unicode.IsLetter(rune(c)):
+			end := i + 1
+			for end < len(src) && (src[end] == '_' || unicode.IsLetter(rune(src[end])) || unicode.IsDigit(rune(src[end]))) {
+				end++
+			}
+			tokens = append(tokens, token{kind: tokenIdent, text: src[i:end], offset: i})
+			i = end
+		default:
+			op := ""
+			for _, candidate := range operators {
+				if strings.HasPrefix(src[i:], candidate) {
+					op = candidate
+					break
+				}
+			}
+			if op == "" {
+				return nil, fmt.Errorf("unexpected character %q at offset %d", c, i)
+			}
+			tokens = append(tokens, token{kind: tokenOperator, text: op, offset: i})
+			i += len(op)
+		}
+	}
+	return append(tokens, token{kind: tokenEOF, offset: len(src)}), nil
+}
+
+// parser is a recursive descent parser of queries.
+type parser struct {
+	tokens []token
+	pos    int
+}
+
+// peek returns the current token.
+func (p *parser) peek() token {
+	return p.tokens[p.pos]
+}
+
+// next returns the current token and advances to the next one.
+func (p *parser) next() token {
+	t := p.tokens[p.pos]
+	if t.kind != tokenEOF {
+		p.pos++
+	}
+	return t
+}
+
+// accept advances past the current token and returns true if it is the given
+// operator.
+func (p *parser) accept(op string) bool {
+	if t := p.peek(); t.kind == tokenOperator && t.text == op {
+		p.pos++
+		return true
+	}
+	return false
+}
+
+// expect advances past the current token, or returns an error if it is not the
+// given operator.
+func (p *parser) expect(op string) error {
+	if !p.accept(op) {
+		return p.errorf("expected %q", op)
+	}
+	return nil
+}
+
+// errorf returns an error at the current token.
+func (p *parser) errorf(format string, args ...any) error {
+	t := p.peek()
+	found := strconv.Quote(t.text)
+	if t.kind == tokenEOF {
+		found = "end of query"
+	}
+	return fmt.Errorf("%s at offset %d, found %s", fmt.Sprintf(format, args...), t.offset, found)
+}
+
+// path parses a path of steps separated by dots.
+func (p *parser) path() (*path, error) {
+	result := &path{}
+	for {
+		t := p.peek()
+		if t.kind != tokenIdent {
+			return nil, p.errorf("expected a field name")
+		}
+		p.next()
+		s := &step{field: t.text}
+		for p.accept("[") {
+			sel, err := p.selector()
+			if err != nil {
+				return nil, err
+			}
+			s.selectors = append(s.selectors, sel)
+		}
+		result.steps = append(result.steps, s)
+		if !p.accept(".") {
+			return result, nil
+		}
+	}
+}
+
+// selector parses a selector, after its opening bracket.
+func (p *parser) selector() (*selector, error) {
+	if p.accept("*") {
+		return &selector{all: true}, p.expect("]")
+	}
+	if t := p.peek(); t.kind == tokenNumber && p.tokens[p.pos+1].text == "]" {
+		index, err := strconv.Atoi(t.text)
+		if err != nil || index < 0 {
+			return nil, p.errorf("invalid index")
+		}
+		p.pos += 2
+		return &selector{index: index}, nil
+	}
+	e, err := p.or()
+	if err != nil {
+		return nil, err
+	}
+	return &selector{index: -1, predicate: e}, p.expect("]")
+}
+
+// or parses a disjunction of conjunctions.
+func (p *parser) or() (expr, error) {
+	left, err := p.and()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("||") {
+		right, err := p.and()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{op: "||", left: left, right: right}
+	}
+	return left, nil
+}
+
+// and parses a conjunction of negations.
+func (p *parser) and() (expr, error) {
+	left, err := p.not()
+	if err != nil {
+		return nil, err
+	}
+	for p.accept("&&") {
+		right, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		left = &logical{op: "&&", left: left, right: right}
+	}
+	return left, nil
+}
+
+// not parses a negation, a parenthesized expression, or a comparison.
+func (p *parser) not() (expr, error) {
+	if p.accept("!") {
+		e, err := p.not()
+		if err != nil {
+			return nil, err
+		}
+		return &negation{e}, nil
+	}
+	if p.accept("(") {
+		e, err := p.or()
+		if err != nil {
+			return nil, err
+		}
+		return e, p.expect(")")
+	}
+	return p.comparison()
+}
+
+// comparison parses an operand, optionally compared to another operand.
+func (p *parser) comparison() (expr, error) {
+	left, err := p.operand()
+	if err != nil {
+		return nil, err
+	}
+	t := p.peek()
+	if t.kind != tokenOperator {
+		return left, nil
+	}
+	switch t.text {
+	case "==", "!=", "<", "<=", ">", ">=":
+		p.next()
+		right, err := p.operand()
+		if err != nil {
+			return nil, err
+		}
+		return &comparison{op: t.text, left: left, right: right}, nil
+	case "=~", "!~":
+		p.next()
+		pattern := p.peek()
+		if pattern.kind != tokenString {
+			return nil, p.errorf("expected a regular expression string")
+		}
+		p.next()
+		re, err := regexp.Compile(pattern.text)
+		if err != nil {
+			return nil, fmt.Errorf("invalid regular expression at offset %d: %w", pattern.offset, err)
+		}
+		return &match{negated: t.text == "!~", operand: left, re: re}, nil
+	}
+	return left, nil
+}
+
+// operand parses a literal, a function call, or a path.
+func (p *parser) operand() (expr, error) {
+	t := p.peek()
+	switch t.kind {
+	case tokenString:
+		p.next()
+		return &literal{t.text}, nil
+	case tokenNumber:
+		p.next()
+		n, err := strconv.ParseFloat(t.text, 64)
+		if err != nil {
+			return nil, fmt.Errorf("invalid number at offset %d: %w", t.offset, err)
+		}
+		return &literal{n}, nil
+	case tokenIdent:
+		switch t.text {
+		case "nil":
+			p.next()
+			return &literal{nil}, nil
+		case "true", "false":
+			p.next()
+			return &literal{t.text == "true"}, nil
+		case "count", "len":
+			if next := p.tokens[p.pos+1]; next.kind == tokenOperator && next.text == "(" {
+				p.pos += 2
+				arg, err := p.path()
+				if err != nil {
+					return nil, err
+				}
+				return &call{fn: t.text, arg: arg}, p.expect(")")
+			}
+		}
+		return p.path()
+	}
+	return nil, p.errorf("expected a value or a field name")
+}
@@ -0,0 +1,533 @@
+// Package query implements a small expression language to select elements of
+// a parsed FSH document, for ad-hoc queries and declarative rules.
+//
+// A query is a path of dot-separated fields, starting at the FSHDocument, such
+// as Profiles.ProfileRules.BindingRules. Fields are named by their Go name or
+// their JSON name in the ast package. Slices are traversed implicitly, and nil
+// elements are skipped, so the path selects the binding rules of all profiles.
+// Each field can be followed by selectors in brackets:
+//
+//   - [*] selects all elements, and is the same as no selector.
+//   - [N] selects the element at index N of each slice.
+//   - [predicate] selects the elements for which the predicate is true.
+//
+// Predicates are evaluated relative to the element, and are made of paths,
+// literals (strings, numbers, true, false, and nil), the functions count(path)
+// and len(path), the comparisons ==, !=, <, <=, >, and >=, the regular
+// expression matches =~ and !~, and the logical operators !, &&, and ||, with
+// parentheses for grouping. For example:
+//
+//	Profiles[Name =~ "^Verily"].ProfileRules.BindingRules[Strength == nil]
+//	Profiles[count(ProfileRules.CardRules) > 10]
+//
+// A path in a comparison is true if any of its values satisfies it, and a path
+// without values, or whose string element is empty, equals nil. String
+// elements of the ast package compare as their values, and strings compare as
+// numbers when compared to a number.
+package query
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strconv"
+	"strings"
+
+	"github.com/verily-src/fsh-lint/ast"
+)
+
+var (
+	documentType = reflect.TypeFor[ast.FSHDocument]()
+	locationType = reflect.TypeFor[ast.Location]()
+)
+
+// Query is a parsed query.
+type Query struct {
+	src  string
+	path *path
+}
+
+// Result is an element selected by a query.
+type Result struct {
+	// Path is the path of the element in the document, with the index of each
+	// slice element, such as Profiles[0].ProfileRules.BindingRules[1].
+	Path string `json:"path"`
+
+	// Value is the element, such as an *ast.BindingRule.
+	Value any `json:"value"`
+
+	// Location is the location of the element, or of its first field that has
+	// one, such as the name of a profile. It is nil if none of them has one.
+	Location *ast.Location `json:"location"`
+}
+
+// Parse parses the query, and checks that its fields exist in the document.
+func Parse(src string) (*Query, error) {
+	tokens, err := lex(src)
+	if err != nil {
+		return nil, err
+	}
+	p := &parser{tokens: tokens}
+	result, err := p.path()
+	if err != nil {
+		return nil, err
+	}
+	if p.peek().kind != tokenEOF {
+		return nil, p.errorf("unexpected token")
+	}
+	if err := result.check(documentType); err != nil {
+		return nil, err
+	}
+	return &Query{src: src, path: result}, nil
+}
+
+// String returns the source of the query.
+func (q *Query) String() string {
+	return q.src
+}
+
+// Root returns the Go name of the first field of the query, such as Profiles.
+func (q *Query) Root() string {
+	return q.path.steps[0].goField
+}
+
+// Eval returns the elements of the document selected by the query, in the
+// order of the document.
+func (q *Query) Eval(doc *ast.FSHDocument) []*Result {
+	var results []*Result
+	for _, n := range q.path.eval([]*node{{value: reflect.ValueOf(doc)}}) {
+		results = append(results, &Result{Path: n.path, Value: n.value.Interface(), Location: locationOf(n.value)})
+	}
+	return results
+}
+
+// node is an element of the document, at a path.
+type node struct {
+	value reflect.Value
+	path  string
+}
+
+// path is a dot-separated path of fields.
+type path struct {
+	steps []*step
+}
+
+// step selects a field of an element, and filters its values with selectors.
+type step struct {
+	field     string
+	selectors []*selector
+
+	// goField is the Go name of the field, set when the query is checked.
+	goField string
+}
+
+// selector filters the values of a field: all of them, the value at an index,
+// or the values for which a predicate is true.
+type selector struct {
+	all       bool
+	index     int
+	predicate expr
+}
+
+// expr is an expression of a predicate.
+type expr interface {
+	// check returns an error if the expression uses a field that does not
+	// exist in elements of type t.
+	check(t reflect.Type) error
+
+	// values returns the values of the expression for the element.
+	values(n *node) []any
+}
+
+// check returns an error if a field of the path does not exist in elements of
+// type t, or in the values of the previous field.
+func (p *path) check(t reflect.Type) error {
+	for _, s := range p.steps {
+		t = elemType(t)
+		if t.Kind() != reflect.Struct {
+			return fmt.Errorf("%s has no field %s", t, s.field)
+		}
+		field, ok := fieldByName(t, s.field)
+		if !ok {
+			return fmt.Errorf("%s has no field %s", t.Name(), s.field)
+		}
+		s.goField = field.Name
+		t = field.Type
+		for _, sel := range s.selectors {
+			if sel.predicate == nil {
+				continue
+			}
+			if err := sel.predicate.check(elemType(t)); err != nil {
+				return err
+			}
+		}
+	}
+	return nil
+}
+
+// eval returns the values of the path in the given elements.
+func (p *path) eval(nodes []*node) []*node {
+	for _, s := range p.steps {
+		var next []*node
+		for _, n := range nodes {
+			field := reflect.Indirect(n.value).FieldByName(s.goField)
+			fieldPath := s.goField
+			if n.path != "" {
+				fieldPath = n.path + "." + s.goField
+			}
+			values := flatten(field, fieldPath)
+			for _, sel := range s.selectors {
+				values = sel.apply(values)
+			}
+			next = append(next, values...)
+		}
+		nodes = next
+	}
+	return nodes
+}
+
+// apply returns the values selected by the selector.
+func (s *selector) apply(nodes []*node) []*node {
+	switch {
+	case s.all:
+		return nodes
+	case s.predicate == nil:
+		if s.index < len(nodes) {
+			return nodes[s.index : s.index+1]
+		}
+		return nil
+	}
+	var result []*node
+	for _, n := range nodes {
+		if truthy(s.predicate.values(n)) {
+			result = append(result, n)
+		}
+	}
+	return result
+}
+
+// flatten returns the value as nodes, with one node for each element of a
+// slice, and without nil pointers.
+func flatten(v reflect.Value, p string) []*node {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if v.IsNil() {
+			return nil
+		}
+	case reflect.Slice, reflect.Array:
+		var result []*node
+		for i := range v.Len() {
+			result = append(result, flatten(v.Index(i), fmt.Sprintf("%s[%d]", p, i))...)
+		}
+		return result
+	}
+	return []*node{{value: v, path: p}}
+}
+
+func (p *path) values(n *node) []any {
+	var result []any
+	for _, v := range p.eval([]*node{n}) {
+		if s := scalar(v.value); s != nil {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// literal is a string, number, boolean, or nil value.
+type literal struct {
+	value any
+}
+
+func (*literal) check(reflect.Type) error {
+	return nil
+}
+
+func (l *literal) values(*node) []any {
+	return []any{l.value}
+}
+
+// call is a call of the count or len function.
+type call struct {
+	fn  string
+	arg *path
+}
+
+func (c *call) check(t reflect.Type) error {
+	return c.arg.check(t)
+}
+
+func (c *call) values(n *node) []any {
+	if c.fn == "count" {
+		return []any{float64(len(c.arg.eval([]*node{n})))}
+	}
+	length := 0
+	if values := c.arg.values(n); len(values) > 0 {
+		length = len(fmt.Sprint(values[0]))
+	}
+	return []any{float64(length)}
+}
+
+// comparison compares the values of two expressions.
+type comparison struct {
+	op          string
+	left, right expr
+}
+
+func (c *comparison) check(t reflect.Type) error {
+	if err := c.left.check(t); err != nil {
+		return err
+	}
+	return c.right.check(t)
+}
+
+func (c *comparison) values(n *node) []any {
+	left, right := orNil(c.left.values(n)), orNil(c.right.values(n))
+	for _, l := range left {
+		for _, r := range right {
+			if compare(c.op, l, r) {
+				return []any{true}
+			}
+		}
+	}
+	return []any{false}
+}
+
+// match matches the values of an expression with a regular expression.
+type match struct {
+	negated bool
+	operand expr
+	re      *regexp.Regexp
+}
+
+func (m *match) check(t reflect.Type) error {
+	return m.operand.check(t)
+}
+
+func (m *match) values(n *node) []any {
+	matched := false
+	for _, v := range m.operand.values(n) {
+		if s, ok := v.(string); ok && m.re.MatchString(s) {
+			matched = true
+			break
+		}
+	}
+	return []any{matched != m.negated}
+}
+
+// logical is the conjunction or disjunction of two expressions.
+type logical struct {
+	op          string
+	left, right expr
+}
+
+func (l *logical) check(t reflect.Type) error {
+	if err := l.left.check(t); err != nil {
+		return err
+	}
+	return l.right.check(t)
+}
+
+func (l *logical) values(n *node) []any {
+	left := truthy(l.left.values(n))
+	if l.op == "&&" {
+		return []any{left && truthy(l.right.values(n))}
+	}
+	return []any{left || truthy(l.right.values(n))}
+}
+
+// negation is the negation of an expression.
+type negation struct {
+	e expr
+}
+
+func (n *negation) check(t reflect.Type) error {
+	return n.e.check(t)
+}
+
+func (n *negation) values(el *node) []any {
+	return []any{!truthy(n.e.values(el))}
+}
+
+// truthy returns true if any of the values is true, a non-zero number, or any
+// other non-nil value, such as the element of a path.
+func truthy(values []any) bool {
+	for _, v := range values {
+		switch v := v.(type) {
+		case nil:
+		case bool:
+			if v {
+				return true
+			}
+		case float64:
+			if v != 0 {
+				return true
+			}
+		default:
+			return true
+		}
+	}
+	return false
+}
+
+// orNil returns the values, or a single nil value if there are none.
+func orNil(values []any) []any {
+	if len(values) == 0 {
+		return []any{nil}
+	}
+	return values
+}
+
+// compare returns true if the comparison of the two values is true.
+func compare(op string, left, right any) bool {
+	if left == nil || right == nil {
+		switch op {
+		case "==":
+			return left == nil && right == nil
+		case "!=":
+			return left != nil || right != nil
+		}
+		return false
+	}
+
+	var cmp int
+	l, lok := number(left)
+	r, rok := number(right)
+	_, lnum := left.(float64)
+	_, rnum := right.(float64)
+	if lok && rok && (lnum || rnum) {
+		switch {
+		case l < r:
+			cmp = -1
+		case l > r:
+			cmp = 1
+		}
+	} else {
+		cmp = strings.Compare(fmt.Sprint(left), fmt.Sprint(right))
+	}
+
+	switch op {
+	case "==":
+		return cmp == 0
+	case "!=":
+		return cmp != 0
+	case "<":
+		return cmp < 0
+	case "<=":
+		return cmp <= 0
+	case ">":
+		return cmp > 0
+	default:
+		return cmp >= 0
+	}
+}
+
+// number returns the value as a number, if it is a number or a string of one.
+func number(v any) (float64, bool) {
+	switch v := v.(type) {
+	case float64:
+		return v, true
+	case string:
+		f, err := strconv.ParseFloat(v, 64)
+		return f, err == nil
+	}
+	return 0, false
+}
+
+// scalar returns the value of an element for comparisons: the value of a
+// parsed element, strings, booleans, and numbers as float64. Empty parsed
+// elements are nil, and other elements are returned as is.
+func scalar(v reflect.Value) any {
+	v = reflect.Indirect(v)
+	switch v.Kind() {
+	case reflect.Struct:
+		if value, ok := parsedElementValue(v); ok {
+			if value.IsZero() {
+				return nil
+			}
+			return scalar(value)
+		}
+		if v.CanAddr() {
+			return v.Addr().Interface()
+		}
+		return v.Interface()
+	case reflect.String:
+		return v.String()
+	case reflect.Bool:
+		return v.Bool()
+	case reflect.Int, reflect.Int8, reflect.Int16, reflect.Int32, reflect.Int64:
+		return float64(v.Int())
+	case reflect.Uint, reflect.Uint8, reflect.Uint16, reflect.Uint32, reflect.Uint64:
+		return float64(v.Uint())
+	case reflect.Float32, reflect.Float64:
+		return v.Float()
+	}
+	if v.IsValid() && v.CanInterface() {
+		return v.Interface()
+	}
+	return nil
+}
+
+// parsedElementValue returns the Value field of an ast.ParsedElement.
+func parsedElementValue(v reflect.Value) (reflect.Value, bool) {
+	if !strings.HasPrefix(v.Type().Name(), "ParsedElement[") {
+		return reflect.Value{}, false
+	}
+	return v.FieldByName("Value"), true
+}
+
+// locationOf returns the location of the value, which is the location of its
+// first field that has one, depth first, or nil if none of them has one.
+func locationOf(v reflect.Value) *ast.Location {
+	switch v.Kind() {
+	case reflect.Ptr, reflect.Interface:
+		if !v.IsNil() {
+			return locationOf(v.Elem())
+		}
+	case reflect.Slice, reflect.Array:
+		for i := range v.Len() {
+			if location := locationOf(v.Index(i)); location != nil {
+				return location
+			}
+		}
+	case reflect.Struct:
+		if v.Type() == locationType {
+			location := v.Interface().(ast.Location)
+			if location.Start == nil || location.End == nil {
+				return nil
+			}
+			return &location
+		}
+		for i := range v.NumField() {
+			if location := locationOf(v.Field(i)); location != nil {
+				return location
+			}
+		}
+	}
+	return nil
+}
+
+// elemType returns the type of the elements of t, following pointers and
+// slices.
+func elemType(t reflect.Type) reflect.Type {
+	for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice || t.Kind() == reflect.Array {
+		t = t.Elem()
+	}
+	return t
+}
+
+// fieldByName returns the exported field of the struct type t with the given
+// Go name or JSON name.
+func fieldByName(t reflect.Type, name string) (reflect.StructField, bool) {
+	if field, ok := t.FieldByName(name); ok && field.IsExported() {
+		return field, true
+	}
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if field.IsExported() && tag == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
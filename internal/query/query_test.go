@@ -0,0 +1,133 @@
+package query_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/fsh"
+	"github.com/verily-src/fsh-lint/internal/query"
+)
+
+const data = `Profile: VerilyPatient
+Parent: Patient
+Id: verily-patient
+Title: "Verily Patient"
+* gender from GenderValueSet (required)
+* maritalStatus from MaritalValueSet
+* name 1..1 MS
+
+Profile: OtherPatient
+Parent: Patient
+Id: other-patient
+* gender from GenderValueSet (extensible)
+* name 0..1
+* address 0..*
+
+ValueSet: GenderValueSet
+Title: "Gender"
+`
+
+func TestQuery_Eval(t *testing.T) {
+	tests := []struct {
+		query string
+		want  []string
+	}{
+		{
+			query: "Profiles.Name",
+			want:  []string{"1 Profiles[0].Name", "9 Profiles[1].Name"},
+		},
+		{
+			query: "Profiles[*].ProfileRules.BindingRules[Strength == nil]",
+			want:  []string{"6 Profiles[0].ProfileRules.BindingRules[1]"},
+		},
+		{
+			// JSON names can be used instead of Go names.
+			query: "profiles.profileRules.bindingRules[strength != nil].valueSet",
+			want: []string{
+				"5 Profiles[0].ProfileRules.BindingRules[0].ValueSet",
+				"12 Profiles[1].ProfileRules.BindingRules[0].ValueSet",
+			},
+		},
+		{
+			query: `Profiles[Name =~ "^Verily"].ID`,
+			want:  []string{"3 Profiles[0].ID"},
+		},
+		{
+			query: `Profiles[Name !~ "^Verily"]`,
+			want:  []string{"9 Profiles[1]"},
+		},
+		{
+			query: "Profiles[Title == nil]",
+			want:  []string{"9 Profiles[1]"},
+		},
+		{
+			query: "Profiles[1].ProfileRules.CardRules[0]",
+			want:  []string{"13 Profiles[1].ProfileRules.CardRules[0]"},
+		},
+		{
+			query: "Profiles[count(ProfileRules.CardRules) >= 2 && !(Title != nil)]",
+			want:  []string{"9 Profiles[1]"},
+		},
+		{
+			query: `Profiles[ProfileRules.BindingRules.Strength == "(extensible)" || len(Name) < 5]`,
+			want:  []string{"9 Profiles[1]"},
+		},
+		{
+			// Strings compare as numbers when compared to a number.
+			query: "Profiles.ProfileRules.CardRules[Cardinality.Min > 0]",
+			want:  []string{"7 Profiles[0].ProfileRules.CardRules[0]"},
+		},
+		{
+			query: "ValueSets[Description]",
+		},
+		{
+			query: "CodeSystems.Name",
+		},
+	}
+
+	doc, err := fsh.Parse(data)
+	if err != nil {
+		t.Fatalf("Parse() error: %v", err)
+	}
+	for _, tt := range tests {
+		t.Run(tt.query, func(t *testing.T) {
+			q, err := query.Parse(tt.query)
+			if err != nil {
+				t.Fatalf("Parse(%q) error: %v", tt.query, err)
+			}
+			var got []string
+			for _, r := range q.Eval(doc) {
+				got = append(got, fmt.Sprintf("%d %s", r.Location.Start.LineNumber, r.Path))
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("Eval() mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParse_Invalid(t *testing.T) {
+	tests := []string{
+		"",
+		"Profiles.",
+		"Profiles.Version",
+		"Profiles[Version == nil]",
+		"Profiles.Name.Value.Length",
+		"Profiles[",
+		"Profiles[*",
+		`Profiles[Name =~ "["]`,
+		"Profiles[Name =~ Title]",
+		`Profiles[Name == "unterminated]`,
+		"Profiles Name",
+		"Profiles[Name = 1]",
+		"Profiles[count(Name]",
+	}
+	for _, src := range tests {
+		t.Run(src, func(t *testing.T) {
+			if _, err := query.Parse(src); err == nil {
+				t.Errorf("Parse(%q) returned no error", src)
+			}
+		})
+	}
+}
@@ -0,0 +1,102 @@
+// Package split moves the entities of FSH files that define more than one of
+// them into files of their own, named after the entities.
+package split
+
+import (
+	"bytes"
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+
+	"github.com/verily-src/fsh-lint/internal/fsh"
+)
+
+// Kinds are the keywords of the entities that are moved into files of their
+// own. Aliases, rule sets, invariants, and other entities stay in the file, so
+// that the entities that use them can still refer to them.
+var Kinds = []string{"CodeSystem", "Extension", "Instance", "Profile", "ValueSet"}
+
+// Moved returns the entities of the tree of the file at path that are moved
+// into files of their own: the entities of Kinds if the file defines more than
+// one, except the one named like the file, which stays.
+func Moved(path string, tree *fsh.SyntaxTree) []*fsh.Node {
+	var entities []*fsh.Node
+	for _, n := range tree.Nodes {
+		if n.Kind == fsh.NodeEntity && slices.Contains(Kinds, n.Keyword()) {
+			entities = append(entities, n)
+		}
+	}
+	if len(entities) < 2 {
+		return nil
+	}
+	name := strings.TrimSuffix(filepath.Base(path), ".fsh")
+	return slices.DeleteFunc(entities, func(n *fsh.Node) bool {
+		return n.Name() == name
+	})
+}
+
+// Path returns the path of the file that the entity is moved into from the
+// file at path.
+func Path(path string, entity *fsh.Node) string {
+	return filepath.Join(filepath.Dir(path), entity.Name()+".fsh")
+}
+
+// File splits the data of the FSH file at path. It returns the remaining data
+// of the file, or nil if nothing but whitespace remains, and the data of the
+// files that its entities are moved into, by path. The files are nil if no
+// entity is moved. Each entity is moved with the comments before it. Returns
+// an error if the file has syntax errors, or if two entities would be moved
+// into the same file.
+func File(path string, data []byte) ([]byte, map[string][]byte, error) {
+	tree, err := fsh.ParseSyntaxTree(string(data))
+	if err != nil {
+		return nil, nil, err
+	}
+	moved := Moved(path, tree)
+	if len(moved) == 0 {
+		return data, nil, nil
+	}
+	newline := "\n"
+	if bytes.Contains(data, []byte("\r\n")) {
+		newline = "\r\n"
+	}
+
+	files := make(map[string][]byte)
+	var remaining []byte
+	end := 0
+	for _, entity := range moved {
+		name := entity.Name()
+		if name == "" || strings.ContainsAny(name, `/\`) {
+			return nil, nil, fmt.Errorf("%s %q cannot be moved into a file named after it", entity.Keyword(), name)
+		}
+		target := Path(path, entity)
+		if _, ok := files[target]; ok {
+			return nil, nil, fmt.Errorf("more than one entity is named %s", name)
+		}
+		text := trimBlankLines(entity.String())
+		if !strings.HasSuffix(text, "\n") {
+			text += newline
+		}
+		files[target] = []byte(text)
+
+		remaining = append(remaining, data[end:entity.Start()]...)
+		end = entity.End()
+	}
+	remaining = append(remaining, data[end:]...)
+	if len(bytes.TrimSpace(remaining)) == 0 {
+		remaining = nil
+	}
+	return remaining, files, nil
+}
+
+// trimBlankLines returns the text without the blank lines at its start.
+func trimBlankLines(text string) string {
+	for {
+		i := strings.IndexByte(text, '\n')
+		if i < 0 || strings.TrimSpace(text[:i]) != "" {
+			return text
+		}
+		text = text[i+1:]
+	}
+}
@@ -0,0 +1,82 @@
+package split_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/split"
+)
+
+func TestFile(t *testing.T) {
+	tests := []struct {
+		name          string
+		path          string
+		data          string
+		wantRemaining string
+		wantFiles     map[string]string
+		wantErr       bool
+	}{
+		{
+			name:          "one entity",
+			path:          "dir/Other.fsh",
+			data:          "Alias: $a = http://a\n\nProfile: A\nParent: Patient\n",
+			wantRemaining: "Alias: $a = http://a\n\nProfile: A\nParent: Patient\n",
+		},
+		{
+			name:          "aliases and rule sets stay",
+			path:          "dir/Terminology.fsh",
+			data:          "Alias: $a = http://a\n\n// Codes.\nValueSet: Codes\n* include codes from system $a\n\nRuleSet: Draft\n* ^status = #draft\n\nCodeSystem: System\n* insert Draft\n",
+			wantRemaining: "Alias: $a = http://a\n\nRuleSet: Draft\n* ^status = #draft\n",
+			wantFiles: map[string]string{
+				"dir/Codes.fsh":  "// Codes.\nValueSet: Codes\n* include codes from system $a\n",
+				"dir/System.fsh": "CodeSystem: System\n* insert Draft\n",
+			},
+		},
+		{
+			name:          "entity named like the file stays",
+			path:          "A.fsh",
+			data:          "Profile: A\nParent: Patient\n\nInstance: B\nInstanceOf: A\n",
+			wantRemaining: "Profile: A\nParent: Patient\n",
+			wantFiles:     map[string]string{"B.fsh": "Instance: B\nInstanceOf: A\n"},
+		},
+		{
+			name:      "nothing remains",
+			path:      "Profiles.fsh",
+			data:      "Profile: A\r\nParent: Patient\r\n\r\nProfile: B\r\nParent: Patient",
+			wantFiles: map[string]string{"A.fsh": "Profile: A\r\nParent: Patient\r\n", "B.fsh": "Profile: B\r\nParent: Patient\r\n"},
+		},
+		{
+			name:    "same name",
+			path:    "Other.fsh",
+			data:    "Profile: A\nParent: Patient\n\nInstance: A\nInstanceOf: Patient\n",
+			wantErr: true,
+		},
+		{
+			name:    "syntax error",
+			path:    "Other.fsh",
+			data:    "Profile: A\n* #x, #y\n",
+			wantErr: true,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			remaining, files, err := split.File(tt.path, []byte(tt.data))
+			if (err != nil) != tt.wantErr {
+				t.Fatalf("File() error = %v, wantErr %t", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(tt.wantRemaining, string(remaining)); diff != "" {
+				t.Errorf("File() remaining mismatch (-want +got):\n%s", diff)
+			}
+			var got map[string]string
+			for path, data := range files {
+				if got == nil {
+					got = make(map[string]string)
+				}
+				got[path] = string(data)
+			}
+			if diff := cmp.Diff(tt.wantFiles, got); diff != "" {
+				t.Errorf("File() files mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
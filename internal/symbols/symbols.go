@@ -0,0 +1,393 @@
+// Package symbols indexes the entities defined in FSH documents and the
+// references between them, for navigation such as listing the entities of a
+// file, going to the definition of a reference, and finding all references to
+// an entity.
+package symbols
+
+import (
+	"path"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/verily-src/fsh-lint/ast"
+)
+
+// Kind is the kind of entity a Symbol defines.
+type Kind string
+
+const (
+	KindCodeSystem Kind = "CodeSystem"
+	KindExtension  Kind = "Extension"
+	KindInstance   Kind = "Instance"
+	KindInvariant  Kind = "Invariant"
+	KindProfile    Kind = "Profile"
+	KindRuleSet    Kind = "RuleSet"
+	KindValueSet   Kind = "ValueSet"
+)
+
+// Symbol is an entity defined in a FSH file.
+type Symbol struct {
+	Name string `json:"name"`
+
+	// ID is the Id of the entity, if it has one.
+	ID string `json:"id,omitempty"`
+
+	Kind     Kind          `json:"kind"`
+	Path     string        `json:"path"`
+	Location *ast.Location `json:"location"`
+
+	// IDLocation is the location of the ID of the entity, if it has one.
+	IDLocation *ast.Location `json:"idLocation,omitempty"`
+}
+
+// ReferenceKind is the kind of rule or keyword that references an entity.
+type ReferenceKind string
+
+const (
+	// ReferenceParent is the Parent of a profile or extension.
+	ReferenceParent ReferenceKind = "Parent"
+
+	// ReferenceInstanceOf is the InstanceOf of an instance.
+	ReferenceInstanceOf ReferenceKind = "InstanceOf"
+
+	// ReferenceInsert is the rule set of an insert rule.
+	ReferenceInsert ReferenceKind = "insert"
+
+	// ReferenceBinding is the value set of a binding rule.
+	ReferenceBinding ReferenceKind = "from"
+
+	// ReferenceObeys is an invariant of an obeys rule.
+	ReferenceObeys ReferenceKind = "obeys"
+
+	// ReferenceContains is an extension of a contains rule on an extension
+	// element.
+	ReferenceContains ReferenceKind = "contains"
+
+	// ReferenceIncludeValueSet is a value set that codes of a value set are
+	// included from or excluded from.
+	ReferenceIncludeValueSet ReferenceKind = "valueset"
+
+	// ReferenceIncludeCodeSystem is a code system that codes of a value set are
+	// included from or excluded from.
+	ReferenceIncludeCodeSystem ReferenceKind = "system"
+)
+
+// targetKinds are the kinds of entities each kind of reference can resolve
+// to. Entities that are not indexed, such as logical models, resources, or the
+// base FHIR definitions, are not resolved.
+var targetKinds = map[ReferenceKind][]Kind{
+	ReferenceParent:            {KindProfile, KindExtension},
+	ReferenceInstanceOf:        {KindProfile, KindExtension},
+	ReferenceInsert:            {KindRuleSet},
+	ReferenceBinding:           {KindValueSet},
+	ReferenceObeys:             {KindInvariant},
+	ReferenceContains:          {KindExtension},
+	ReferenceIncludeValueSet:   {KindValueSet},
+	ReferenceIncludeCodeSystem: {KindCodeSystem},
+}
+
+// Reference is a use of the name or ID of an entity in a FSH file.
+type Reference struct {
+	// Name is the name or ID of the referenced entity, as written.
+	Name string `json:"name"`
+
+	Kind ReferenceKind `json:"kind"`
+
+	// From is the name of the entity that contains the reference.
+	From string `json:"from"`
+
+	Path     string        `json:"path"`
+	Location *ast.Location `json:"location"`
+}
+
+// file holds the symbols and references of an indexed file.
+type file struct {
+	symbols    []*Symbol
+	references []*Reference
+}
+
+// Index is an index of the symbols and references of a set of FSH files. It is
+// safe for concurrent use.
+type Index struct {
+	mu    sync.RWMutex
+	files map[string]*file
+}
+
+// NewIndex returns an empty Index.
+func NewIndex() *Index {
+	return &Index{files: make(map[string]*file)}
+}
+
+// Add indexes the given parsed document as the contents of the file at the
+// given path, replacing any previous contents of the file.
+func (idx *Index) Add(path string, doc *ast.FSHDocument) {
+	f := &file{}
+	b := &builder{path: path, file: f}
+	b.document(doc)
+	sortByLocation(f.symbols, func(s *Symbol) (string, *ast.Location) { return s.Path, s.Location })
+	sortByLocation(f.references, func(r *Reference) (string, *ast.Location) { return r.Path, r.Location })
+
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	idx.files[path] = f
+}
+
+// Remove removes the file at the given path from the index.
+func (idx *Index) Remove(path string) {
+	idx.mu.Lock()
+	defer idx.mu.Unlock()
+	delete(idx.files, path)
+}
+
+// Symbols returns the symbols of the file at the given path, in the order they
+// are defined.
+func (idx *Index) Symbols(path string) []*Symbol {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	if f, ok := idx.files[path]; ok {
+		return slices.Clone(f.symbols)
+	}
+	return nil
+}
+
+// AllSymbols returns the symbols of all files, sorted by path and location.
+func (idx *Index) AllSymbols() []*Symbol {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	var result []*Symbol
+	for _, f := range idx.files {
+		result = append(result, f.symbols...)
+	}
+	sortByLocation(result, func(s *Symbol) (string, *ast.Location) { return s.Path, s.Location })
+	return result
+}
+
+// Lookup returns the symbols with the given name or ID, sorted by path and
+// location. More than one symbol is returned if the name is defined more than
+// once.
+func (idx *Index) Lookup(name string) []*Symbol {
+	var result []*Symbol
+	for _, s := range idx.AllSymbols() {
+		if s.Name == name || s.ID == name {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// Resolve returns the definitions of the entity the reference refers to, or
+// nil if the entity is not defined in the indexed files.
+func (idx *Index) Resolve(ref *Reference) []*Symbol {
+	var result []*Symbol
+	for _, s := range idx.Lookup(ref.Name) {
+		if refersTo(ref, s) {
+			result = append(result, s)
+		}
+	}
+	return result
+}
+
+// References returns the references to the entity with the given name or ID,
+// sorted by path and location. If the entity is defined in the indexed files,
+// references to it by either its name or its ID are returned. Otherwise, such
+// as for base FHIR resources, the references that use the name as written are
+// returned.
+func (idx *Index) References(name string) []*Reference {
+	definitions := idx.Lookup(name)
+
+	idx.mu.RLock()
+	var result []*Reference
+	for _, f := range idx.files {
+		for _, ref := range f.references {
+			if len(definitions) == 0 && ref.Name == name ||
+				slices.ContainsFunc(definitions, func(s *Symbol) bool { return refersTo(ref, s) }) {
+				result = append(result, ref)
+			}
+		}
+	}
+	idx.mu.RUnlock()
+
+	sortByLocation(result, func(r *Reference) (string, *ast.Location) { return r.Path, r.Location })
+	return result
+}
+
+// ReferencesTo returns the references to the entity of the symbol that are
+// written as name, sorted by path and location. Unlike References, the name
+// does not need to be the current name or ID of the entity, such as to find the
+// references to an entity by its former ID.
+func (idx *Index) ReferencesTo(s *Symbol, name string) []*Reference {
+	idx.mu.RLock()
+	var result []*Reference
+	for _, f := range idx.files {
+		for _, ref := range f.references {
+			if ref.Name == name && slices.Contains(targetKinds[ref.Kind], s.Kind) {
+				result = append(result, ref)
+			}
+		}
+	}
+	idx.mu.RUnlock()
+
+	sortByLocation(result, func(r *Reference) (string, *ast.Location) { return r.Path, r.Location })
+	return result
+}
+
+// refersTo returns true if the reference refers to the symbol.
+func refersTo(ref *Reference, s *Symbol) bool {
+	return (ref.Name == s.Name || ref.Name == s.ID) && slices.Contains(targetKinds[ref.Kind], s.Kind)
+}
+
+// ReferencesAt returns the references of the file at the given path on the
+// given line, which is 1-based like the lines of locations.
+func (idx *Index) ReferencesAt(path string, line int) []*Reference {
+	idx.mu.RLock()
+	defer idx.mu.RUnlock()
+	f, ok := idx.files[path]
+	if !ok {
+		return nil
+	}
+	var result []*Reference
+	for _, ref := range f.references {
+		if ref.Location.Start.LineNumber <= line && line <= ref.Location.End.LineNumber {
+			result = append(result, ref)
+		}
+	}
+	return result
+}
+
+// builder collects the symbols and references of a document.
+type builder struct {
+	path   string
+	file   *file
+	entity string
+}
+
+// symbol adds a symbol for the entity with the given name and ID, and makes it
+// the entity that contains the following references.
+func (b *builder) symbol(kind Kind, name, id *ast.ParsedElement[string]) {
+	if name == nil || name.Location == nil {
+		return
+	}
+	s := &Symbol{Name: name.Value, Kind: kind, Path: b.path, Location: name.Location}
+	if id != nil {
+		s.ID = id.Value
+		s.IDLocation = id.Location
+	}
+	b.file.symbols = append(b.file.symbols, s)
+	b.entity = name.Value
+}
+
+// reference adds a reference from the current entity.
+func (b *builder) reference(kind ReferenceKind, name *ast.ParsedElement[string]) {
+	if name == nil || name.Value == "" || name.Location == nil {
+		return
+	}
+	b.file.references = append(b.file.references, &Reference{
+		Name:     name.Value,
+		Kind:     kind,
+		From:     b.entity,
+		Path:     b.path,
+		Location: name.Location,
+	})
+}
+
+func (b *builder) document(doc *ast.FSHDocument) {
+	for _, p := range doc.Profiles {
+		b.symbol(KindProfile, p.Name, p.ID)
+		b.reference(ReferenceParent, p.Parent)
+		b.structureDefRules(p.ProfileRules)
+	}
+	for _, e := range doc.Extensions {
+		b.symbol(KindExtension, e.Name, e.ID)
+		b.reference(ReferenceParent, e.Parent)
+		b.structureDefRules(e.ExtensionRules)
+	}
+	for _, i := range doc.Instances {
+		b.symbol(KindInstance, i.Name, nil)
+		b.reference(ReferenceInstanceOf, i.InstanceOf)
+		if i.InstanceRules != nil {
+			b.insertRules(i.InstanceRules.InsertRules)
+		}
+	}
+	for _, vs := range doc.ValueSets {
+		b.symbol(KindValueSet, vs.Name, vs.ID)
+		for _, component := range slices.Concat(vs.IncludeComponents, vs.ExcludeComponents) {
+			if component.FromCodeSystem != nil {
+				b.reference(ReferenceIncludeCodeSystem, component.FromCodeSystem.Name)
+			}
+			for _, source := range component.FromValueSet {
+				b.reference(ReferenceIncludeValueSet, source.Name)
+			}
+		}
+		if vs.ValueSetRules != nil {
+			b.insertRules(vs.ValueSetRules.InsertRules)
+			for _, rule := range vs.ValueSetRules.CodeInsertRules {
+				b.reference(ReferenceInsert, rule.RuleSetName)
+			}
+		}
+	}
+	for _, cs := range doc.CodeSystems {
+		b.symbol(KindCodeSystem, cs.Name, cs.ID)
+	}
+	for _, rs := range doc.RuleSets {
+		b.symbol(KindRuleSet, rs.Name, nil)
+	}
+	for _, inv := range doc.Invariants {
+		b.symbol(KindInvariant, inv.Name, nil)
+	}
+}
+
+func (b *builder) structureDefRules(rules *ast.StructureDefRules) {
+	if rules == nil {
+		return
+	}
+	for _, rule := range rules.BindingRules {
+		b.reference(ReferenceBinding, rule.ValueSet)
+	}
+	for _, rule := range rules.ObeysRules {
+		for _, invariant := range rule.Invariants {
+			b.reference(ReferenceObeys, invariant)
+		}
+	}
+	for _, rule := range rules.ContainsRules {
+		if !isExtensionPath(rule.Name) {
+			continue
+		}
+		for _, item := range rule.Items {
+			b.reference(ReferenceContains, item.Name)
+		}
+	}
+	b.insertRules(rules.InsertRules)
+}
+
+func (b *builder) insertRules(rules []*ast.InsertRule) {
+	for _, rule := range rules {
+		b.reference(ReferenceInsert, rule.RuleSetName)
+	}
+}
+
+// isExtensionPath returns true if the path of a contains rule is an extension
+// element, whose items are extensions rather than slices.
+func isExtensionPath(p *ast.ParsedElement[string]) bool {
+	if p == nil {
+		return false
+	}
+	last := path.Base(strings.ReplaceAll(p.Value, ".", "/"))
+	return last == "extension" || last == "modifierExtension"
+}
+
+// sortByLocation sorts the items by path, then by the start of their location.
+func sortByLocation[T any](items []T, key func(T) (string, *ast.Location)) {
+	sort.SliceStable(items, func(i, j int) bool {
+		pi, li := key(items[i])
+		pj, lj := key(items[j])
+		if pi != pj {
+			return pi < pj
+		}
+		if li.Start.LineNumber != lj.Start.LineNumber {
+			return li.Start.LineNumber < lj.Start.LineNumber
+		}
+		return li.Start.ColumnNumber < lj.Start.ColumnNumber
+	})
+}
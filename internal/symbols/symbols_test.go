@@ -0,0 +1,231 @@
+package symbols_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/fsh"
+	"github.com/verily-src/fsh-lint/internal/symbols"
+)
+
+var files = map[string]string{
+	"Profiles.fsh": `Profile: BaseProfile
+Parent: Patient
+Id: base-profile
+* insert MetadataRules
+
+Profile: DerivedProfile
+Parent: base-profile
+* gender from GenderValueSet (required)
+* obeys dp-1 and dp-2
+* extension contains BirthPlace named birthPlace 0..1
+
+Extension: BirthPlace
+* insert NamedRules(birth, "Birth Place")
+
+Instance: ExampleDerived
+InstanceOf: DerivedProfile
+* insert MetadataRules
+`,
+	"Terminology.fsh": `CodeSystem: GenderCodes
+Id: gender-codes
+
+ValueSet: GenderValueSet
+* include codes from system GenderCodes
+* include codes from valueset OtherValueSet
+
+RuleSet: MetadataRules
+* ^status = #active
+
+RuleSet: NamedRules(name, title)
+* ^name = "{name}"
+
+Invariant: dp-1
+Description: "Must have a name."
+Severity: #error
+`,
+}
+
+func newIndex(t *testing.T) *symbols.Index {
+	t.Helper()
+	idx := symbols.NewIndex()
+	for path, data := range files {
+		doc, err := fsh.Parse(data)
+		if err != nil {
+			t.Fatalf("Parse(%s) error: %v", path, err)
+		}
+		idx.Add(path, doc)
+	}
+	return idx
+}
+
+// symbolString returns a compact string of a symbol, for comparison.
+func symbolString(s *symbols.Symbol) string {
+	return fmt.Sprintf("%s:%d %s %s", s.Path, s.Location.Start.LineNumber, s.Kind, s.Name)
+}
+
+// referenceString returns a compact string of a reference, for comparison.
+func referenceString(r *symbols.Reference) string {
+	return fmt.Sprintf("%s:%d %s %s in %s", r.Path, r.Location.Start.LineNumber, r.Kind, r.Name, r.From)
+}
+
+func TestIndex_Symbols(t *testing.T) {
+	idx := newIndex(t)
+
+	var got []string
+	for _, s := range idx.Symbols("Profiles.fsh") {
+		got = append(got, symbolString(s))
+	}
+	want := []string{
+		"Profiles.fsh:1 Profile BaseProfile",
+		"Profiles.fsh:6 Profile DerivedProfile",
+		"Profiles.fsh:12 Extension BirthPlace",
+		"Profiles.fsh:15 Instance ExampleDerived",
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Symbols() mismatch (-got +want):\n%s", diff)
+	}
+
+	if got, want := len(idx.AllSymbols()), 9; got != want {
+		t.Errorf("len(AllSymbols()) = %d, want %d", got, want)
+	}
+}
+
+func TestIndex_Resolve(t *testing.T) {
+	idx := newIndex(t)
+
+	// Every reference in the profiles resolves, except those to base FHIR
+	// definitions and undefined entities.
+	got := make(map[string][]string)
+	for _, path := range []string{"Profiles.fsh", "Terminology.fsh"} {
+		for line := 1; line <= 20; line++ {
+			for _, ref := range idx.ReferencesAt(path, line) {
+				var defs []string
+				for _, s := range idx.Resolve(ref) {
+					defs = append(defs, symbolString(s))
+				}
+				got[referenceString(ref)] = defs
+			}
+		}
+	}
+	want := map[string][]string{
+		"Profiles.fsh:2 Parent Patient in BaseProfile":                nil,
+		"Profiles.fsh:4 insert MetadataRules in BaseProfile":          {"Terminology.fsh:8 RuleSet MetadataRules"},
+		"Profiles.fsh:7 Parent base-profile in DerivedProfile":        {"Profiles.fsh:1 Profile BaseProfile"},
+		"Profiles.fsh:8 from GenderValueSet in DerivedProfile":        {"Terminology.fsh:4 ValueSet GenderValueSet"},
+		"Profiles.fsh:9 obeys dp-1 in DerivedProfile":                 {"Terminology.fsh:14 Invariant dp-1"},
+		"Profiles.fsh:9 obeys dp-2 in DerivedProfile":                 nil,
+		"Profiles.fsh:10 contains BirthPlace in DerivedProfile":       {"Profiles.fsh:12 Extension BirthPlace"},
+		"Profiles.fsh:13 insert NamedRules in BirthPlace":             {"Terminology.fsh:11 RuleSet NamedRules"},
+		"Profiles.fsh:16 InstanceOf DerivedProfile in ExampleDerived": {"Profiles.fsh:6 Profile DerivedProfile"},
+		"Profiles.fsh:17 insert MetadataRules in ExampleDerived":      {"Terminology.fsh:8 RuleSet MetadataRules"},
+		"Terminology.fsh:5 system GenderCodes in GenderValueSet":      {"Terminology.fsh:1 CodeSystem GenderCodes"},
+		"Terminology.fsh:6 valueset OtherValueSet in GenderValueSet":  nil,
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("Resolve() mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestIndex_References(t *testing.T) {
+	idx := newIndex(t)
+
+	tests := []struct {
+		name string
+		want []string
+	}{
+		{
+			name: "MetadataRules",
+			want: []string{
+				"Profiles.fsh:4 insert MetadataRules in BaseProfile",
+				"Profiles.fsh:17 insert MetadataRules in ExampleDerived",
+			},
+		},
+		{
+			// References by ID are found from the name, and vice versa.
+			name: "BaseProfile",
+			want: []string{"Profiles.fsh:7 Parent base-profile in DerivedProfile"},
+		},
+		{
+			name: "base-profile",
+			want: []string{"Profiles.fsh:7 Parent base-profile in DerivedProfile"},
+		},
+		{
+			// Undefined entities are found by name.
+			name: "Patient",
+			want: []string{"Profiles.fsh:2 Parent Patient in BaseProfile"},
+		},
+		{
+			name: "Unused",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, ref := range idx.References(tt.name) {
+				got = append(got, referenceString(ref))
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("References(%q) mismatch (-got +want):\n%s", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestIndex_ReferencesTo(t *testing.T) {
+	idx := newIndex(t)
+	base := idx.Lookup("BaseProfile")[0]
+
+	tests := []struct {
+		name   string
+		symbol *symbols.Symbol
+		want   []string
+	}{
+		{
+			// Only references written as the name are returned.
+			name:   "base-profile",
+			symbol: base,
+			want:   []string{"Profiles.fsh:7 Parent base-profile in DerivedProfile"},
+		},
+		{
+			name:   "BaseProfile",
+			symbol: base,
+		},
+		{
+			// Former names are found, if the reference can refer to the kind of
+			// the symbol.
+			name:   "GenderValueSet",
+			symbol: idx.Lookup("GenderCodes")[0],
+		},
+		{
+			name:   "OtherValueSet",
+			symbol: idx.Lookup("GenderValueSet")[0],
+			want:   []string{"Terminology.fsh:6 valueset OtherValueSet in GenderValueSet"},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			var got []string
+			for _, ref := range idx.ReferencesTo(tt.symbol, tt.name) {
+				got = append(got, referenceString(ref))
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("ReferencesTo(%q) mismatch (-got +want):\n%s", tt.name, diff)
+			}
+		})
+	}
+}
+
+func TestIndex_Remove(t *testing.T) {
+	idx := newIndex(t)
+	idx.Remove("Terminology.fsh")
+
+	if got := idx.Lookup("MetadataRules"); len(got) != 0 {
+		t.Errorf("Lookup() after Remove() = %v, want none", got)
+	}
+	// References from the remaining file are still found by name.
+	if got, want := len(idx.References("MetadataRules")), 2; got != want {
+		t.Errorf("len(References()) after Remove() = %d, want %d", got, want)
+	}
+}
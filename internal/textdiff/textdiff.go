@@ -0,0 +1,169 @@
+// Package textdiff computes line-based differences between two versions of a
+// file, and formats them as unified diffs.
+package textdiff
+
+import (
+	"fmt"
+	"path/filepath"
+	"slices"
+	"strings"
+)
+
+// contextLines is the number of unchanged lines shown around each change.
+const contextLines = 3
+
+// noNewline marks a last line that does not end with a newline.
+const noNewline = "\\ No newline at end of file\n"
+
+// op is a single line of a diff: an unchanged, deleted, or inserted line.
+type op struct {
+	kind byte // ' ', '-', or '+'
+	line string
+
+	// oldLine and newLine are the 0-based line numbers of the line before the
+	// op in the old and new versions.
+	oldLine, newLine int
+}
+
+// Unified returns the unified diff that turns old into new, with a/ and b/
+// prefixed headers for the given path, or an empty string if they are equal.
+func Unified(path string, old, new []byte) string {
+	if string(old) == string(new) {
+		return ""
+	}
+	ops := diffLines(splitLines(string(old)), splitLines(string(new)))
+
+	path = filepath.ToSlash(path)
+	var b strings.Builder
+	fmt.Fprintf(&b, "--- a/%s\n+++ b/%s\n", path, path)
+	for _, hunk := range hunks(ops) {
+		first := hunk[0]
+		oldCount, newCount := 0, 0
+		for _, o := range hunk {
+			if o.kind != '+' {
+				oldCount++
+			}
+			if o.kind != '-' {
+				newCount++
+			}
+		}
+		fmt.Fprintf(&b, "@@ -%s +%s @@\n", hunkRange(first.oldLine, oldCount), hunkRange(first.newLine, newCount))
+		for _, o := range hunk {
+			b.WriteByte(o.kind)
+			b.WriteString(o.line)
+			if !strings.HasSuffix(o.line, "\n") {
+				b.WriteString("\n" + noNewline)
+			}
+		}
+	}
+	return b.String()
+}
+
+// hunkRange returns the range of a hunk header for count lines after the
+// 0-based line start. Empty ranges refer to the line before them.
+func hunkRange(start, count int) string {
+	if count == 0 {
+		return fmt.Sprintf("%d,0", start)
+	}
+	if count == 1 {
+		return fmt.Sprintf("%d", start+1)
+	}
+	return fmt.Sprintf("%d,%d", start+1, count)
+}
+
+// splitLines splits s into lines that keep their newline. The last line has no
+// newline if s does not end with one.
+func splitLines(s string) []string {
+	lines := strings.SplitAfter(s, "\n")
+	if lines[len(lines)-1] == "" {
+		lines = lines[:len(lines)-1]
+	}
+	return lines
+}
+
+// hunks groups the changes of ops with up to contextLines unchanged lines around
+// them. Changes separated by at most twice as many unchanged lines share a hunk.
+func hunks(ops []op) [][]op {
+	var result [][]op
+	start, last := -1, -1
+	for i, o := range ops {
+		if o.kind == ' ' {
+			continue
+		}
+		if start >= 0 && i-last-1 > 2*contextLines {
+			result = append(result, ops[start:last+1+contextLines])
+			start = -1
+		}
+		if start < 0 {
+			start = max(0, i-contextLines)
+		}
+		last = i
+	}
+	if start >= 0 {
+		result = append(result, ops[start:min(len(ops), last+1+contextLines)])
+	}
+	return result
+}
+
+// diffLines returns the ops that turn a into b with the fewest deletions and
+// insertions, using Myers' algorithm.
+func diffLines(a, b []string) []op {
+	n, m := len(a), len(b)
+	offset := n + m + 1
+	v := make([]int, 2*offset+1)
+	var trace [][]int
+
+search:
+	for d := 0; d <= n+m; d++ {
+		trace = append(trace, slices.Clone(v))
+		for k := -d; k <= d; k += 2 {
+			var x int
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				x = v[offset+k+1]
+			} else {
+				x = v[offset+k-1] + 1
+			}
+			y := x - k
+			for x < n && y < m && a[x] == b[y] {
+				x, y = x+1, y+1
+			}
+			v[offset+k] = x
+			if x >= n && y >= m {
+				break search
+			}
+		}
+	}
+
+	// Walk back from the end through the furthest reaching paths.
+	var ops []op
+	x, y := n, m
+	for d := len(trace) - 1; d >= 0; d-- {
+		v := trace[d]
+		k := x - y
+		prevX, prevY := 0, 0
+		if d > 0 {
+			prevK := k - 1
+			if k == -d || k != d && v[offset+k-1] < v[offset+k+1] {
+				prevK = k + 1
+			}
+			prevX = v[offset+prevK]
+			prevY = prevX - prevK
+		}
+		for x > prevX && y > prevY {
+			x, y = x-1, y-1
+			ops = append(ops, op{kind: ' ', line: a[x], oldLine: x, newLine: y})
+		}
+		if d == 0 {
+			break
+		}
+		if x == prevX {
+			y--
+			ops = append(ops, op{kind: '+', line: b[y], oldLine: x, newLine: y})
+		} else {
+			x--
+			ops = append(ops, op{kind: '-', line: a[x], oldLine: x, newLine: y})
+		}
+	}
+	slices.Reverse(ops)
+	return ops
+}
@@ -0,0 +1,130 @@
+package textdiff_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/textdiff"
+)
+
+const lines = "1\n2\n3\n4\n5\n6\n7\n8\n9\n10\n11\n12\n"
+
+func TestUnified(t *testing.T) {
+	tests := []struct {
+		name string
+		old  string
+		new  string
+		want string
+	}{
+		{
+			name: "equal",
+			old:  lines,
+			new:  lines,
+		},
+		{
+			name: "replaced line",
+			old:  lines,
+			new:  "1\n2\n3\n4\n5\nsix\n7\n8\n9\n10\n11\n12\n",
+			want: `--- a/Example.fsh
++++ b/Example.fsh
+@@ -3,7 +3,7 @@
+ 3
+ 4
+ 5
+-6
++six
+ 7
+ 8
+ 9
+`,
+		},
+		{
+			name: "inserted first line",
+			old:  lines,
+			new:  "0\n" + lines,
+			want: `--- a/Example.fsh
++++ b/Example.fsh
+@@ -1,3 +1,4 @@
++0
+ 1
+ 2
+ 3
+`,
+		},
+		{
+			name: "distant changes",
+			old:  lines,
+			new:  "one\n2\n3\n4\n5\n6\n7\n8\n9\n10\n12\n",
+			want: `--- a/Example.fsh
++++ b/Example.fsh
+@@ -1,4 +1,4 @@
+-1
++one
+ 2
+ 3
+ 4
+@@ -8,5 +8,4 @@
+ 8
+ 9
+ 10
+-11
+ 12
+`,
+		},
+		{
+			name: "close changes",
+			old:  lines,
+			new:  "1\ntwo\n3\n4\n5\n6\n7\n8\nnine\n10\n11\n12\n",
+			want: `--- a/Example.fsh
++++ b/Example.fsh
+@@ -1,12 +1,12 @@
+ 1
+-2
++two
+ 3
+ 4
+ 5
+ 6
+ 7
+ 8
+-9
++nine
+ 10
+ 11
+ 12
+`,
+		},
+		{
+			name: "no newline at end of file",
+			old:  "1\n2",
+			new:  "1\n2\n",
+			want: `--- a/Example.fsh
++++ b/Example.fsh
+@@ -1,2 +1,2 @@
+ 1
+-2
+\ No newline at end of file
++2
+`,
+		},
+		{
+			name: "empty file",
+			old:  "",
+			new:  "1\n",
+			want: `--- a/Example.fsh
++++ b/Example.fsh
+@@ -0,0 +1 @@
++1
+`,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got := textdiff.Unified("Example.fsh", []byte(tt.old), []byte(tt.new))
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("Unified() mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
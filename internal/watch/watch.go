@@ -0,0 +1,268 @@
+// Package watch notifies of changes to files in a set of directories, using
+// file system notifications where available and polling otherwise.
+package watch
+
+import (
+	"io/fs"
+	"os"
+	"path/filepath"
+	"slices"
+	"strings"
+	"sync"
+	"time"
+
+	"github.com/fsnotify/fsnotify"
+)
+
+const (
+	// settleDelay is how long the watcher waits for further changes before
+	// notifying, so that a burst of changes, such as an editor saving a file
+	// or a branch checkout, results in a single notification.
+	settleDelay = 100 * time.Millisecond
+
+	// PollInterval is how often the polling watcher checks for changes.
+	PollInterval = time.Second
+)
+
+// Watcher watches the files in a set of directories, and their subdirectories,
+// for changes.
+type Watcher struct {
+	// Changes receives a value once files have changed and the changes have
+	// settled.
+	Changes <-chan struct{}
+
+	// Errors receives errors of the underlying file system watcher.
+	Errors <-chan error
+
+	// Polling is true if the watcher falls back to polling, because file
+	// system notifications are unavailable or polling was requested.
+	Polling bool
+
+	match   func(path string) bool
+	raw     chan struct{}
+	errors  chan error
+	done    chan struct{}
+	closer  func() error
+	closeMu sync.Once
+}
+
+// New returns a Watcher of the files matching match in the given roots. Roots
+// may be directories or files; the directory of a file root is watched. If poll
+// is true, or file system notifications are unavailable, the watcher polls the
+// roots every PollInterval instead.
+func New(roots []string, match func(path string) bool, poll bool) *Watcher {
+	changes := make(chan struct{}, 1)
+	w := &Watcher{
+		Changes: changes,
+		match:   match,
+		raw:     make(chan struct{}, 1),
+		errors:  make(chan error, 1),
+		done:    make(chan struct{}),
+	}
+	w.Errors = w.errors
+
+	dirs := watchedDirs(roots)
+	started := false
+	if !poll {
+		if err := w.startNotify(dirs); err == nil {
+			started = true
+		}
+	}
+	if !started {
+		w.Polling = true
+		w.startPoll(dirs)
+	}
+
+	go w.settle(changes)
+	return w
+}
+
+// Close stops the watcher.
+func (w *Watcher) Close() error {
+	var err error
+	w.closeMu.Do(func() {
+		close(w.done)
+		if w.closer != nil {
+			err = w.closer()
+		}
+	})
+	return err
+}
+
+// notify records that a matching file changed.
+func (w *Watcher) notify() {
+	select {
+	case w.raw <- struct{}{}:
+	default:
+	}
+}
+
+// settle forwards raw changes to changes once no further change happened for
+// settleDelay.
+func (w *Watcher) settle(changes chan<- struct{}) {
+	var timer <-chan time.Time
+	for {
+		select {
+		case <-w.done:
+			return
+		case <-w.raw:
+			timer = time.After(settleDelay)
+		case <-timer:
+			timer = nil
+			select {
+			case changes <- struct{}{}:
+			default:
+			}
+		}
+	}
+}
+
+// startNotify watches the directories with file system notifications.
+func (w *Watcher) startNotify(dirs []string) error {
+	watcher, err := fsnotify.NewWatcher()
+	if err != nil {
+		return err
+	}
+	for _, dir := range dirs {
+		if err := addRecursive(watcher, dir); err != nil {
+			_ = watcher.Close()
+			return err
+		}
+	}
+	w.closer = watcher.Close
+
+	go func() {
+		for {
+			select {
+			case event, ok := <-watcher.Events:
+				if !ok {
+					return
+				}
+				// New directories are not watched automatically.
+				if event.Has(fsnotify.Create) {
+					if info, err := os.Stat(event.Name); err == nil && info.IsDir() {
+						_ = addRecursive(watcher, event.Name)
+						w.notify()
+						continue
+					}
+				}
+				if w.match(event.Name) {
+					w.notify()
+				}
+			case err, ok := <-watcher.Errors:
+				if !ok {
+					return
+				}
+				select {
+				case w.errors <- err:
+				default:
+				}
+			}
+		}
+	}()
+	return nil
+}
+
+// addRecursive adds the directory and all of its subdirectories to watcher.
+func addRecursive(watcher *fsnotify.Watcher, dir string) error {
+	return filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+		if err != nil {
+			return err
+		}
+		if d.IsDir() {
+			return watcher.Add(path)
+		}
+		return nil
+	})
+}
+
+// fileState is the state of a file compared by the polling watcher.
+type fileState struct {
+	modTime time.Time
+	size    int64
+}
+
+// startPoll polls the directories every PollInterval.
+func (w *Watcher) startPoll(dirs []string) {
+	previous := w.snapshot(dirs)
+	go func() {
+		ticker := time.NewTicker(PollInterval)
+		defer ticker.Stop()
+		for {
+			select {
+			case <-w.done:
+				return
+			case <-ticker.C:
+				current := w.snapshot(dirs)
+				if changed(previous, current) {
+					w.notify()
+				}
+				previous = current
+			}
+		}
+	}()
+}
+
+// snapshot returns the state of the matching files in the directories.
+func (w *Watcher) snapshot(dirs []string) map[string]fileState {
+	files := make(map[string]fileState)
+	for _, dir := range dirs {
+		_ = filepath.WalkDir(dir, func(path string, d fs.DirEntry, err error) error {
+			if err != nil || d.IsDir() || !w.match(path) {
+				return nil
+			}
+			if info, err := d.Info(); err == nil {
+				files[path] = fileState{modTime: info.ModTime(), size: info.Size()}
+			}
+			return nil
+		})
+	}
+	return files
+}
+
+// changed returns true if any file was added, removed, or modified.
+func changed(previous, current map[string]fileState) bool {
+	if len(previous) != len(current) {
+		return true
+	}
+	for path, state := range current {
+		if previous[path] != state {
+			return true
+		}
+	}
+	return false
+}
+
+// watchedDirs returns the directories to watch for the given roots, without
+// duplicates or directories nested in other watched directories.
+func watchedDirs(roots []string) []string {
+	var dirs []string
+	for _, root := range roots {
+		dir := root
+		if info, err := os.Stat(root); err != nil || !info.IsDir() {
+			dir = filepath.Dir(root)
+		}
+		dirs = append(dirs, filepath.Clean(dir))
+	}
+
+	var result []string
+	for _, dir := range dirs {
+		nested := false
+		for _, other := range dirs {
+			if other != dir && isWithin(dir, other) {
+				nested = true
+				break
+			}
+		}
+		if !nested && !slices.Contains(result, dir) {
+			result = append(result, dir)
+		}
+	}
+	return result
+}
+
+// isWithin returns true if dir is inside of parent.
+func isWithin(dir, parent string) bool {
+	rel, err := filepath.Rel(parent, dir)
+	return err == nil && rel != "." && rel != ".." && !strings.HasPrefix(rel, ".."+string(filepath.Separator))
+}
@@ -0,0 +1,80 @@
+package watch
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+	"time"
+
+	"github.com/google/go-cmp/cmp"
+)
+
+func isFSH(path string) bool {
+	return filepath.Ext(path) == ".fsh"
+}
+
+func TestWatcher_Changes(t *testing.T) {
+	testCases := []struct {
+		name string
+		poll bool
+	}{
+		{name: "file system notifications", poll: false},
+		{name: "polling", poll: true},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			sub := filepath.Join(dir, "profiles")
+			if err := os.Mkdir(sub, 0755); err != nil {
+				t.Fatal(err)
+			}
+			w := New([]string{dir}, isFSH, tc.poll)
+			defer func() { _ = w.Close() }()
+			if w.Polling != tc.poll {
+				t.Skipf("Polling: got %t, want %t, file system notifications are unavailable", w.Polling, tc.poll)
+			}
+
+			// Files that do not match do not trigger a change.
+			if err := os.WriteFile(filepath.Join(sub, "notes.txt"), []byte("notes"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			select {
+			case <-w.Changes:
+				t.Fatalf("Changes: got change for non-matching file, want none")
+			case <-time.After(PollInterval + 2*settleDelay):
+			}
+
+			if err := os.WriteFile(filepath.Join(sub, "Example.fsh"), []byte("Profile: Example"), 0644); err != nil {
+				t.Fatal(err)
+			}
+			select {
+			case <-w.Changes:
+			case <-time.After(2*PollInterval + 2*settleDelay):
+				t.Fatalf("Changes: got no change for matching file, want one")
+			}
+		})
+	}
+}
+
+func TestWatchedDirs(t *testing.T) {
+	dir := t.TempDir()
+	file := filepath.Join(dir, "a", "Example.fsh")
+	if err := os.MkdirAll(filepath.Dir(file), 0755); err != nil {
+		t.Fatal(err)
+	}
+	if err := os.WriteFile(file, nil, 0644); err != nil {
+		t.Fatal(err)
+	}
+	other := filepath.Join(dir, "b")
+	if err := os.Mkdir(other, 0755); err != nil {
+		t.Fatal(err)
+	}
+
+	got := watchedDirs([]string{file, filepath.Join(dir, "a"), other, other + string(filepath.Separator), filepath.Join(other, "c")})
+
+	want := []string{filepath.Join(dir, "a"), other}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("watchedDirs() mismatch (-got +want):\n%s", diff)
+	}
+}
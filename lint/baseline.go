@@ -0,0 +1,279 @@
+package lint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"path/filepath"
+	"slices"
+	"sort"
+	"strings"
+	"sync"
+
+	"github.com/verily-src/fsh-lint/ast"
+)
+
+// baselineVersion is the version of the baseline file format.
+const baselineVersion = 1
+
+// BaselineEntry identifies a single problem recorded in a baseline.
+type BaselineEntry struct {
+	// Rule is the ID of the rule that found the problem.
+	Rule string `json:"rule"`
+
+	// File is the slash-separated path of the file the problem was found in.
+	File string `json:"file"`
+
+	// Entity is the name of the entity the problem was found in, if any.
+	Entity string `json:"entity,omitempty"`
+
+	// Fingerprint identifies the problem by its message and the content of the
+	// lines it spans, so that it is robust to line shifts.
+	Fingerprint string `json:"fingerprint"`
+
+	// Message is the message of the problem, for readers of the baseline.
+	Message string `json:"message"`
+}
+
+// Baseline is a set of known problems that are hidden from the lint results,
+// so that existing problems can be grandfathered while new ones are reported.
+type Baseline struct {
+	Version int              `json:"version"`
+	Entries []*BaselineEntry `json:"entries"`
+
+	// mu guards Entries and matched, since files are linted concurrently.
+	mu sync.Mutex
+
+	// matched is the set of entries that matched a problem found while linting.
+	matched map[*BaselineEntry]bool
+}
+
+// NewBaseline returns an empty baseline.
+func NewBaseline() *Baseline {
+	return &Baseline{Version: baselineVersion}
+}
+
+// LoadBaseline reads the baseline file at the given path.
+func LoadBaseline(path string) (*Baseline, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+
+	b := &Baseline{}
+	if err := json.Unmarshal(data, b); err != nil {
+		return nil, fmt.Errorf("parsing baseline %s: %w", path, err)
+	}
+	if b.Version != baselineVersion {
+		return nil, fmt.Errorf("unsupported baseline version %d in %s, want %d", b.Version, path, baselineVersion)
+	}
+	return b, nil
+}
+
+// Write writes the baseline to the file at the given path, with the entries
+// sorted so that the file is stable across runs.
+func (b *Baseline) Write(path string) error {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	sort.SliceStable(b.Entries, func(i, j int) bool {
+		x, y := b.Entries[i], b.Entries[j]
+		if x.File != y.File {
+			return x.File < y.File
+		}
+		if x.Entity != y.Entity {
+			return x.Entity < y.Entity
+		}
+		if x.Rule != y.Rule {
+			return x.Rule < y.Rule
+		}
+		return x.Fingerprint < y.Fingerprint
+	})
+
+	data, err := json.MarshalIndent(b, "", "  ")
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(path, append(data, '\n'), 0644)
+}
+
+// Add records the given entry in the baseline.
+func (b *Baseline) Add(entry *BaselineEntry) {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.Entries = append(b.Entries, entry)
+}
+
+// reset marks every entry as unmatched, so that the baseline hides the same
+// problems on every run of a linter that is reused. Does nothing for a nil
+// baseline.
+func (b *Baseline) reset() {
+	if b == nil {
+		return
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	b.matched = nil
+}
+
+// match marks the first unmatched entry equal to the given entry as matched.
+// Returns false if there is no such entry.
+func (b *Baseline) match(entry *BaselineEntry) bool {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, e := range b.Entries {
+		if b.matched[e] || e.File != entry.File || e.Rule != entry.Rule ||
+			e.Entity != entry.Entity || e.Fingerprint != entry.Fingerprint {
+			continue
+		}
+		if b.matched == nil {
+			b.matched = make(map[*BaselineEntry]bool)
+		}
+		b.matched[e] = true
+		return true
+	}
+	return false
+}
+
+// contains returns true if the baseline has an entry for the problem of the
+// given entry, whether or not the entry was already matched. Returns false for
+// a nil baseline.
+func (b *Baseline) contains(entry *BaselineEntry) bool {
+	if b == nil {
+		return false
+	}
+	b.mu.Lock()
+	defer b.mu.Unlock()
+	for _, e := range b.Entries {
+		if e.File == entry.File && e.Rule == entry.Rule && e.Entity == entry.Entity && e.Fingerprint == entry.Fingerprint {
+			return true
+		}
+	}
+	return false
+}
+
+// unmatched returns the entries of the file at the given path that did not
+// match any problem.
+func (b *Baseline) unmatched(path string) []*BaselineEntry {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	file := baselinePath(path)
+	var result []*BaselineEntry
+	for _, e := range b.Entries {
+		if e.File == file && !b.matched[e] {
+			result = append(result, e)
+		}
+	}
+	return result
+}
+
+// files returns the paths of the files that the baseline has entries for, in
+// the slash-separated form used in baselines.
+func (b *Baseline) files() []string {
+	b.mu.Lock()
+	defer b.mu.Unlock()
+
+	var files []string
+	for _, e := range b.Entries {
+		if !slices.Contains(files, e.File) {
+			files = append(files, e.File)
+		}
+	}
+	sort.Strings(files)
+	return files
+}
+
+// NewBaselineEntry returns the baseline entry of the given problem found in the
+// given file.
+func NewBaselineEntry(fc *FileContext, problem *Problem) *BaselineEntry {
+	entry := &BaselineEntry{
+		Rule:    problem.RuleID,
+		File:    baselinePath(fc.Path),
+		Message: problem.Message,
+	}
+
+	var source string
+	if start := problem.StartPosition(); start != nil {
+		end := start
+		if problem.EndPosition() != nil {
+			end = problem.EndPosition()
+		}
+		entry.Entity = entityAt(fc.ParsedFSH, start.LineNumber)
+		source = sourceLines(fc.Data, start.LineNumber, end.LineNumber)
+	} else if names := entityNames(fc.ParsedFSH); len(names) == 1 {
+		// A problem without a location belongs to the only entity of the file.
+		entry.Entity = names[0].Value
+	}
+
+	hash := sha256.Sum256([]byte(strings.Join([]string{entry.Rule, entry.Entity, entry.Message, source}, "\x00")))
+	entry.Fingerprint = hex.EncodeToString(hash[:8])
+	return entry
+}
+
+// baselinePath returns path in the slash-separated form used in baselines.
+func baselinePath(path string) string {
+	return filepath.ToSlash(filepath.Clean(path))
+}
+
+// sourceLines returns the lines from start to end, inclusive, with surrounding
+// whitespace removed from each line.
+func sourceLines(data []byte, start, end int) string {
+	lines := strings.Split(string(data), "\n")
+	var result []string
+	for i := start; i <= end && i <= len(lines); i++ {
+		if i >= 1 {
+			result = append(result, strings.TrimSpace(lines[i-1]))
+		}
+	}
+	return strings.Join(result, "\n")
+}
+
+// entityAt returns the name of the entity declared closest before the given
+// line, or an empty string if there is none.
+func entityAt(doc *ast.FSHDocument, line int) string {
+	var entity string
+	closest := 0
+	for _, name := range entityNames(doc) {
+		if name.Location == nil || name.Location.Start == nil {
+			continue
+		}
+		declared := name.Location.Start.LineNumber
+		if declared <= line && declared > closest {
+			entity, closest = name.Value, declared
+		}
+	}
+	return entity
+}
+
+// entityNames returns the names of all entities declared in the document.
+func entityNames(doc *ast.FSHDocument) []*ast.ParsedElement[string] {
+	if doc == nil {
+		return nil
+	}
+
+	var names []*ast.ParsedElement[string]
+	add := func(name *ast.ParsedElement[string]) {
+		if name != nil {
+			names = append(names, name)
+		}
+	}
+	for _, vs := range doc.ValueSets {
+		add(vs.Name)
+	}
+	for _, p := range doc.Profiles {
+		add(p.Name)
+	}
+	for _, cs := range doc.CodeSystems {
+		add(cs.Name)
+	}
+	for _, i := range doc.Instances {
+		add(i.Name)
+	}
+	for _, e := range doc.Extensions {
+		add(e.Name)
+	}
+	return names
+}
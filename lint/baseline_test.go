@@ -0,0 +1,161 @@
+package lint_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic"
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic/diagnostictest"
+	"github.com/verily-src/fsh-lint/lint"
+	"github.com/verily-src/fsh-lint/rules"
+)
+
+const baselineFSH = "ValueSet: ExampleOne\nId: example-two\nTitle: \"Example One\"\n"
+
+func TestNewBaselineEntry_RobustToLineShifts(t *testing.T) {
+	entries := func(data string) []*lint.BaselineEntry {
+		t.Helper()
+		fc, err := lint.NewFileContextFromData("./input/ExampleOne.fsh", []byte(data))
+		if err != nil {
+			t.Fatalf("NewFileContextFromData(): got error %v, want nil", err)
+		}
+		problems, err := (&rules.ValueSetNameMatchesIDRule{}).Validate(fc)
+		if err != nil {
+			t.Fatalf("Validate(): got error %v, want nil", err)
+		}
+		var result []*lint.BaselineEntry
+		for _, problem := range problems {
+			result = append(result, lint.NewBaselineEntry(fc, problem))
+		}
+		return result
+	}
+
+	original := entries(baselineFSH)
+	shifted := entries("// A comment\n\n" + baselineFSH)
+
+	if len(original) != 1 {
+		t.Fatalf("NewBaselineEntry(): got %d entries, want 1", len(original))
+	}
+	if got, want := original[0].File, "input/ExampleOne.fsh"; got != want {
+		t.Errorf("NewBaselineEntry() File: got %q, want %q", got, want)
+	}
+	if got, want := original[0].Entity, "ExampleOne"; got != want {
+		t.Errorf("NewBaselineEntry() Entity: got %q, want %q", got, want)
+	}
+	if diff := cmp.Diff(shifted, original); diff != "" {
+		t.Errorf("NewBaselineEntry() of shifted lines mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestLinter_Baseline(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "baseline.json")
+	newLinter := func() (*lint.Linter, *diagnostictest.FakePrinter) {
+		reporter, printer := diagnostictest.NewFakeReporter()
+		linter := lint.NewLinter(nil, []lint.Rule{&rules.ValueSetNameMatchesIDRule{}})
+		linter.Reporter = reporter
+		return linter, printer
+	}
+
+	// Record the existing problems.
+	linter, printer := newLinter()
+	linter.RecordBaseline = lint.NewBaseline()
+	linter.LintData("Examples.fsh", []byte(baselineFSH+"\nValueSet: ExampleTwo\nId: example-three\n"))
+	if len(printer.Messages) != 0 {
+		t.Errorf("LintData() with RecordBaseline: got %d messages, want 0", len(printer.Messages))
+	}
+	if err := linter.RecordBaseline.Write(path); err != nil {
+		t.Fatalf("Write(): got error %v, want nil", err)
+	}
+
+	// Fix ExampleTwo, keep the problem in ExampleOne, and add a new problem.
+	baseline, err := lint.LoadBaseline(path)
+	if err != nil {
+		t.Fatalf("LoadBaseline(): got error %v, want nil", err)
+	}
+	linter, printer = newLinter()
+	linter.Baseline = baseline
+	linter.LintData("Examples.fsh", []byte(baselineFSH+
+		"\nValueSet: ExampleTwo\nId: example-two\n\nValueSet: ExampleThree\nId: example-four\n"))
+
+	type result struct {
+		Severity diagnostic.Severity
+		Rule     string
+		Line     int
+	}
+	var got []result
+	for _, message := range printer.Messages {
+		got = append(got, result{Severity: message.Severity, Rule: message.Rule, Line: message.Line})
+	}
+	want := []result{
+		// The baseline entry of ExampleTwo no longer occurs.
+		{Severity: diagnostic.SeverityWarning},
+		// The problem in ExampleThree is new.
+		{Severity: diagnostic.SeverityNotice, Rule: "value-set-name-matches-id", Line: 9},
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("LintData() with Baseline messages mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestLinter_Baseline_RemovedFiles(t *testing.T) {
+	dir := t.TempDir()
+	kept, removed, unlinted := filepath.Join(dir, "Kept.fsh"), filepath.Join(dir, "Removed.fsh"), filepath.Join(dir, "Unlinted.fsh")
+	baseline := lint.NewBaseline()
+	for _, path := range []string{kept, removed, unlinted} {
+		linter := lint.NewLinter(nil, []lint.Rule{&rules.ValueSetNameMatchesIDRule{}})
+		linter.Reporter, _ = diagnostictest.NewFakeReporter()
+		linter.RecordBaseline = baseline
+		linter.LintData(path, []byte(baselineFSH))
+	}
+	for _, path := range []string{kept, unlinted} {
+		if err := os.WriteFile(path, []byte(baselineFSH), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reporter, printer := diagnostictest.NewFakeReporter()
+	linter := lint.NewLinter(nil, []lint.Rule{&rules.ValueSetNameMatchesIDRule{}})
+	linter.Reporter = reporter
+	linter.Baseline = baseline
+	linter.LintFiles([]string{kept})
+
+	type result struct {
+		Severity diagnostic.Severity
+		File     string
+	}
+	var got []result
+	for _, message := range printer.Messages {
+		got = append(got, result{Severity: message.Severity, File: message.File})
+	}
+	// Only the entry of the removed file is stale, since the entries of files
+	// that exist may be matched by a run that lints them.
+	want := []result{{Severity: diagnostic.SeverityWarning, File: removed}}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("LintFiles() with Baseline messages mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestLinter_Baseline_Reused(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Examples.fsh")
+	if err := os.WriteFile(path, []byte(baselineFSH), 0644); err != nil {
+		t.Fatal(err)
+	}
+	reporter, printer := diagnostictest.NewFakeReporter()
+	linter := lint.NewLinter(nil, []lint.Rule{&rules.ValueSetNameMatchesIDRule{}})
+	linter.Reporter = reporter
+	linter.RecordBaseline = lint.NewBaseline()
+	linter.LintFiles([]string{path})
+	linter.Baseline, linter.RecordBaseline = linter.RecordBaseline, nil
+
+	// The baseline hides the problem on every run, as in watch mode.
+	for run := range 2 {
+		printer.Messages = nil
+		reporter.Reset()
+		linter.LintFiles([]string{path})
+		if len(printer.Messages) != 0 {
+			t.Errorf("LintFiles() run %d with Baseline: got messages %v, want none", run, printer.Messages)
+		}
+	}
+}
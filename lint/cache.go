@@ -0,0 +1,158 @@
+package lint
+
+import (
+	"crypto/sha256"
+	"encoding/hex"
+	"encoding/json"
+	"fmt"
+	"os"
+	"sort"
+	"sync"
+)
+
+// CacheFileName is the name of the default cache file.
+const CacheFileName = ".fsh-lint-cache"
+
+// cacheFile is the on-disk format of a Cache.
+type cacheFile struct {
+	Version string                 `json:"version"`
+	Entries map[string]*cacheEntry `json:"entries"`
+}
+
+// cacheEntry holds the problems found in a single file.
+type cacheEntry struct {
+	Problems []*Problem `json:"problems"`
+}
+
+// Cache stores the problems found in each file, keyed by a hash of the file
+// content, the linter version, and the effective rule configuration, so that
+// unchanged files are not parsed and validated again. It is safe for
+// concurrent use.
+type Cache struct {
+	path    string
+	version string
+
+	mu sync.Mutex
+
+	// entries are the entries loaded from disk.
+	entries map[string]*cacheEntry
+
+	// used are the entries used or added in this run, which are the only
+	// entries saved, so that the cache does not grow without bound.
+	used map[string]*cacheEntry
+}
+
+// NewCache returns an empty in-memory cache for the given linter version, which
+// is not saved to disk.
+func NewCache(version string) *Cache {
+	return &Cache{
+		version: version,
+		entries: make(map[string]*cacheEntry),
+		used:    make(map[string]*cacheEntry),
+	}
+}
+
+// LoadCache reads the cache file at the given path for the given linter
+// version. If the file does not exist, is invalid, or was written by another
+// version, an empty cache is returned.
+func LoadCache(path, version string) *Cache {
+	c := NewCache(version)
+	c.path = path
+
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return c
+	}
+	var file cacheFile
+	if err := json.Unmarshal(data, &file); err != nil || file.Version != version {
+		return c
+	}
+	if file.Entries != nil {
+		c.entries = file.Entries
+	}
+	return c
+}
+
+// Save writes the entries used in this run to the cache file, and starts a new
+// run with only those entries. An in-memory cache is not written.
+func (c *Cache) Save() error {
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	c.entries, c.used = c.used, make(map[string]*cacheEntry)
+	if c.path == "" {
+		return nil
+	}
+
+	data, err := json.Marshal(&cacheFile{Version: c.version, Entries: c.entries})
+	if err != nil {
+		return err
+	}
+	return os.WriteFile(c.path, data, 0644)
+}
+
+// get returns the problems cached for the given key, and true if there are any.
+func (c *Cache) get(key string) ([]*Problem, bool) {
+	if c == nil {
+		return nil, false
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+
+	entry, ok := c.used[key]
+	if !ok {
+		entry, ok = c.entries[key]
+	}
+	if !ok {
+		return nil, false
+	}
+	c.used[key] = entry
+	return entry.Problems, true
+}
+
+// put caches the problems for the given key.
+func (c *Cache) put(key string, problems []*Problem) {
+	if c == nil {
+		return
+	}
+	c.mu.Lock()
+	defer c.mu.Unlock()
+	c.used[key] = &cacheEntry{Problems: problems}
+}
+
+// key returns the cache key of a file with the given path and data, linted
+// with the given rule settings. projectHash is non-empty if cross-file rules
+// are enabled.
+func (c *Cache) key(path string, data []byte, settings []string, projectHash string) string {
+	h := sha256.New()
+	write := func(s string) {
+		_, _ = fmt.Fprintf(h, "%d:%s", len(s), s)
+	}
+	write(c.version)
+	write(path)
+	write(string(data))
+	for _, setting := range settings {
+		write(setting)
+	}
+	write(projectHash)
+	return hex.EncodeToString(h.Sum(nil))
+}
+
+// projectHash returns a hash of the contents of all files at the given paths,
+// which changes when any of the files changes.
+func projectHash(paths []string) string {
+	sorted := append([]string(nil), paths...)
+	sort.Strings(sorted)
+
+	h := sha256.New()
+	for _, path := range sorted {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			// An unreadable file is reported when it is linted.
+			continue
+		}
+		sum := sha256.Sum256(data)
+		_, _ = fmt.Fprintf(h, "%d:%s:%x\n", len(path), path, sum)
+	}
+	return hex.EncodeToString(h.Sum(nil))
+}
@@ -0,0 +1,109 @@
+package lint_test
+
+import (
+	"os"
+	"path/filepath"
+	"sync/atomic"
+	"testing"
+
+	"github.com/verily-src/fsh-lint/ast"
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic/diagnostictest"
+	"github.com/verily-src/fsh-lint/lint"
+)
+
+// countingRule reports a problem on the first line of every file, and counts
+// how many files it validated.
+type countingRule struct {
+	calls     atomic.Int32
+	crossFile bool
+}
+
+func (*countingRule) ID() string      { return "counting-rule" }
+func (*countingRule) Message() string { return "Counted." }
+
+func (r *countingRule) CrossFile() bool { return r.crossFile }
+
+func (r *countingRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
+	r.calls.Add(1)
+	location := &ast.Location{Start: &ast.Position{LineNumber: 1}, End: &ast.Position{LineNumber: 1}}
+	return []*lint.Problem{{RuleID: r.ID(), Message: r.Message(), Location: location}}, nil
+}
+
+func TestLinter_Cache(t *testing.T) {
+	testCases := []struct {
+		name      string
+		crossFile bool
+		version   string
+		change    func(t *testing.T, a, b string)
+		wantCalls int32
+	}{
+		{
+			name:      "unchanged files are not validated again",
+			change:    func(*testing.T, string, string) {},
+			wantCalls: 0,
+		}, {
+			name: "changed file is validated again",
+			change: func(t *testing.T, a, _ string) {
+				writeFile(t, a, "ValueSet: Changed\n")
+			},
+			wantCalls: 1,
+		}, {
+			name:      "all files are validated again with another linter version",
+			version:   "v2",
+			change:    func(*testing.T, string, string) {},
+			wantCalls: 2,
+		}, {
+			name:      "cross-file rules are validated again when any file changes",
+			crossFile: true,
+			change: func(t *testing.T, a, _ string) {
+				writeFile(t, a, "ValueSet: Changed\n")
+			},
+			wantCalls: 2,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			dir := t.TempDir()
+			cachePath := filepath.Join(dir, lint.CacheFileName)
+			a, b := filepath.Join(dir, "A.fsh"), filepath.Join(dir, "B.fsh")
+			writeFile(t, a, "ValueSet: A\n")
+			writeFile(t, b, "ValueSet: B\n")
+
+			run := func(version string) (*countingRule, int) {
+				rule := &countingRule{crossFile: tc.crossFile}
+				reporter, printer := diagnostictest.NewFakeReporter()
+				linter := lint.NewLinter(nil, []lint.Rule{rule})
+				linter.Reporter = reporter
+				linter.Cache = lint.LoadCache(cachePath, version)
+				linter.LintFiles([]string{a, b})
+				return rule, len(printer.Messages)
+			}
+
+			if rule, _ := run("v1"); rule.calls.Load() != 2 {
+				t.Fatalf("LintFiles() with empty cache: got %d calls, want 2", rule.calls.Load())
+			}
+
+			tc.change(t, a, b)
+			version := tc.version
+			if version == "" {
+				version = "v1"
+			}
+			rule, messages := run(version)
+
+			if got := rule.calls.Load(); got != tc.wantCalls {
+				t.Errorf("LintFiles() with cache: got %d calls, want %d", got, tc.wantCalls)
+			}
+			if messages != 2 {
+				t.Errorf("LintFiles() with cache: got %d messages, want 2", messages)
+			}
+		})
+	}
+}
+
+func writeFile(t *testing.T, path, data string) {
+	t.Helper()
+	if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+		t.Fatal(err)
+	}
+}
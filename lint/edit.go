@@ -0,0 +1,256 @@
+package lint
+
+import (
+	"bytes"
+	"errors"
+	"fmt"
+	"slices"
+	"unicode/utf8"
+
+	"github.com/verily-src/fsh-lint/ast"
+)
+
+// TextEdit replaces the bytes of a file from Start up to, but not including,
+// End with NewText. An edit whose Start equals its End inserts NewText.
+type TextEdit struct {
+	Start   int    `json:"start"`
+	End     int    `json:"end"`
+	NewText string `json:"newText"`
+}
+
+// overlaps returns true if the edits cannot both be applied: their ranges
+// intersect, an insertion is strictly inside the other edit, or both insert at
+// the same offset.
+func (e *TextEdit) overlaps(other *TextEdit) bool {
+	if e.Start == e.End && other.Start == other.End {
+		return e.Start == other.Start
+	}
+	if e.Start == e.End {
+		return other.Start < e.Start && e.Start < other.End
+	}
+	if other.Start == other.End {
+		return e.Start < other.Start && other.Start < e.End
+	}
+	return e.Start < other.End && other.Start < e.End
+}
+
+// Offset returns the byte offset in data of the position, whose line is 1-based
+// and whose column is a 0-based count of characters. Returns false if the
+// position is not in data.
+func Offset(data []byte, position *ast.Position) (int, bool) {
+	if position == nil || position.LineNumber < 1 || position.ColumnNumber < 0 {
+		return 0, false
+	}
+	offset := 0
+	for line := 1; line < position.LineNumber; line++ {
+		i := bytes.IndexByte(data[offset:], '\n')
+		if i < 0 {
+			return 0, false
+		}
+		offset += i + 1
+	}
+	for range position.ColumnNumber {
+		if offset >= len(data) || data[offset] == '\n' {
+			return 0, false
+		}
+		_, size := utf8.DecodeRune(data[offset:])
+		offset += size
+	}
+	return offset, true
+}
+
+// Position returns the position of the byte offset in data, whose line is
+// 1-based and whose column is a 0-based count of characters. It is the inverse
+// of Offset for the offsets of characters in data.
+func Position(data []byte, offset int) *ast.Position {
+	offset = min(max(offset, 0), len(data))
+	start := bytes.LastIndexByte(data[:offset], '\n') + 1
+	return &ast.Position{
+		LineNumber:   bytes.Count(data[:offset], []byte("\n")) + 1,
+		ColumnNumber: utf8.RuneCount(data[start:offset]),
+	}
+}
+
+// TextEdits returns the edits that fix the problem in data. These are the
+// problem's Edits if it has any. Otherwise, the edit replaces the first
+// occurrence of Diff.Got at or after the start of the problem's location, on
+// the lines of the location, with Diff.Want. This search is a fallback for
+// rules, such as those of other modules, that create fixable problems with
+// NewProblem, and may replace the wrong occurrence if Diff.Got also occurs
+// before the value, so the rules of this module set Edits. Returns an error if
+// the problem is not fixable, or if Diff.Got does not occur there.
+func (p *Problem) TextEdits(data []byte) ([]*TextEdit, error) {
+	if !p.IsFixable {
+		return nil, fmt.Errorf("problem of rule %s is not fixable", p.RuleID)
+	}
+	if len(p.Edits) > 0 {
+		return p.Edits, nil
+	}
+	if p.Diff == nil || p.Diff.Got == "" {
+		return nil, fmt.Errorf("problem of rule %s has no edits: %w", p.RuleID, ProblemIsMisconfigured)
+	}
+
+	start, ok := Offset(data, p.StartPosition())
+	if !ok {
+		return nil, fmt.Errorf("invalid start position for fix of rule %s", p.RuleID)
+	}
+	end := len(data)
+	if endLine := p.EndPosition(); endLine != nil {
+		if lineStart, ok := Offset(data, &ast.Position{LineNumber: endLine.LineNumber}); ok {
+			if i := bytes.IndexByte(data[lineStart:], '\n'); i >= 0 {
+				end = lineStart + i
+			}
+		}
+	}
+	if end < start {
+		return nil, fmt.Errorf("invalid line range for fix of rule %s: %d-%d", p.RuleID, p.StartPosition().LineNumber, p.EndPosition().LineNumber)
+	}
+
+	i := bytes.Index(data[start:end], []byte(p.Diff.Got))
+	if i < 0 {
+		return nil, fmt.Errorf("cannot fix %s: '%s' not found at line %d", p.RuleID, p.Diff.Got, p.StartPosition().LineNumber)
+	}
+	return []*TextEdit{{Start: start + i, End: start + i + len(p.Diff.Got), NewText: p.Diff.Want}}, nil
+}
+
+// ApplyFixes applies the edits of the fixable problems to data in a single
+// pass. Problems are considered in the order of their first edit, and a problem
+// whose edits conflict with the edits of a problem considered before it is
+// skipped, so that it can be fixed once the fixed data is linted again. Edits
+// that are identical to edits already applied do not conflict. Returns the
+// fixed data, the problems whose edits were applied, and the errors of the
+// problems whose edits are invalid.
+func ApplyFixes(data []byte, problems []*Problem) ([]byte, []*Problem, error) {
+	type fix struct {
+		problem *Problem
+		edits   []*TextEdit
+	}
+
+	var fixes []*fix
+	var errs []error
+	for _, problem := range problems {
+		if !problem.IsFixable {
+			continue
+		}
+		edits, err := problem.TextEdits(data)
+		if err == nil {
+			edits = slices.Clone(edits)
+			err = validateEdits(data, edits)
+		}
+		if err != nil {
+			errs = append(errs, err)
+			continue
+		}
+		fixes = append(fixes, &fix{problem: problem, edits: edits})
+	}
+	slices.SortStableFunc(fixes, func(a, b *fix) int {
+		return a.edits[0].Start - b.edits[0].Start
+	})
+
+	var applied []*Problem
+	var accepted []*TextEdit
+	for _, f := range fixes {
+		var added []*TextEdit
+		conflict := false
+		for _, edit := range f.edits {
+			if slices.ContainsFunc(accepted, func(e *TextEdit) bool { return *e == *edit }) {
+				continue
+			}
+			if slices.ContainsFunc(accepted, edit.overlaps) {
+				conflict = true
+				break
+			}
+			added = append(added, edit)
+		}
+		if conflict {
+			continue
+		}
+		accepted = append(accepted, added...)
+		applied = append(applied, f.problem)
+	}
+	if len(accepted) == 0 {
+		return data, applied, errors.Join(errs...)
+	}
+
+	sortEdits(accepted)
+	return applyEdits(data, accepted), applied, errors.Join(errs...)
+}
+
+// ApplyEdits applies the edits to data, and returns an error if an edit is
+// outside of data, or if the edits overlap each other.
+func ApplyEdits(data []byte, edits []*TextEdit) ([]byte, error) {
+	edits = slices.Clone(edits)
+	if err := validateEdits(data, edits); err != nil {
+		return nil, err
+	}
+	return applyEdits(data, edits), nil
+}
+
+// applyEdits applies the sorted, non-overlapping edits to data.
+func applyEdits(data []byte, edits []*TextEdit) []byte {
+	var result bytes.Buffer
+	offset := 0
+	for _, edit := range edits {
+		result.Write(data[offset:edit.Start])
+		result.WriteString(edit.NewText)
+		offset = edit.End
+	}
+	result.Write(data[offset:])
+	return result.Bytes()
+}
+
+// validateEdits returns an error if an edit is outside of data, or if the edits
+// overlap each other. The edits are sorted.
+func validateEdits(data []byte, edits []*TextEdit) error {
+	if len(edits) == 0 {
+		return fmt.Errorf("fix has no edits: %w", ProblemIsMisconfigured)
+	}
+	for i, edit := range edits {
+		if edit.Start < 0 || edit.End < edit.Start || edit.End > len(data) {
+			return fmt.Errorf("edit %d-%d is outside of the file: %w", edit.Start, edit.End, ProblemIsMisconfigured)
+		}
+		for _, other := range edits[:i] {
+			if edit.overlaps(other) {
+				return fmt.Errorf("edits %d-%d and %d-%d overlap: %w", other.Start, other.End, edit.Start, edit.End, ProblemIsMisconfigured)
+			}
+		}
+	}
+	sortEdits(edits)
+	return nil
+}
+
+// sortEdits sorts the edits by their start, with insertions before the edits
+// that start at the same offset.
+func sortEdits(edits []*TextEdit) {
+	slices.SortStableFunc(edits, func(a, b *TextEdit) int {
+		if a.Start != b.Start {
+			return a.Start - b.Start
+		}
+		return a.End - b.End
+	})
+}
+
+// matchLineEndings returns data with the line endings of original: lone "\n"
+// line endings are replaced with "\r\n" if every line of original ends with
+// "\r\n", and the trailing newline is added or removed to match original.
+func matchLineEndings(original, data []byte) []byte {
+	crlf := bytes.Count(original, []byte("\r\n"))
+	if crlf > 0 && crlf == bytes.Count(original, []byte("\n")) {
+		data = bytes.ReplaceAll(data, []byte("\r\n"), []byte("\n"))
+		data = bytes.ReplaceAll(data, []byte("\n"), []byte("\r\n"))
+	}
+	if len(original) == 0 || len(data) == 0 {
+		return data
+	}
+	newline := []byte("\n")
+	if crlf > 0 && bytes.HasSuffix(original, []byte("\r\n")) {
+		newline = []byte("\r\n")
+	}
+	switch hasNewline := bytes.HasSuffix(original, []byte("\n")); {
+	case hasNewline && !bytes.HasSuffix(data, []byte("\n")):
+		data = append(slices.Clip(data), newline...)
+	case !hasNewline:
+		data = bytes.TrimRight(data, "\r\n")
+	}
+	return data
+}
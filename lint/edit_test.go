@@ -0,0 +1,410 @@
+package lint_test
+
+import (
+	"bytes"
+	"log"
+	"os"
+	"path/filepath"
+	"runtime"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/ast"
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic/diagnostictest"
+	"github.com/verily-src/fsh-lint/lint"
+)
+
+func TestApplyFixes(t *testing.T) {
+	tests := []struct {
+		name        string
+		data        string
+		problems    []*lint.Problem
+		want        string
+		wantApplied []string
+		wantErr     bool
+	}{
+		{
+			name: "edits are applied in a single pass",
+			data: "abcdef",
+			problems: []*lint.Problem{
+				editProblem("second", &lint.TextEdit{Start: 4, End: 6, NewText: "EF"}),
+				editProblem("first", &lint.TextEdit{Start: 0, End: 1, NewText: "A"}, &lint.TextEdit{Start: 2, End: 2, NewText: "-"}),
+			},
+			want:        "Ab-cdEF",
+			wantApplied: []string{"first", "second"},
+		},
+		{
+			name: "conflicting problems are skipped",
+			data: "abcdef",
+			problems: []*lint.Problem{
+				editProblem("first", &lint.TextEdit{Start: 1, End: 4, NewText: "X"}),
+				editProblem("overlapping", &lint.TextEdit{Start: 3, End: 5, NewText: "Y"}),
+				editProblem("inside", &lint.TextEdit{Start: 2, End: 2, NewText: "Z"}),
+				editProblem("adjacent", &lint.TextEdit{Start: 4, End: 5, NewText: "E"}),
+			},
+			want:        "aXEf",
+			wantApplied: []string{"first", "adjacent"},
+		},
+		{
+			name: "identical edits do not conflict",
+			data: "abc",
+			problems: []*lint.Problem{
+				editProblem("first", &lint.TextEdit{Start: 1, End: 2, NewText: "B"}),
+				editProblem("second", &lint.TextEdit{Start: 1, End: 2, NewText: "B"}),
+			},
+			want:        "aBc",
+			wantApplied: []string{"first", "second"},
+		},
+		{
+			name: "insertions at the same offset conflict",
+			data: "abc",
+			problems: []*lint.Problem{
+				editProblem("first", &lint.TextEdit{Start: 1, End: 1, NewText: "X"}),
+				editProblem("second", &lint.TextEdit{Start: 1, End: 1, NewText: "Y"}),
+			},
+			want:        "aXbc",
+			wantApplied: []string{"first"},
+		},
+		{
+			name: "diff replaces the occurrence at the location",
+			data: "ValueSet: Name\nTitle: \"Name Name\"\n",
+			problems: []*lint.Problem{
+				diffProblem("title", 2, 7, "Name Name", "Name"),
+			},
+			want:        "ValueSet: Name\nTitle: \"Name\"\n",
+			wantApplied: []string{"title"},
+		},
+		{
+			name: "diff replaces the first occurrence after the start",
+			data: "Id: name\n// name\n",
+			problems: []*lint.Problem{
+				diffProblem("id", 1, 4, "name", "id"),
+			},
+			want:        "Id: id\n// name\n",
+			wantApplied: []string{"id"},
+		},
+		{
+			name: "invalid problems are reported and skipped",
+			data: "abc",
+			problems: []*lint.Problem{
+				editProblem("outside", &lint.TextEdit{Start: 2, End: 4, NewText: "X"}),
+				editProblem("self-overlapping", &lint.TextEdit{Start: 0, End: 2}, &lint.TextEdit{Start: 1, End: 3}),
+				diffProblem("missing", 1, 0, "d", "D"),
+				editProblem("valid", &lint.TextEdit{Start: 0, End: 1, NewText: "A"}),
+				{RuleID: "unfixable", Edits: []*lint.TextEdit{{Start: 1, End: 2, NewText: "X"}}},
+			},
+			want:        "Abc",
+			wantApplied: []string{"valid"},
+			wantErr:     true,
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			got, applied, err := lint.ApplyFixes([]byte(tt.data), tt.problems)
+			if (err != nil) != tt.wantErr {
+				t.Errorf("ApplyFixes() error = %v, wantErr %v", err, tt.wantErr)
+			}
+			if diff := cmp.Diff(string(got), tt.want); diff != "" {
+				t.Errorf("ApplyFixes() data mismatch (-got +want):\n%s", diff)
+			}
+			var gotApplied []string
+			for _, problem := range applied {
+				gotApplied = append(gotApplied, problem.RuleID)
+			}
+			if diff := cmp.Diff(gotApplied, tt.wantApplied); diff != "" {
+				t.Errorf("ApplyFixes() applied mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOffset(t *testing.T) {
+	data := []byte("Title: \"Ünïcode\"\nId: x\n")
+	tests := []struct {
+		position *ast.Position
+		want     int
+		wantOK   bool
+	}{
+		{position: &ast.Position{LineNumber: 1, ColumnNumber: 0}, want: 0, wantOK: true},
+		{position: &ast.Position{LineNumber: 1, ColumnNumber: 10}, want: 11, wantOK: true},
+		{position: &ast.Position{LineNumber: 2, ColumnNumber: 4}, want: 23, wantOK: true},
+		{position: &ast.Position{LineNumber: 2, ColumnNumber: 6}},
+		{position: &ast.Position{LineNumber: 4, ColumnNumber: 0}},
+		{position: nil},
+	}
+	for _, tt := range tests {
+		got, ok := lint.Offset(data, tt.position)
+		if got != tt.want || ok != tt.wantOK {
+			t.Errorf("Offset(%+v) = %d, %t, want %d, %t", tt.position, got, ok, tt.want, tt.wantOK)
+		}
+	}
+}
+
+func TestPosition(t *testing.T) {
+	data := []byte("Title: \"Ünïcode\"\nId: x\n")
+	for _, offset := range []int{0, 11, 19, 23, 25} {
+		position := lint.Position(data, offset)
+		if got, ok := lint.Offset(data, position); got != offset || !ok {
+			t.Errorf("Offset(Position(%d)) = %d, %t, want %d, true", offset, got, ok, offset)
+		}
+	}
+	if got, want := lint.Position(data, 23), (&ast.Position{LineNumber: 2, ColumnNumber: 4}); *got != *want {
+		t.Errorf("Position(23) = %+v, want %+v", got, want)
+	}
+}
+
+// collapseRule replaces every "ab" in a file with "b", so that fixing "aab"
+// takes two passes.
+type collapseRule struct{}
+
+func (collapseRule) ID() string      { return "collapse" }
+func (collapseRule) Message() string { return "ab should be b" }
+func (collapseRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
+	var problems []*lint.Problem
+	for i := 0; ; i++ {
+		j := bytes.Index(fc.Data[i:], []byte("ab"))
+		if j < 0 {
+			return problems, nil
+		}
+		i += j
+		problems = append(problems, editProblem("collapse", &lint.TextEdit{Start: i, End: i + 2, NewText: "b"}))
+	}
+}
+
+func TestLinter_FixUntilFixedPoint(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Example.fsh")
+	if err := os.WriteFile(path, []byte("// aaab ab\nValueSet: Example\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	var logs bytes.Buffer
+	log.SetOutput(&logs)
+	t.Cleanup(func() { log.SetOutput(os.Stderr) })
+
+	reporter, printer := diagnostictest.NewFakeReporter()
+	linter := lint.NewLinter(nil, []lint.Rule{collapseRule{}})
+	linter.Reporter = reporter
+	linter.Fix = true
+	linter.LintFiles([]string{path})
+
+	got, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(got), "// b b\nValueSet: Example\n"); diff != "" {
+		t.Errorf("LintFiles() fixed data mismatch (-got +want):\n%s", diff)
+	}
+	// Only the problems of the original file are reported.
+	if len(printer.Messages) != 2 {
+		t.Errorf("LintFiles() reported %d messages, want 2", len(printer.Messages))
+	}
+	// The problems fixed by every pass are counted as fixed.
+	var fixed int
+	for _, rs := range reporter.Summary().Rules {
+		if rs.Rule == "collapse" {
+			fixed = rs.Fixed
+		}
+	}
+	if fixed != 4 {
+		t.Errorf("LintFiles() fixed %d collapse problems, want 4", fixed)
+	}
+	// Problems without a field name are logged by their message.
+	if want := `[collapse] Fixed "collapse" in `; !strings.Contains(logs.String(), want) {
+		t.Errorf("LintFiles() logged %q, want it to contain %q", logs.String(), want)
+	}
+}
+
+// replaceRule replaces the first occurrence of old in a file with new, until
+// new occurs in the file.
+type replaceRule struct {
+	old, new string
+}
+
+func (replaceRule) ID() string      { return "replace" }
+func (replaceRule) Message() string { return "old should be new" }
+func (r replaceRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
+	i := bytes.Index(fc.Data, []byte(r.old))
+	if i < 0 || bytes.Contains(fc.Data, []byte(r.new)) {
+		return nil, nil
+	}
+	return []*lint.Problem{editProblem("replace", &lint.TextEdit{Start: i, End: i + len(r.old), NewText: r.new})}, nil
+}
+
+func TestLinter_FixKeepsLineEndings(t *testing.T) {
+	tests := []struct {
+		name string
+		data string
+		rule replaceRule
+		want string
+	}{
+		{
+			name: "CRLF",
+			data: "ValueSet: Example\r\nId: example\r\n",
+			rule: replaceRule{old: "Id: example", new: "Id: example\nTitle: \"Example\""},
+			want: "ValueSet: Example\r\nId: example\r\nTitle: \"Example\"\r\n",
+		},
+		{
+			name: "mixed line endings",
+			data: "ValueSet: Example\r\nId: example\n",
+			rule: replaceRule{old: "Id: example", new: "Id: example\nTitle: \"Example\""},
+			want: "ValueSet: Example\r\nId: example\nTitle: \"Example\"\n",
+		},
+		{
+			name: "trailing newline",
+			data: "ValueSet: Example\nId: wrong\n",
+			rule: replaceRule{old: "Id: wrong\n", new: "Id: example"},
+			want: "ValueSet: Example\nId: example\n",
+		},
+		{
+			name: "no trailing newline",
+			data: "ValueSet: Example\nId: wrong",
+			rule: replaceRule{old: "Id: wrong", new: "Id: example\n"},
+			want: "ValueSet: Example\nId: example",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter, _ := diagnostictest.NewFakeReporter()
+			linter := lint.NewLinter(nil, []lint.Rule{tt.rule})
+			linter.Reporter = reporter
+			linter.Fix = true
+
+			got := linter.LintData("Example.fsh", []byte(tt.data))
+			if diff := cmp.Diff(string(got), tt.want); diff != "" {
+				t.Errorf("LintData() mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestLinter_FixStopsBeforeSyntaxErrors(t *testing.T) {
+	tests := []struct {
+		name  string
+		data  string
+		rules []lint.Rule
+		want  string
+	}{
+		{
+			name:  "first pass",
+			data:  "ValueSet: Example\nId: example\n",
+			rules: []lint.Rule{replaceRule{old: "Id: example", new: "Id: example\n* #x, #y"}},
+			want:  "ValueSet: Example\nId: example\n",
+		},
+		{
+			name: "later pass",
+			data: "ValueSet: Example\nId: wrong\n",
+			rules: []lint.Rule{
+				replaceRule{old: "Id: wrong", new: "Id: example"},
+				replaceRule{old: "Id: example", new: "Id: example\n* #x, #y"},
+			},
+			want: "ValueSet: Example\nId: example\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter, printer := diagnostictest.NewFakeReporter()
+			linter := lint.NewLinter(nil, tt.rules)
+			linter.Reporter = reporter
+			linter.Fix = true
+
+			got := linter.LintData("Example.fsh", []byte(tt.data))
+			if diff := cmp.Diff(string(got), tt.want); diff != "" {
+				t.Errorf("LintData() mismatch (-got +want):\n%s", diff)
+			}
+			if got := reporter.ErrorCount(); got != 1 {
+				t.Errorf("LintData() reported %d errors, want 1: %v", got, printer.Messages)
+			}
+		})
+	}
+}
+
+// problemRule reports its problem in every file.
+type problemRule struct {
+	problem *lint.Problem
+}
+
+func (problemRule) ID() string      { return "problem" }
+func (problemRule) Message() string { return "problem" }
+func (r problemRule) Validate(*lint.FileContext) ([]*lint.Problem, error) {
+	return []*lint.Problem{r.problem}, nil
+}
+
+func TestLinter_FixErrorsAreNotErrors(t *testing.T) {
+	tests := []struct {
+		name    string
+		problem *lint.Problem
+	}{
+		{
+			name:    "misconfigured edit",
+			problem: editProblem("problem", &lint.TextEdit{Start: 100, End: 200, NewText: "x"}),
+		},
+		{
+			name:    "diff not found",
+			problem: diffProblem("problem", 2, 0, "wrong", "example"),
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter, printer := diagnostictest.NewFakeReporter()
+			linter := lint.NewLinter(nil, []lint.Rule{problemRule{problem: tt.problem}})
+			linter.Reporter = reporter
+			linter.Fix = true
+
+			data := "ValueSet: Example\nId: example\n"
+			got := linter.LintData("Example.fsh", []byte(data))
+			if diff := cmp.Diff(string(got), data); diff != "" {
+				t.Errorf("LintData() mismatch (-got +want):\n%s", diff)
+			}
+			if got := reporter.ErrorCount(); got != 0 {
+				t.Errorf("LintData() reported %d errors, want 0: %v", got, printer.Messages)
+			}
+		})
+	}
+}
+
+func TestLinter_FixKeepsFileMode(t *testing.T) {
+	if runtime.GOOS == "windows" {
+		t.Skip("file modes are not supported on Windows")
+	}
+	path := filepath.Join(t.TempDir(), "Example.fsh")
+	if err := os.WriteFile(path, []byte("// ab\nValueSet: Example\n"), 0600); err != nil {
+		t.Fatal(err)
+	}
+
+	reporter, _ := diagnostictest.NewFakeReporter()
+	linter := lint.NewLinter(nil, []lint.Rule{collapseRule{}})
+	linter.Reporter = reporter
+	linter.Fix = true
+	linter.LintFiles([]string{path})
+
+	info, err := os.Stat(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if mode := info.Mode().Perm(); mode != 0600 {
+		t.Errorf("LintFiles() changed the file mode to %v, want %v", mode, os.FileMode(0600))
+	}
+}
+
+// editProblem returns a fixable problem of the rule with the given edits.
+func editProblem(ruleID string, edits ...*lint.TextEdit) *lint.Problem {
+	return &lint.Problem{RuleID: ruleID, Message: ruleID, IsFixable: true, Edits: edits}
+}
+
+// diffProblem returns a fixable problem of the rule that replaces got with want
+// at the given line and column.
+func diffProblem(ruleID string, line, column int, got, want string) *lint.Problem {
+	position := &ast.Position{LineNumber: line, ColumnNumber: column}
+	return &lint.Problem{
+		RuleID:    ruleID,
+		Message:   ruleID,
+		Location:  &ast.Location{Start: position, End: position},
+		Diff:      &lint.Diff{Got: got, Want: want},
+		IsFixable: true,
+	}
+}
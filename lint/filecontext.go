@@ -4,8 +4,8 @@ import (
 	"fmt"
 	"os"
 
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/internal/fsh"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
 )
 
 // FileContext represents the context of a file being linted.
@@ -17,7 +17,7 @@ type FileContext struct {
 	Data []byte
 
 	// ParsedFSH is the data in parsed form
-	ParsedFSH *types.FSHDocument
+	ParsedFSH *ast.FSHDocument
 }
 
 // NewFileContext creates a new FileContext with the given path.
@@ -27,6 +27,12 @@ func NewFileContext(path string) (*FileContext, error) {
 		return nil, err
 	}
 
+	return NewFileContextFromData(path, data)
+}
+
+// NewFileContextFromData creates a new FileContext from the given data, as if
+// it were read from the given path. The path is not accessed.
+func NewFileContextFromData(path string, data []byte) (*FileContext, error) {
 	f := string(data)
 	parsedFSH, err := fsh.Parse(f)
 	if err != nil {
@@ -10,10 +10,17 @@ type Formatter interface {
 
 type DefaultFormatter struct{}
 
-// Format formats the given problem into a human-readable string.
+// Format formats the given problem into a human-readable string. Problems with
+// an unsafe fix are marked, since they are not fixed by default.
 func (d *DefaultFormatter) Format(p *Problem) string {
+	var s string
 	if p.Diff != nil {
-		return fmt.Sprintf("[%s] %s. %s", p.RuleID, p.Diff, p.Message)
+		s = fmt.Sprintf("[%s] %s. %s", p.RuleID, p.Diff, p.Message)
+	} else {
+		s = fmt.Sprintf("[%s] %s", p.RuleID, p.Message)
 	}
-	return fmt.Sprintf("[%s] %s", p.RuleID, p.Message)
+	if p.IsFixable && p.UnsafeFix {
+		s += " (unsafe fix available)"
+	}
+	return s
 }
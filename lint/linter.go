@@ -3,11 +3,18 @@ package lint
 import (
 	"errors"
 	"fmt"
+	"io/fs"
 	"log"
 	"os"
+	"path/filepath"
+	"runtime"
+	"slices"
 	"strings"
+	"sync"
 
+	"github.com/verily-src/fsh-lint/internal/atomicfile"
 	"github.com/verily-src/fsh-lint/internal/cli/diagnostic"
+	"github.com/verily-src/fsh-lint/internal/config"
 )
 
 // Linter orchestrates the linting process.
@@ -21,6 +28,10 @@ type Linter struct {
 	// rules is the set of rules to run after requiredRules has been run.
 	rules []Rule
 
+	// CustomRules are rules defined in the configuration, which run after
+	// rules. Optional.
+	CustomRules []Rule
+
 	// Formatter is responsible for formatting the generated lint Problems.
 	Formatter Formatter
 
@@ -28,12 +39,71 @@ type Linter struct {
 	Reporter *diagnostic.Reporter
 
 	// Fix is a flag that indicates whether the linter should attempt to fix
-	// the problems found.
+	// the problems found. Only safe fixes are applied unless FixUnsafe is set.
 	Fix bool
 
+	// FixUnsafe is a flag that indicates whether Fix also applies unsafe fixes,
+	// which may break references.
+	FixUnsafe bool
+
+	// WriteFile is called with the fixed data of each file that Lint and
+	// LintFiles fix, and may be called concurrently. Optional, the fixed data
+	// replaces the file atomically if nil, keeping its mode, unless the file
+	// changed on disk since it was read.
+	WriteFile func(path string, data []byte) error
+
+	// FixDryRun is a flag that indicates whether the fixes are only previewed,
+	// such as by a WriteFile that does not write. Fixes are then logged as
+	// fixes that would be made, are not counted as fixed by the Reporter, and
+	// the Cache is not saved.
+	FixDryRun bool
+
+	// RenameReferences is a flag that indicates whether LintFiles renames the
+	// references in the linted files to the entities whose name or ID a fix
+	// changed, once all files are fixed.
+	RenameReferences bool
+
 	// HasErrors is a flag that indicates whether the linter has found any
 	// error-level lint problems.
 	HasErrors bool
+
+	// Config is the project configuration used to disable rules or change
+	// their severity, optionally for specific paths. Optional.
+	Config *config.Config
+
+	// Filter decides whether a problem found in the file at the given path is
+	// reported. Problems it returns false for are neither reported nor fixed.
+	// Optional, all problems are reported if nil.
+	Filter func(path string, problem *Problem) bool
+
+	// Baseline hides the known problems recorded in it, and reports its entries
+	// that no longer occur in the linted files, or whose files LintFiles finds
+	// removed. Optional.
+	Baseline *Baseline
+
+	// RecordBaseline records every problem found instead of reporting it, to
+	// write a new baseline. Optional.
+	RecordBaseline *Baseline
+
+	// Jobs is the number of files that LintFiles lints concurrently. Defaults
+	// to GOMAXPROCS if less than 1.
+	Jobs int
+
+	// Cache stores the problems found in each file so that unchanged files are
+	// not parsed and validated again. LintFiles saves it after linting.
+	// Optional.
+	Cache *Cache
+
+	// projectHash is the hash of all files linted by LintFiles, part of the
+	// cache key when cross-file rules are enabled.
+	projectHash string
+
+	// renames and fixedData hold the renames made by fixes and the fixed data
+	// of each file while LintFiles renames references. fixedData is nil
+	// otherwise.
+	renameMu  sync.Mutex
+	renames   []*rename
+	fixedData map[string][]byte
 }
 
 // NewLinter initializes a new linter with the given required rules and rules.
@@ -50,11 +120,172 @@ func NewLinter(requiredRules []Rule, rules []Rule) *Linter {
 	}
 }
 
+// Rules returns all the rules the linter runs, with the required rules first.
+func (l *Linter) Rules() []Rule {
+	var rules []Rule
+	rules = append(rules, l.requiredRules...)
+	rules = append(rules, l.otherRules()...)
+	return rules
+}
+
+// otherRules returns the rules to run after the required rules, followed by
+// the custom rules.
+func (l *Linter) otherRules() []Rule {
+	return append(slices.Clone(l.rules), l.CustomRules...)
+}
+
 // Lint reads, parses, and validates the file at the given path reporting any
 // issues found to Linter.reporter. If the fix flag is set, the linter will
 // attempt to fix the problems found.
 func (l *Linter) Lint(path string) {
-	// Use default reporter and formatter if not set
+	l.setDefaults()
+	l.lintFile(path)
+	l.updateHasErrors()
+}
+
+// LintFiles lints the files at the given paths like Lint, running up to Jobs
+// files concurrently. Messages are held by the reporter until all files are
+// linted, and then printed sorted by file and position, so that the output
+// does not depend on the order in which files finish. Each call matches the
+// problems to the Baseline anew, so that a linter can be reused across runs.
+func (l *Linter) LintFiles(paths []string) {
+	l.setDefaults()
+	l.Baseline.reset()
+	l.Reporter.WithSortedOutput(true)
+	if l.Cache != nil && l.hasCrossFileRules() {
+		l.projectHash = projectHash(paths)
+	}
+
+	if l.Fix && l.RenameReferences {
+		l.renames, l.fixedData = nil, make(map[string][]byte)
+		defer func() {
+			l.renames, l.fixedData = nil, nil
+		}()
+	}
+
+	jobs := l.Jobs
+	if jobs < 1 {
+		jobs = runtime.GOMAXPROCS(0)
+	}
+
+	work := make(chan string)
+	var wg sync.WaitGroup
+	for range min(jobs, len(paths)) {
+		wg.Add(1)
+		go func() {
+			defer wg.Done()
+			for path := range work {
+				l.lintFile(path)
+			}
+		}()
+	}
+	for _, path := range paths {
+		work <- path
+	}
+	close(work)
+	wg.Wait()
+
+	if l.fixedData != nil {
+		l.renameReferences(paths)
+	}
+
+	if l.Baseline != nil {
+		l.reportRemovedBaselineFiles(paths)
+	}
+
+	if l.Cache != nil && !l.FixDryRun {
+		if err := l.Cache.Save(); err != nil {
+			l.Reporter.Warningf("Unable to save the cache: %v", err)
+		}
+	}
+
+	l.Reporter.Flush()
+	l.updateHasErrors()
+}
+
+// lintFile reads and lints the file at the given path, writing the fixed data
+// with WriteFile if any problem was fixed.
+func (l *Linter) lintFile(path string) {
+	// return early if file can't be read
+	data, err := os.ReadFile(path)
+	if err != nil {
+		l.Reporter.Errorf("%v", err)
+		return
+	}
+
+	fixedData, fixed := l.lintData(path, data)
+	if fixed {
+		if l.writeFile(path, data, fixedData) {
+			l.recordFixedData(path, fixedData)
+		}
+	}
+}
+
+// writeFile writes the fixed data of the file at the given path, whose
+// contents were original when read, with WriteFile, reporting any error.
+// Returns true if the data was written.
+func (l *Linter) writeFile(path string, original, data []byte) bool {
+	write := l.WriteFile
+	if write == nil {
+		write = func(path string, data []byte) error {
+			return atomicfile.Write(path, original, data)
+		}
+	}
+	if err := write(path, data); err != nil {
+		l.Reporter.Errorf("Error writing to %s: %v", path, err)
+		return false
+	}
+	return true
+}
+
+// LintData parses and validates the given data as if it were the contents of
+// the file at the given path, reporting any issues found to Linter.reporter.
+// The path is only used for filename-based rules and reporting, and is never
+// read from or written to. If the fix flag is set, the returned data has the
+// problems found fixed; otherwise the given data is returned unchanged.
+func (l *Linter) LintData(path string, data []byte) []byte {
+	l.setDefaults()
+	fixedData, _ := l.lintData(path, data)
+	l.updateHasErrors()
+	return fixedData
+}
+
+// Problems parses and validates the given data as if it were the contents of
+// the file at the given path, returning the problems found instead of reporting
+// them, for integrations such as editors. Problems rejected by Filter are not
+// returned, but the baseline is not applied. Returns an error if the data
+// cannot be parsed, or if any rule fails.
+func (l *Linter) Problems(path string, data []byte) ([]*Problem, error) {
+	fileContext, err := NewFileContextFromData(path, data)
+	if err != nil {
+		return nil, err
+	}
+
+	problems, errs := validate(fileContext, l.enabledRules(path, l.requiredRules), l.enabledRules(path, l.otherRules()))
+	if l.Filter != nil {
+		var filtered []*Problem
+		for _, problem := range problems {
+			if l.Filter(path, problem) {
+				filtered = append(filtered, problem)
+			}
+		}
+		problems = filtered
+	}
+	return problems, errors.Join(errs...)
+}
+
+// ProblemsInFile reads the file at the given path and returns the problems
+// found in it, like Problems. The file is never written to, even if Fix is set.
+func (l *Linter) ProblemsInFile(path string) ([]*Problem, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	return l.Problems(path, data)
+}
+
+// setDefaults sets the default reporter and formatter if they are not set.
+func (l *Linter) setDefaults() {
 	if l.Reporter == nil {
 		l.Reporter = diagnostic.NewReporter(diagnostic.DefaultPrinter)
 	}
@@ -62,114 +293,323 @@ func (l *Linter) Lint(path string) {
 	if l.Formatter == nil {
 		l.Formatter = &DefaultFormatter{}
 	}
+}
 
-	// read and parse file
-	fileContext, err := NewFileContext(path)
-	if err != nil {
-		// return early if file can't be read/parsed
-		l.Reporter.Errorf("%v", err)
-		return
+// updateHasErrors sets HasErrors if the reporter has reported any errors.
+func (l *Linter) updateHasErrors() {
+	if l.Reporter.ErrorCount() > 0 {
+		l.HasErrors = true
 	}
+}
 
-	// validate that required rules are present
+// lintData parses and validates data, reporting the problems found. Returns the
+// fixed data, and true if any problem was fixed.
+func (l *Linter) lintData(path string, data []byte) ([]byte, bool) {
+	requiredRules := l.enabledRules(path, l.requiredRules)
+	rules := l.enabledRules(path, l.otherRules())
+
+	var key string
 	var problems []*Problem
-	missingFieldProblems := lintWithRules(fileContext, l.requiredRules, l.Reporter)
-	problems = append(problems, missingFieldProblems...)
+	cached := false
+	if l.Cache != nil {
+		key = l.Cache.key(path, data, l.ruleSettings(path, requiredRules, rules), l.projectHash)
+		problems, cached = l.Cache.get(key)
+	}
 
-	// validate the rule set only if there are no missing fields
-	if len(problems) == 0 {
-		ruleProblems := lintWithRules(fileContext, l.rules, l.Reporter)
-		problems = append(problems, ruleProblems...)
+	// The parsed document is only needed to validate the file, or to find the
+	// entities of problems for the baseline.
+	fileContext := &FileContext{Path: path, Data: data}
+	if !cached || l.Baseline != nil || l.RecordBaseline != nil {
+		// return early if file can't be parsed
+		var err error
+		fileContext, err = NewFileContextFromData(path, data)
+		if err != nil {
+			l.Reporter.Errorf("%v", err)
+			return data, false
+		}
+	}
+
+	if !cached {
+		var errs []error
+		problems, errs = validate(fileContext, requiredRules, rules)
+		for _, err := range errs {
+			if errors.Is(err, ProblemIsMisconfigured) {
+				l.Reporter.Debugf("%v", err)
+			} else {
+				l.Reporter.Errorf("%v", err)
+			}
+		}
+
+		// problems are only cached if all rules ran without errors
+		if len(errs) == 0 {
+			l.Cache.put(key, problems)
+		}
+	}
+
+	problems = l.filterProblems(fileContext, problems)
+	if l.Baseline != nil {
+		l.reportStaleBaselineEntries(path)
 	}
 
-	writeToFile := false
 	for _, problem := range problems {
-		message := makeMessage(problem, l.Formatter, path)
+		message := makeMessage(problem, l.Formatter, path, l.Severity(path, problem.RuleID))
 		l.Reporter.Report(message)
+	}
 
-		if l.Fix {
-			fixed, err := fixProblem(problem, fileContext)
-			if err != nil {
-				l.Reporter.Errorf("Error fixing problem: %v", err)
-			}
-			if fixed {
-				log.Printf("[%s] Fixed %s in %s", problem.RuleID, problem.Diff.FieldName, fileContext.Path)
-				writeToFile = true
+	if !l.Fix {
+		return fileContext.Data, false
+	}
+	fixed, ok := l.fix(fileContext, problems, requiredRules, rules)
+	return matchLineEndings(fileContext.Data, fixed), ok
+}
+
+// maxFixPasses is the maximum number of times fixes are applied to a file, each
+// time to the problems found in the data fixed by the previous pass.
+const maxFixPasses = 10
+
+// fix applies the fixes of the problems to the data of fc in a single pass, and
+// then lints and fixes the fixed data again until no more problems are fixed,
+// such as problems whose fixes conflicted with others. Only the problems of the
+// first pass are reported, and later passes only fix problems that would be
+// reported, which are counted as fixed like those of the first pass. Unsafe fixes are skipped unless FixUnsafe is set. Fixing stops at
+// the last pass whose fixed data can be parsed. Returns the fixed data, and true
+// if any problem was fixed.
+func (l *Linter) fix(fc *FileContext, problems []*Problem, requiredRules, rules []Rule) ([]byte, bool) {
+	data := fc.Data
+	anyFixed := false
+	for pass := 1; ; pass++ {
+		if !l.FixUnsafe {
+			problems = slices.DeleteFunc(slices.Clone(problems), func(problem *Problem) bool {
+				return problem.UnsafeFix
+			})
+		}
+		fixed, applied, err := ApplyFixes(data, problems)
+		l.reportFixErrors(err, pass)
+		if len(applied) == 0 {
+			return data, anyFixed
+		}
+
+		// The fixes of a pass are only kept if the fixed data can be parsed, so
+		// that a fix that breaks the syntax is never written.
+		next, err := NewFileContextFromData(fc.Path, fixed)
+		if err != nil {
+			l.Reporter.Errorf("Fixing %s would make it unparsable, stopping fixes: %v", fc.Path, err)
+			return data, anyFixed
+		}
+		for _, problem := range applied {
+			if l.FixDryRun {
+				log.Printf("[%s] Would fix %s in %s", problem.RuleID, fixedName(problem), fc.Path)
+			} else {
+				l.Reporter.Fixed(problem.RuleID, fc.Path)
+				log.Printf("[%s] Fixed %s in %s", problem.RuleID, fixedName(problem), fc.Path)
 			}
+			l.recordRename(fc.Path, problem)
 		}
+		data, anyFixed = fixed, true
+		if pass == maxFixPasses {
+			return data, true
+		}
+
+		problems, _ = validate(next, requiredRules, rules)
+		problems = slices.DeleteFunc(problems, func(problem *Problem) bool {
+			return l.hidden(next, problem)
+		})
 	}
+}
 
-	if writeToFile {
-		err = os.WriteFile(fileContext.Path, fileContext.Data, 0644)
-		if err != nil {
-			l.Reporter.Errorf("Error writing to %s: %v", fileContext.Path, err)
+// reportFixErrors reports the errors of the problems of a fix pass that cannot
+// be fixed. These are not errors of the run, since the problems are reported
+// as they are: misconfigured problems are reported for debugging, like those
+// that rules return, and others as warnings in the first pass, whose problems
+// are the reported ones.
+func (l *Linter) reportFixErrors(err error, pass int) {
+	errs := []error{err}
+	if joined, ok := err.(interface{ Unwrap() []error }); ok {
+		errs = joined.Unwrap()
+	}
+	for _, err := range errs {
+		switch {
+		case err == nil:
+		case pass == 1 && !errors.Is(err, ProblemIsMisconfigured):
+			l.Reporter.Warningf("Unable to fix problem: %v", err)
+		default:
+			l.Reporter.Debugf("Unable to fix problem: %v", err)
 		}
 	}
+}
 
-	if l.Reporter.ErrorCount() > 0 {
-		l.HasErrors = true
+// fixedName returns the name of what fixing the problem changed, for logging,
+// or the message of the problem if it has no field name.
+func fixedName(problem *Problem) string {
+	if problem.Diff != nil && problem.Diff.FieldName != "" {
+		return problem.Diff.FieldName
+	}
+	return fmt.Sprintf("%q", strings.TrimSuffix(problem.Message, "."))
+}
+
+// hidden returns true if the problem would not be reported, because problems
+// are recorded to RecordBaseline, or it is in the Baseline, or Filter rejects
+// it. Unlike filterProblems, it neither records nor matches the problem.
+func (l *Linter) hidden(fc *FileContext, problem *Problem) bool {
+	return l.RecordBaseline != nil || l.Baseline.contains(NewBaselineEntry(fc, problem)) ||
+		l.Filter != nil && !l.Filter(fc.Path, problem)
+}
+
+// filterProblems returns the problems that should be reported. Problems are
+// recorded to RecordBaseline, or hidden if they are in the Baseline, before
+// Filter is applied, so that baseline matching sees every problem.
+func (l *Linter) filterProblems(fc *FileContext, problems []*Problem) []*Problem {
+	var result []*Problem
+	for _, problem := range problems {
+		if l.RecordBaseline != nil {
+			l.RecordBaseline.Add(NewBaselineEntry(fc, problem))
+			continue
+		}
+		if l.Baseline != nil && l.Baseline.match(NewBaselineEntry(fc, problem)) {
+			continue
+		}
+		if l.Filter != nil && !l.Filter(fc.Path, problem) {
+			continue
+		}
+		result = append(result, problem)
+	}
+	return result
+}
+
+// reportStaleBaselineEntries reports a warning for each baseline entry of the
+// file at the given path that no longer matches a problem.
+func (l *Linter) reportStaleBaselineEntries(path string) {
+	for _, entry := range l.Baseline.unmatched(path) {
+		entity := ""
+		if entry.Entity != "" {
+			entity = fmt.Sprintf(" in %s", entry.Entity)
+		}
+		message := diagnostic.Warningf("Baseline entry [%s]%s no longer occurs, and can be removed from the baseline: %s",
+			entry.Rule, entity, entry.Message)
+		l.Reporter.Report(message.With(diagnostic.File(path)))
+	}
+}
+
+// reportRemovedBaselineFiles reports the baseline entries of the files that
+// were not linted because they no longer exist, like those that no longer match
+// a problem. The entries of existing files that were not linted are kept, since
+// a run may only cover some of the files of the baseline.
+func (l *Linter) reportRemovedBaselineFiles(paths []string) {
+	linted := make(map[string]bool)
+	for _, path := range paths {
+		linted[baselinePath(path)] = true
+	}
+	for _, file := range l.Baseline.files() {
+		if linted[file] {
+			continue
+		}
+		path := filepath.FromSlash(file)
+		if _, err := os.Stat(path); errors.Is(err, fs.ErrNotExist) {
+			l.reportStaleBaselineEntries(path)
+		}
 	}
 }
 
+// validate runs the required rules on the given fileContext, and the other
+// rules only if the required rules found no problems. Returns the problems
+// found, and the errors returned by the rules.
+func validate(fc *FileContext, requiredRules, rules []Rule) ([]*Problem, []error) {
+	// validate that required rules are present
+	problems, errs := lintWithRules(fc, requiredRules)
+
+	// validate the rule set only if there are no missing fields
+	if len(problems) == 0 {
+		ruleProblems, ruleErrs := lintWithRules(fc, rules)
+		problems = append(problems, ruleProblems...)
+		errs = append(errs, ruleErrs...)
+	}
+	return problems, errs
+}
+
 // lintWithRules runs the given rules on the given fileContext and returns the
-// problems found. reporter is used to report any errors that occur while running.
-func lintWithRules(fc *FileContext, rules []Rule, reporter *diagnostic.Reporter) []*Problem {
+// problems found, and the errors returned by the rules.
+func lintWithRules(fc *FileContext, rules []Rule) ([]*Problem, []error) {
 	var problems []*Problem
+	var errs []error
 	for _, rule := range rules {
 		p, err := rule.Validate(fc)
+		if errors.Is(err, ProblemIsMisconfigured) {
+			err = fmt.Errorf("Rule %s returned a misconfigured lint Problem: %w", rule.ID(), err)
+		}
 		if err != nil {
-			if errors.Is(err, ProblemIsMisconfigured) {
-				reporter.Debugf("Rule %s returned a misconfigured lint Problem: %v", rule.ID(), err)
-			} else {
-				reporter.Errorf("%v", err)
-			}
+			errs = append(errs, err)
 		}
 		problems = append(problems, p...)
 	}
-	return problems
+	return problems, errs
 }
 
-// fixProblem updates fc.Data by fixing the problem in the file. Returns true
-// if the problem was fixed, false otherwise.
-func fixProblem(problem *Problem, fc *FileContext) (bool, error) {
-	if !problem.IsFixable {
-		return false, nil
+// ruleSettings returns the ID and severity of each of the given rules for the
+// file at the given path, which are part of the cache key. Rules that implement
+// fmt.Stringer, such as custom rules, also include their string.
+func (l *Linter) ruleSettings(path string, ruleSets ...[]Rule) []string {
+	var settings []string
+	for _, rules := range ruleSets {
+		for _, rule := range rules {
+			setting := fmt.Sprintf("%s=%s", rule.ID(), l.Severity(path, rule.ID()))
+			if s, ok := rule.(fmt.Stringer); ok {
+				setting += " " + s.String()
+			}
+			settings = append(settings, setting)
+		}
 	}
+	return settings
+}
 
-	startLine := problem.StartPosition().LineNumber
-	endLine := problem.EndPosition().LineNumber
-	want := problem.Diff.Want
-	got := problem.Diff.Got
-
-	fileLines := strings.Split(string(fc.Data), "\n")
-	if startLine < 1 || endLine > len(fileLines) {
-		return false, fmt.Errorf("Invalid line range for fix: %d-%d", startLine, endLine)
+// hasCrossFileRules returns true if any of the rules depends on other files.
+func (l *Linter) hasCrossFileRules() bool {
+	for _, rule := range l.Rules() {
+		if r, ok := rule.(CrossFileRule); ok && r.CrossFile() {
+			return true
+		}
 	}
+	return false
+}
 
-	linesToFix := fileLines[startLine-1 : endLine]
-	contentToFix := strings.Join(linesToFix, "\n")
-	if strings.Count(contentToFix, got) != 1 {
-		return false, fmt.Errorf("Cannot fix issue in file: %s, '%s' not found exactly once", fc.Path, got)
+// enabledRules returns the rules that are not turned off in the configuration
+// for the file at the given path, and the opt-in rules whose severity it sets.
+func (l *Linter) enabledRules(path string, rules []Rule) []Rule {
+	var result []Rule
+	for _, rule := range rules {
+		setting, ok := l.Config.RuleSetting(path, rule.ID())
+		if ok && setting == config.Off || !ok && MetadataOf(rule).OptIn {
+			continue
+		}
+		result = append(result, rule)
 	}
+	return result
+}
 
-	fixedContent := strings.Replace(contentToFix, got, want, 1)
-	// Replace the lines in the file with the fixed content
-	fileLines = append(
-		fileLines[:startLine-1], // Lines before the start of the fix
-		append(strings.Split(fixedContent, "\n"), // Fixed content split into lines
-			fileLines[endLine:]...)..., // Lines after the end of the fix
-	)
-	fc.Data = []byte(strings.Join(fileLines, "\n"))
-
-	return true, nil
+// Severity returns the severity of the rule with the given ID for the file at
+// the given path. The configured severity takes precedence over the rule's
+// default severity.
+func (l *Linter) Severity(path, ruleID string) Severity {
+	if setting, ok := l.Config.RuleSetting(path, ruleID); ok && setting != config.Off {
+		return setting.Severity()
+	}
+	for _, rule := range l.Rules() {
+		if rule.ID() == ruleID {
+			return MetadataOf(rule).Severity
+		}
+	}
+	return diagnostic.SeverityNotice
 }
 
-// makeMessage creates a diagnostic message from the given problem using the formatter.
-func makeMessage(problem *Problem, formatter Formatter, path string) *diagnostic.Message {
+// makeMessage creates a diagnostic message with the given severity from the
+// given problem using the formatter.
+func makeMessage(problem *Problem, formatter Formatter, path string, severity diagnostic.Severity) *diagnostic.Message {
 	var attachments []diagnostic.Attachment
 
 	// Add available location data
 	attachments = append(attachments, diagnostic.File(path))
+	attachments = append(attachments, diagnostic.Rule(problem.RuleID))
+	attachments = append(attachments, diagnostic.Fixable(problem.IsFixable))
+	attachments = append(attachments, diagnostic.UnsafeFix(problem.IsFixable && problem.UnsafeFix))
 	if start := problem.StartPosition(); start != nil {
 		if end := problem.EndPosition(); end != nil {
 			attachments = append(attachments, diagnostic.LineRange(start.LineNumber, end.LineNumber))
@@ -181,7 +621,7 @@ func makeMessage(problem *Problem, formatter Formatter, path string) *diagnostic
 	}
 
 	msg := formatter.Format(problem)
-	message := diagnostic.Noticef("%s", msg)
+	message := diagnostic.NewMessage(severity, "%s", msg)
 	return message.With(
 		attachments...,
 	)
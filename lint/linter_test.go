@@ -0,0 +1,80 @@
+package lint_test
+
+import (
+	"fmt"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic/diagnostictest"
+	"github.com/verily-src/fsh-lint/lint"
+	"github.com/verily-src/fsh-lint/rules"
+)
+
+func TestLinter_LintFiles(t *testing.T) {
+	dir := t.TempDir()
+	var paths, want []string
+	for i := range 20 {
+		path := filepath.Join(dir, fmt.Sprintf("Example%02d.fsh", i))
+		data := fmt.Sprintf("ValueSet: Example%02d\nId: wrong-id\nTitle: \"Wrong Title\"\n", i)
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+		// Lint the files in reverse order, to check that output is sorted.
+		paths = append([]string{path}, paths...)
+		want = append(want, path+":2:value-set-name-matches-id", path+":3:value-set-name-matches-title")
+	}
+
+	reporter, printer := diagnostictest.NewFakeReporter()
+	// The title rule runs first, to check that messages are sorted by position.
+	linter := lint.NewLinter(nil, []lint.Rule{&rules.ValueSetNameMatchesTitleRule{}, &rules.ValueSetNameMatchesIDRule{}})
+	linter.Reporter = reporter
+	linter.Jobs = 4
+
+	linter.LintFiles(paths)
+
+	var got []string
+	for _, message := range printer.Messages {
+		got = append(got, fmt.Sprintf("%s:%d:%s", message.File, message.Line, message.Rule))
+	}
+	if diff := cmp.Diff(got, want); diff != "" {
+		t.Errorf("LintFiles() messages mismatch (-got +want):\n%s", diff)
+	}
+}
+
+func TestLinter_ProblemsInFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Example.fsh")
+	data := []byte("ValueSet: Example\nId: wrong-id\nTitle: \"Example\"\n")
+	if err := os.WriteFile(path, data, 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	linter := lint.NewLinter(nil, []lint.Rule{&rules.ValueSetNameMatchesIDRule{}})
+	linter.Fix = true
+
+	problems, err := linter.ProblemsInFile(path)
+	if err != nil {
+		t.Fatalf("ProblemsInFile() error: %v", err)
+	}
+	var got []string
+	for _, problem := range problems {
+		got = append(got, fmt.Sprintf("%d:%s", problem.StartPosition().LineNumber, problem.RuleID))
+	}
+	if diff := cmp.Diff(got, []string{"2:value-set-name-matches-id"}); diff != "" {
+		t.Errorf("ProblemsInFile() mismatch (-got +want):\n%s", diff)
+	}
+
+	// The file is not fixed, even with Fix set.
+	gotData, err := os.ReadFile(path)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(gotData), string(data)); diff != "" {
+		t.Errorf("ProblemsInFile() changed the file (-got +want):\n%s", diff)
+	}
+
+	if _, err := linter.ProblemsInFile(filepath.Join(t.TempDir(), "Missing.fsh")); err == nil {
+		t.Error("ProblemsInFile() of a missing file returned no error")
+	}
+}
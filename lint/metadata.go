@@ -0,0 +1,155 @@
+package lint
+
+import (
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic"
+)
+
+// DocsBaseURL is the base URL of the rule documentation. Rule IDs are appended
+// as anchors to build the documentation URL of a rule.
+const DocsBaseURL = "https://github.com/verily-src/fsh-lint/blob/main/docs/rules.md"
+
+// Category is the group a rule is listed under in the documentation.
+type Category string
+
+const (
+	// CategorySpecial represents rules that apply across entity kinds.
+	CategorySpecial Category = "Special"
+
+	// CategoryCodeSystem represents rules that apply to code systems.
+	CategoryCodeSystem Category = "Code System"
+
+	// CategoryExtension represents rules that apply to extensions.
+	CategoryExtension Category = "Extension"
+
+	// CategoryInstance represents rules that apply to instances.
+	CategoryInstance Category = "Instance"
+
+	// CategoryProfile represents rules that apply to profiles.
+	CategoryProfile Category = "Profile"
+
+	// CategoryValueSet represents rules that apply to value sets.
+	CategoryValueSet Category = "Value Set"
+)
+
+// Categories is the list of all categories in the order they are documented.
+var Categories = []Category{
+	CategorySpecial,
+	CategoryCodeSystem,
+	CategoryExtension,
+	CategoryInstance,
+	CategoryProfile,
+	CategoryValueSet,
+}
+
+// EntityKind is a kind of FSH entity that a rule can apply to.
+type EntityKind string
+
+const (
+	// EntityCodeSystem represents a FSH CodeSystem.
+	EntityCodeSystem EntityKind = "CodeSystem"
+
+	// EntityExtension represents a FSH Extension.
+	EntityExtension EntityKind = "Extension"
+
+	// EntityInstance represents a FSH Instance.
+	EntityInstance EntityKind = "Instance"
+
+	// EntityProfile represents a FSH Profile.
+	EntityProfile EntityKind = "Profile"
+
+	// EntityValueSet represents a FSH ValueSet.
+	EntityValueSet EntityKind = "ValueSet"
+)
+
+// EntityKinds is the list of all entity kinds.
+var EntityKinds = []EntityKind{
+	EntityCodeSystem,
+	EntityExtension,
+	EntityInstance,
+	EntityProfile,
+	EntityValueSet,
+}
+
+// Example is a FSH snippet that either follows or violates a rule.
+type Example struct {
+	// Description explains what the example shows. Optional.
+	Description string
+
+	// FSH is the FSH source of the example. Required.
+	FSH string
+
+	// Good indicates whether the example follows the rule (true) or violates
+	// it (false).
+	Good bool
+}
+
+// Metadata describes a rule for documentation and tooling purposes.
+type Metadata struct {
+	// Category is the group the rule is listed under. Required.
+	Category Category
+
+	// Description is a markdown description of what the rule checks. Required.
+	Description string
+
+	// Examples are FSH snippets that follow or violate the rule. Optional.
+	Examples []Example
+
+	// EntityKinds are the kinds of entities the rule applies to. Optional.
+	EntityKinds []EntityKind
+
+	// Severity is the default severity of problems reported by the rule. When
+	// empty, problems are reported as notices.
+	Severity diagnostic.Severity
+
+	// Fixable indicates whether the rule can automatically fix problems.
+	Fixable bool
+
+	// OptIn indicates that the rule only runs for the files whose configuration
+	// sets its severity, since its problems are not wrong in every project.
+	OptIn bool
+
+	// UnsafeFix indicates that some fixes of the rule may break references, or
+	// otherwise change the meaning of the file, and are only applied when unsafe
+	// fixes are enabled.
+	UnsafeFix bool
+
+	// Resources are markdown formatted references relevant to the rule. Optional.
+	Resources []string
+
+	// DocsURL is the URL of the documentation of the rule. When empty, the URL
+	// is built from DocsBaseURL and the rule ID.
+	DocsURL string
+}
+
+// Documented is an optional interface that a Rule can implement to provide
+// metadata about itself.
+type Documented interface {
+	// Metadata returns the metadata of the rule.
+	Metadata() *Metadata
+}
+
+// MetadataOf returns the metadata of the given rule. If the rule does not
+// implement Documented, metadata is built from the rule's message. Defaults
+// are filled in for the severity and docs URL when not set.
+func MetadataOf(r Rule) *Metadata {
+	var md Metadata
+	if d, ok := r.(Documented); ok {
+		if m := d.Metadata(); m != nil {
+			md = *m
+		}
+	}
+
+	if md.Category == "" {
+		md.Category = CategorySpecial
+	}
+	if md.Description == "" {
+		md.Description = r.Message()
+	}
+	if md.Severity == "" {
+		md.Severity = diagnostic.SeverityNotice
+	}
+	if md.DocsURL == "" {
+		md.DocsURL = DocsBaseURL + "#" + r.ID()
+	}
+	return &md
+}
@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 )
 
 var ProblemIsMisconfigured = fmt.Errorf("problem is misconfigured")
@@ -15,21 +15,38 @@ type Problem struct {
 	Message string
 
 	// Location provides the location of the problem. Required.
-	Location *types.Location
+	Location *ast.Location
 
 	// Diff provides the expected and found values of a field. Optional.
 	Diff *Diff
 
+	// Edits are the text edits that fix the problem, by byte offset in the
+	// file. Optional, fixable problems without edits are fixed by replacing
+	// Diff.Got with Diff.Want at the location, as a fallback for rules that do
+	// not know the offsets of their fixes.
+	Edits []*TextEdit
+
 	// RuleID provides the id of the rule this problem is violation of. Required.
 	RuleID string
 
 	// IsFixable indicates whether the problem can be automatically fixed. Required.
 	IsFixable bool
+
+	// UnsafeFix indicates that the fix of the problem may break references to
+	// the entity, such as a change to its ID or name, or otherwise change the
+	// meaning of the file, so it is only applied when unsafe fixes are enabled.
+	// Optional.
+	UnsafeFix bool
+
+	// Renames indicates that the fix of the problem renames an entity, by
+	// changing its name or ID from Diff.Got to Diff.Want, so that references to
+	// it can be renamed as well. Optional.
+	Renames bool
 }
 
 // NewProblem creates a new Problem instance with the given parameters, and returns
 // an error if it is misconfigured (e.g., if IsFixable is true but Diff or Location is nil).
-func NewProblem(ruleID string, message string, location *types.Location, diff *Diff, isFixable bool) (*Problem, error) {
+func NewProblem(ruleID string, message string, location *ast.Location, diff *Diff, isFixable bool) (*Problem, error) {
 	if isFixable == true {
 		if diff == nil {
 			return nil, fmt.Errorf("diff must not be nil if problem is fixable: %w", ProblemIsMisconfigured)
@@ -48,8 +65,43 @@ func NewProblem(ruleID string, message string, location *types.Location, diff *D
 	}, nil
 }
 
+// NewProblemWithEdits creates a fixable Problem whose fix is the given edits,
+// such as insertions of missing content, and returns an error if there are no
+// edits. The diff is optional, and only describes the fix.
+func NewProblemWithEdits(ruleID string, message string, location *ast.Location, diff *Diff, edits []*TextEdit) (*Problem, error) {
+	if len(edits) == 0 {
+		return nil, fmt.Errorf("edits must not be empty if problem is fixable: %w", ProblemIsMisconfigured)
+	}
+
+	return &Problem{
+		Message:   message,
+		Location:  location,
+		Diff:      diff,
+		Edits:     edits,
+		RuleID:    ruleID,
+		IsFixable: true,
+	}, nil
+}
+
+// NewRenameProblem creates a fixable Problem whose fix is the given edits, that
+// rename an entity by replacing its name or ID Diff.Got with Diff.Want, and
+// returns an error if there is no diff or no edits. The fix is unsafe, since
+// changing the name or ID changes the canonical URL, which breaks references.
+func NewRenameProblem(ruleID string, message string, location *ast.Location, diff *Diff, edits []*TextEdit) (*Problem, error) {
+	if diff == nil {
+		return nil, fmt.Errorf("diff must not be nil if problem renames an entity: %w", ProblemIsMisconfigured)
+	}
+	problem, err := NewProblemWithEdits(ruleID, message, location, diff, edits)
+	if err != nil {
+		return nil, err
+	}
+	problem.UnsafeFix = true
+	problem.Renames = true
+	return problem, nil
+}
+
 // StartPosition returns the start position of the problem, or nil if not available.
-func (p *Problem) StartPosition() *types.Position {
+func (p *Problem) StartPosition() *ast.Position {
 	if p.Location == nil {
 		return nil
 	}
@@ -57,7 +109,7 @@ func (p *Problem) StartPosition() *types.Position {
 }
 
 // EndPosition returns the end position of the problem, or nil if not available.
-func (p *Problem) EndPosition() *types.Position {
+func (p *Problem) EndPosition() *ast.Position {
 	if p.Location == nil {
 		return nil
 	}
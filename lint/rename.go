@@ -0,0 +1,213 @@
+package lint
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"log"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/verily-src/fsh-lint/ast"
+	"github.com/verily-src/fsh-lint/internal/atomicfile"
+	"github.com/verily-src/fsh-lint/internal/symbols"
+)
+
+// Rename renames the entity of the symbol from old to new in files, the data
+// of the indexed files by path. The name or ID of the definition of the entity
+// is renamed if it is old, as are the references to the entity written as old.
+// Returns the renamed data of the files that changed, by path, or an error if
+// old does not occur at one of these locations, such as when a file changed
+// after it was indexed. Nothing is renamed if there is an error.
+func Rename(files map[string][]byte, idx *symbols.Index, def *symbols.Symbol, old, new string) (map[string][]byte, error) {
+	locations := make(map[string][]*ast.Location)
+	if def.Name == old {
+		locations[def.Path] = append(locations[def.Path], def.Location)
+	}
+	if def.ID == old && def.IDLocation != nil {
+		locations[def.Path] = append(locations[def.Path], def.IDLocation)
+	}
+	for _, ref := range idx.ReferencesTo(def, old) {
+		locations[ref.Path] = append(locations[ref.Path], ref.Location)
+	}
+
+	renamed := make(map[string][]byte)
+	for path, locs := range locations {
+		data, ok := files[path]
+		if !ok {
+			return nil, fmt.Errorf("%s is not one of the renamed files", path)
+		}
+		var edits []*TextEdit
+		for _, location := range locs {
+			start, ok := nameOffset(data, location, old)
+			if !ok {
+				return nil, fmt.Errorf("%s:%d: %s does not occur at its parsed location", path, location.Start.LineNumber, old)
+			}
+			edits = append(edits, &TextEdit{Start: start, End: start + len(old), NewText: new})
+		}
+		result, err := ApplyEdits(data, edits)
+		if err != nil {
+			return nil, fmt.Errorf("renaming %s in %s: %w", old, path, err)
+		}
+		renamed[path] = result
+	}
+	return renamed, nil
+}
+
+// nameOffset returns the byte offset in data of the first occurrence of name
+// as a whole word at or after the start of the location, on the lines of the
+// location. Some locations start at the keyword before the name, such as the
+// system of an include rule.
+func nameOffset(data []byte, location *ast.Location, name string) (int, bool) {
+	start, ok := Offset(data, location.Start)
+	if !ok || name == "" {
+		return 0, false
+	}
+	end := len(data)
+	if next, ok := Offset(data, &ast.Position{LineNumber: location.End.LineNumber + 1}); ok {
+		end = next
+	}
+	for i := start; i < end; {
+		j := bytes.Index(data[i:end], []byte(name))
+		if j < 0 {
+			return 0, false
+		}
+		i += j
+		if (i == 0 || isNameDelimiter(data[i-1])) && (i+len(name) == len(data) || isNameDelimiter(data[i+len(name)])) {
+			return i, true
+		}
+		i++
+	}
+	return 0, false
+}
+
+// isNameDelimiter returns true if the byte cannot be part of a name or ID.
+func isNameDelimiter(b byte) bool {
+	return strings.IndexByte(" \t\r\n(),\"", b) >= 0
+}
+
+// rename is a change of the name or ID of an entity defined in the file at
+// path, made by the fix of a problem of the rule.
+type rename struct {
+	ruleID, path, old, new string
+}
+
+// recordRename records the rename that the fix of the problem made in the file
+// at the given path, if it renames an entity and LintFiles renames references.
+func (l *Linter) recordRename(path string, problem *Problem) {
+	if !problem.Renames || problem.Diff == nil {
+		return
+	}
+	l.renameMu.Lock()
+	defer l.renameMu.Unlock()
+	if l.fixedData == nil {
+		return
+	}
+	l.renames = append(l.renames, &rename{ruleID: problem.RuleID, path: path, old: problem.Diff.Got, new: problem.Diff.Want})
+}
+
+// recordFixedData records the fixed data of the file at the given path, to
+// rename references in it once all files are fixed, if LintFiles renames
+// references.
+func (l *Linter) recordFixedData(path string, data []byte) {
+	l.renameMu.Lock()
+	defer l.renameMu.Unlock()
+	if l.fixedData != nil {
+		l.fixedData[path] = data
+	}
+}
+
+// renameReferences renames the references in the files at the given paths to
+// the entities whose name or ID the fixes of LintFiles changed, and writes the
+// renamed files with WriteFile. References are not renamed if the former name
+// or ID is still defined, since they may refer to that definition instead.
+func (l *Linter) renameReferences(paths []string) {
+	if len(l.renames) == 0 {
+		return
+	}
+	slices.SortFunc(l.renames, func(a, b *rename) int {
+		return cmp.Or(cmp.Compare(a.path, b.path), cmp.Compare(a.old, b.old))
+	})
+
+	// Files that cannot be read or parsed were already reported, and are
+	// skipped.
+	files := make(map[string][]byte)
+	idx := symbols.NewIndex()
+	index := func(path string, data []byte) {
+		fc, err := NewFileContextFromData(path, data)
+		if err != nil {
+			idx.Remove(path)
+			return
+		}
+		files[path] = data
+		idx.Add(path, fc.ParsedFSH)
+	}
+	for _, path := range paths {
+		data, ok := l.fixedData[path]
+		if !ok {
+			var err error
+			if data, err = os.ReadFile(path); err != nil {
+				continue
+			}
+		}
+		index(path, data)
+	}
+
+	// The files are on disk as indexed, since fixed files were written.
+	original := maps.Clone(files)
+	changed := make(map[string]bool)
+	for _, r := range l.renames {
+		if len(idx.Lookup(r.old)) > 0 {
+			l.Reporter.Warningf("References to %s are not renamed to %s, since %s is still defined", r.old, r.new, r.old)
+			continue
+		}
+		i := slices.IndexFunc(idx.Symbols(r.path), func(s *symbols.Symbol) bool {
+			return s.Name == r.new || s.ID == r.new
+		})
+		if i < 0 {
+			continue
+		}
+		renamed, err := Rename(files, idx, idx.Symbols(r.path)[i], r.old, r.new)
+		if err != nil {
+			l.Reporter.Errorf("Error renaming references to %s: %v", r.old, err)
+			continue
+		}
+		for _, path := range slices.Sorted(maps.Keys(renamed)) {
+			if l.FixDryRun {
+				log.Printf("[%s] Would rename references to %s in %s", r.ruleID, r.old, path)
+			} else {
+				log.Printf("[%s] Renamed references to %s in %s", r.ruleID, r.old, path)
+			}
+			index(path, renamed[path])
+			changed[path] = true
+		}
+	}
+
+	l.writeRenamed(slices.Sorted(maps.Keys(changed)), original, files)
+}
+
+// writeRenamed writes the renamed files at the given paths with WriteFile, or
+// replaces them on disk if it is not set, in which case nothing is written if a
+// file changed on disk since it was read. If a write fails, the files that were
+// already written are restored to their original contents, so that either all
+// or none of the references are renamed.
+func (l *Linter) writeRenamed(paths []string, original, renamed map[string][]byte) {
+	if l.WriteFile == nil {
+		for _, path := range paths {
+			if err := atomicfile.Check(path, original[path]); err != nil {
+				l.Reporter.Errorf("Error writing to %s: %v", path, err)
+				return
+			}
+		}
+	}
+	for i, path := range paths {
+		if !l.writeFile(path, original[path], renamed[path]) {
+			for _, written := range paths[:i] {
+				l.writeFile(written, renamed[written], original[written])
+			}
+			return
+		}
+	}
+}
@@ -0,0 +1,59 @@
+package lint_test
+
+import (
+	"errors"
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic/diagnostictest"
+	"github.com/verily-src/fsh-lint/lint"
+	"github.com/verily-src/fsh-lint/rules"
+)
+
+func TestLinter_RenameReferences_WriteFails(t *testing.T) {
+	dir := t.TempDir()
+	valueSet := filepath.Join(dir, "ExampleOne.fsh")
+	first := filepath.Join(dir, "FirstProfile.fsh")
+	second := filepath.Join(dir, "SecondProfile.fsh")
+	files := map[string]string{
+		valueSet: "ValueSet: ExampleOne\nId: example-two\nTitle: \"Example One\"\n",
+		first:    "Profile: FirstProfile\nParent: Patient\n* gender from example-two (required)\n",
+		second:   "Profile: SecondProfile\nParent: Patient\n* gender from example-two (required)\n",
+	}
+	for path, data := range files {
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reporter, printer := diagnostictest.NewFakeReporter()
+	linter := lint.NewLinter(nil, []lint.Rule{&rules.ValueSetNameMatchesIDRule{}})
+	linter.Reporter = reporter
+	linter.Fix = true
+	linter.FixUnsafe = true
+	linter.RenameReferences = true
+	linter.WriteFile = func(path string, data []byte) error {
+		if path == second {
+			return errors.New("disk full")
+		}
+		return os.WriteFile(path, data, 0644)
+	}
+
+	linter.LintFiles([]string{valueSet, first, second})
+
+	if got := reporter.ErrorCount(); got != 1 {
+		t.Errorf("LintFiles() reported %d errors, want 1: %v", got, printer.Messages)
+	}
+	// The references of the first file are restored, since those of the second
+	// could not be renamed.
+	for _, path := range []string{first, second} {
+		got, err := os.ReadFile(path)
+		if err != nil {
+			t.Fatal(err)
+		}
+		if string(got) != files[path] {
+			t.Errorf("LintFiles() changed %s to %q, want %q", filepath.Base(path), got, files[path])
+		}
+	}
+}
@@ -14,3 +14,15 @@ type Rule interface {
 	// This method should return a list of problems found in the file and an error if one occurs.
 	Validate(*FileContext) ([]*Problem, error)
 }
+
+// CrossFileRule is implemented by rules whose problems in a file depend on the
+// contents of other files in the project, such as rules that resolve references
+// between entities. Cached problems of a file are invalidated when any file in
+// the project changes if a cross-file rule is enabled.
+type CrossFileRule interface {
+	Rule
+
+	// CrossFile returns true if the problems found by the rule depend on the
+	// contents of other files.
+	CrossFile() bool
+}
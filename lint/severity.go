@@ -0,0 +1,14 @@
+package lint
+
+import "github.com/verily-src/fsh-lint/internal/cli/diagnostic"
+
+// Severity is the severity of a lint problem.
+type Severity = diagnostic.Severity
+
+// The severities a rule can report problems with, from most to least severe.
+const (
+	SeverityError   = diagnostic.SeverityError
+	SeverityWarning = diagnostic.SeverityWarning
+	SeverityNotice  = diagnostic.SeverityNotice
+	SeverityDebug   = diagnostic.SeverityDebug
+)
@@ -1,33 +1,119 @@
 package main
 
 import (
+	"crypto/sha256"
+	"errors"
 	"fmt"
+	"io"
 	"io/fs"
 	"log"
+	"maps"
 	"os"
 	"path/filepath"
+	"runtime/debug"
+	"slices"
+	"sort"
 	"strings"
+	"sync"
 
+	"github.com/bmatcuk/doublestar/v4"
 	"github.com/spf13/pflag"
 	"github.com/verily-src/fsh-lint/internal/cli/diagnostic"
+	"github.com/verily-src/fsh-lint/internal/config"
+	"github.com/verily-src/fsh-lint/internal/gitdiff"
+	"github.com/verily-src/fsh-lint/internal/ignore"
+	"github.com/verily-src/fsh-lint/internal/textdiff"
 	"github.com/verily-src/fsh-lint/lint"
+	"github.com/verily-src/fsh-lint/rules"
 )
 
+// version is the version of the linter, set at build time.
+var version = "dev"
+
 func main() {
 	log.SetFlags(0) // ignore timestamp formatting
 
-	installFlags(pflag.CommandLine)
-	pflag.Parse()
+	if err := run(os.Stdout, os.Args[1:]); err != nil {
+		log.Fatal(err)
+	}
+}
+
+// run runs the command named by the first argument with the rest of args.
+// Requesting the usage of a command with --help is not an error.
+func run(w io.Writer, args []string) error {
+	// Running without a command is an alias for the lint command, to keep the
+	// original flag-only invocation working.
+	cmd := lintCommand
+	if len(args) > 0 && !strings.HasPrefix(args[0], "-") {
+		cmd = findCommand(args[0])
+		if cmd == nil {
+			return fmt.Errorf("unknown command %q, run 'fsh-lint help' for usage", args[0])
+		}
+		args = args[1:]
+	}
 
-	files, err := filesFromFlags(pflag.CommandLine)
+	if err := cmd.Run(w, args); err != nil && !errors.Is(err, pflag.ErrHelp) {
+		return err
+	}
+	return nil
+}
+
+// runLint parses the lint flags from args and runs the Linter against the
+// selected files.
+func runLint(_ io.Writer, args []string) error {
+	fs := pflag.NewFlagSet("lint", pflag.ExitOnError)
+	installFlags(fs)
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	if Linter.Config == nil {
+		cfg, err := configFromFlags(fs)
+		if err != nil {
+			return err
+		}
+		if err := applyConfig(Linter, cfg); err != nil {
+			return err
+		}
+	}
+
+	stdin, err := fs.GetBool("stdin")
 	if err != nil {
-		log.Fatal(err)
+		return err
+	}
+
+	changes, err := changesFromFlags(fs)
+	if err != nil {
+		return err
+	}
+
+	var files []string
+	if stdin {
+		if fs.Changed("env") || fs.Changed("paths") || changes != nil {
+			return fmt.Errorf("--stdin cannot be used with --env, --paths, or --changed-since")
+		}
+	} else {
+		files, err = filesFromFlags(fs, changes)
+		if err != nil {
+			return err
+		}
+	}
+
+	newCodeOnly, err := fs.GetBool("new-code-only")
+	if err != nil {
+		return err
+	}
+	if newCodeOnly {
+		if changes == nil {
+			return fmt.Errorf("--new-code-only requires --changed-since")
+		}
+		Linter.Filter = newCodeFilter(changes)
 	}
 
 	if Linter.Reporter == nil {
-		reporter, err := diagnostic.ReporterFromFlags(pflag.CommandLine)
+		reporter, err := diagnostic.ReporterFromFlags(fs)
 		if err != nil {
-			log.Fatal(err)
+			return err
 		}
 		Linter.Reporter = reporter
 	}
@@ -36,64 +122,450 @@ func main() {
 		Linter.Formatter = &lint.DefaultFormatter{}
 	}
 
-	Linter.Fix = pflag.CommandLine.Changed("fix")
+	fixDryRun, err := fs.GetBool("fix-dry-run")
+	if err != nil {
+		return err
+	}
+	if fixDryRun && fs.Changed("fix") {
+		return fmt.Errorf("only one of --fix or --fix-dry-run must be used")
+	}
+	fixUnsafe, err := fs.GetBool("fix-unsafe")
+	if err != nil {
+		return err
+	}
+	Linter.Fix = fs.Changed("fix") || fixDryRun || fixUnsafe
+	Linter.FixUnsafe = fixUnsafe
+	fixReferences, err := fs.GetBool("fix-references")
+	if err != nil {
+		return err
+	}
+	if fixReferences && !fixUnsafe {
+		return fmt.Errorf("--fix-references requires --fix-unsafe")
+	}
+	Linter.RenameReferences = fixReferences
+
+	jobs, err := fs.GetInt("jobs")
+	if err != nil {
+		return err
+	}
+	if jobs < 0 {
+		return fmt.Errorf("--jobs must not be negative, got %d", jobs)
+	}
+	Linter.Jobs = jobs
+
+	writeBaseline, err := baselineFromFlags(fs, Linter)
+	if err != nil {
+		return err
+	}
+
+	summary, err := fs.GetBool("summary")
+	if err != nil {
+		return err
+	}
+
+	if stdin {
+		filename, err := fs.GetString("stdin-filename")
+		if err != nil {
+			return err
+		}
+		if writeBaseline != "" || fs.Changed("watch") || fixDryRun || fixReferences {
+			return fmt.Errorf("--write-baseline, --watch, --fix-dry-run, and --fix-references cannot be used with --stdin")
+		}
+		return RunLinterOnReader(Linter, filename, os.Stdin, os.Stdout, summary)
+	}
 
-	RunLinter(Linter, files)
+	useCache, err := cacheFromFlags(fs)
+	if err != nil {
+		return err
+	}
+	if useCache {
+		Linter.Cache = lint.LoadCache(lint.CacheFileName, linterVersion())
+	}
+
+	watching, err := fs.GetBool("watch")
+	if err != nil {
+		return err
+	}
+	if watching {
+		if writeBaseline != "" || fixDryRun || fixReferences {
+			return fmt.Errorf("--write-baseline, --fix-dry-run, and --fix-references cannot be used with --watch")
+		}
+		return runWatch(fs, Linter)
+	}
+
+	if writeBaseline != "" {
+		if fixDryRun {
+			return fmt.Errorf("--fix-dry-run cannot be used with --write-baseline")
+		}
+		return RunLinterForBaseline(Linter, files, writeBaseline)
+	}
+
+	if fixDryRun {
+		return RunLinterForDiff(Linter, files, os.Stdout, summary)
+	}
+
+	RunLinter(Linter, files, summary)
+	return nil
 }
 
-// RunLinter runs the Linter against all files in the given paths. Exits with
-// a non-zero exit code if any error-level problems are found.
-func RunLinter(linter *lint.Linter, paths []string) {
-	for _, path := range paths {
-		linter.Lint(path)
+// baselineFromFlags sets the baseline of the linter from --baseline. Returns the
+// path given by --write-baseline to write a new baseline to, if any.
+func baselineFromFlags(fs *pflag.FlagSet, linter *lint.Linter) (string, error) {
+	baselinePath, err := fs.GetString("baseline")
+	if err != nil {
+		return "", err
+	}
+	writeBaseline, err := fs.GetString("write-baseline")
+	if err != nil {
+		return "", err
+	}
+
+	if baselinePath != "" && writeBaseline != "" {
+		return "", fmt.Errorf("only one of --baseline or --write-baseline must be used")
+	}
+	if baselinePath != "" {
+		baseline, err := lint.LoadBaseline(baselinePath)
+		if err != nil {
+			return "", err
+		}
+		linter.Baseline = baseline
+	}
+	return writeBaseline, nil
+}
+
+// RunLinterOnReader runs the Linter against the FSH content read from r, as if
+// it were the content of a file named filename. If the linter fixes problems,
+// the fixed content is written to w instead of to disk. Exits with a non-zero
+// exit code if any error-level problems are found.
+func RunLinterOnReader(linter *lint.Linter, filename string, r io.Reader, w io.Writer, summary bool) error {
+	data, err := io.ReadAll(r)
+	if err != nil {
+		return fmt.Errorf("reading %s from stdin: %w", filename, err)
+	}
+
+	fixed := linter.LintData(filename, data)
+	if linter.Fix {
+		if _, err := w.Write(fixed); err != nil {
+			return err
+		}
+	}
+
+	if summary && linter.Reporter != nil {
+		linter.Reporter.PrintSummary()
 	}
 
 	// when the linter has error-level lint problems, exit with an error to indicate blocking
 	if linter.HasErrors {
 		os.Exit(1)
 	}
+	return nil
 }
 
-// installFlags installs the flags --env, --paths, --fix, --output-format, and
-// --debug to the given flag set.
+// changesFromFlags returns the changes relative to the git ref given by
+// --changed-since, or nil if it is not given.
+func changesFromFlags(fs *pflag.FlagSet) (*gitdiff.Changes, error) {
+	ref, err := fs.GetString("changed-since")
+	if err != nil || ref == "" {
+		return nil, err
+	}
+	return gitdiff.Since(ref)
+}
+
+// newCodeFilter returns a lint.Linter filter that only keeps problems located
+// on changed lines. Problems without a location apply to the whole file, and
+// are kept.
+func newCodeFilter(changes *gitdiff.Changes) func(string, *lint.Problem) bool {
+	return func(path string, problem *lint.Problem) bool {
+		start := problem.StartPosition()
+		if start == nil {
+			return true
+		}
+		end := start
+		if problem.EndPosition() != nil {
+			end = problem.EndPosition()
+		}
+		return changes.Overlaps(path, start.LineNumber, end.LineNumber)
+	}
+}
+
+// configFromFlags loads the configuration file given by --config, or the
+// default configuration file if it exists. Returns nil if there is none.
+func configFromFlags(fs *pflag.FlagSet) (*config.Config, error) {
+	path, err := fs.GetString("config")
+	if err != nil {
+		return nil, err
+	}
+	if path == "" {
+		if _, err := os.Stat(config.FileName); err != nil {
+			return nil, nil
+		}
+		path = config.FileName
+	}
+	return config.Load(path)
+}
+
+// applyConfig sets the configuration of the linter, and the custom rules it
+// defines. Returns an error if a custom rule is invalid or has the ID of a
+// built-in rule.
+func applyConfig(linter *lint.Linter, cfg *config.Config) error {
+	customRules, err := rules.CustomRules(cfg)
+	if err != nil {
+		return err
+	}
+	linter.CustomRules = nil
+	for _, custom := range customRules {
+		for _, rule := range linter.Rules() {
+			if rule.ID() == custom.ID() {
+				return fmt.Errorf("custom rule %s has the ID of a built-in rule", custom.ID())
+			}
+		}
+	}
+	var metadataOrder *config.MetadataOrder
+	if cfg != nil {
+		metadataOrder = cfg.MetadataOrder
+	}
+	for _, rule := range linter.Rules() {
+		if order, ok := rule.(*rules.EntityMetadataOrderRule); ok {
+			order.Configure(metadataOrder)
+		}
+	}
+	linter.Config = cfg
+	linter.CustomRules = customRules
+	return nil
+}
+
+// RunLinter runs the Linter against all files in the given paths, printing an
+// end-of-run summary if summary is true. Exits with a non-zero exit code if any
+// error-level problems are found.
+func RunLinter(linter *lint.Linter, paths []string, summary bool) {
+	linter.LintFiles(paths)
+
+	if summary && linter.Reporter != nil {
+		linter.Reporter.PrintSummary()
+	}
+
+	// when the linter has error-level lint problems, exit with an error to indicate blocking
+	if linter.HasErrors {
+		os.Exit(1)
+	}
+}
+
+// cacheFromFlags returns true if --cache is given, and --no-cache is not.
+func cacheFromFlags(fs *pflag.FlagSet) (bool, error) {
+	useCache, err := fs.GetBool("cache")
+	if err != nil {
+		return false, err
+	}
+	noCache, err := fs.GetBool("no-cache")
+	if err != nil {
+		return false, err
+	}
+	return useCache && !noCache, nil
+}
+
+// linterVersion returns the version of the linter used in the cache key. For
+// builds without a version, such as local builds from source, a hash of the
+// executable is used so that the cache is invalidated whenever it is rebuilt.
+func linterVersion() string {
+	if version != "dev" {
+		return version
+	}
+	if info, ok := debug.ReadBuildInfo(); ok && info.Main.Version != "" && info.Main.Version != "(devel)" {
+		return info.Main.Version
+	}
+	if exe, err := os.Executable(); err == nil {
+		if data, err := os.ReadFile(exe); err == nil {
+			return fmt.Sprintf("%s-%x", version, sha256.Sum256(data))
+		}
+	}
+	return version
+}
+
+// RunLinterForDiff runs the Linter against all files in the given paths, writing
+// a unified diff of the changes that fixing the problems would make to w instead
+// of writing them to disk, and printing an end-of-run summary if summary is
+// true. Exits with a non-zero exit code if any file would change or any
+// error-level problems are found.
+func RunLinterForDiff(linter *lint.Linter, paths []string, w io.Writer, summary bool) error {
+	changed, err := writeFixDiffs(linter, paths, w)
+	if err != nil {
+		return err
+	}
+
+	if summary && linter.Reporter != nil {
+		linter.Reporter.PrintSummary()
+	}
+
+	if changed || linter.HasErrors {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// writeFixDiffs lints the files at the given paths with fixes enabled, and
+// writes the unified diffs of the files that the fixes would change to w,
+// sorted by path, without changing the files. Returns true if any file would
+// change.
+func writeFixDiffs(linter *lint.Linter, paths []string, w io.Writer) (bool, error) {
+	var mu sync.Mutex
+	diffs := make(map[string]string)
+	linter.Fix, linter.FixDryRun = true, true
+	linter.WriteFile = func(path string, data []byte) error {
+		original, err := os.ReadFile(path)
+		if err != nil {
+			return err
+		}
+		if diff := textdiff.Unified(path, original, data); diff != "" {
+			mu.Lock()
+			defer mu.Unlock()
+			diffs[path] = diff
+		}
+		return nil
+	}
+	linter.LintFiles(paths)
+
+	for _, path := range slices.Sorted(maps.Keys(diffs)) {
+		if _, err := io.WriteString(w, diffs[path]); err != nil {
+			return false, err
+		}
+	}
+	return len(diffs) > 0, nil
+}
+
+// RunLinterForBaseline runs the Linter against all files in the given paths,
+// writing every problem found to a new baseline at baselinePath instead of
+// reporting it. Exits with a non-zero exit code if any errors are reported,
+// such as files that cannot be parsed.
+func RunLinterForBaseline(linter *lint.Linter, paths []string, baselinePath string) error {
+	if linter.RecordBaseline == nil {
+		linter.RecordBaseline = lint.NewBaseline()
+	}
+	linter.LintFiles(paths)
+
+	if err := linter.RecordBaseline.Write(baselinePath); err != nil {
+		return fmt.Errorf("writing baseline: %w", err)
+	}
+	log.Printf("Wrote %d problems to the baseline %s", len(linter.RecordBaseline.Entries), baselinePath)
+
+	if linter.HasErrors {
+		os.Exit(1)
+	}
+	return nil
+}
+
+// installFlags installs the flags --env, --paths, --stdin, --stdin-filename,
+// --exclude, --changed-since, --new-code-only, --config, --baseline,
+// --write-baseline, --fix, --fix-dry-run, --fix-unsafe, --fix-references,
+// --watch, --watch-poll, --cache, --no-cache, --jobs, --output-format, --debug,
+// and --summary to the given flag set.
 func installFlags(fs *pflag.FlagSet) {
 	// input flags
 	fs.String("env", "", "Read new line delimited list of files or directories from the given environment variable.")
 	fs.String("paths", "", "Read comma delimited list of files or directories. For file names with spaces, use quotes.")
+	fs.Bool("stdin", false, "Read FSH content from stdin. With --fix, the fixed content is written to stdout.")
+	fs.String("stdin-filename", "stdin.fsh", "The filename to use for content read with --stdin, for filename-based rules and messages.")
+	fs.StringSlice("exclude", nil, "Comma delimited list of patterns, with .gitignore semantics, of files or directories to skip.")
+	fs.String("changed-since", "", "Only lint files changed since the given git ref. Without --env or --paths, all changed files are linted.")
+	fs.Bool("new-code-only", false, "Only report problems on lines changed since the --changed-since ref.")
 
 	// configuration flags
-	fs.Bool("fix", false, "Modify files and fix linting errors if possible.")
+	fs.String("config", "", fmt.Sprintf("Path to the configuration file. Defaults to %s in the working directory, if it exists.", config.FileName))
+	fs.String("baseline", "", "Hide the known problems recorded in the given baseline file, and warn about entries that no longer occur.")
+	fs.String("write-baseline", "", "Record every problem found to the given baseline file instead of reporting it.")
+	fs.Bool("fix", false, "Modify files and fix linting errors if possible. The fixed files are linted and fixed again, and the problems that only these later passes find and fix are counted as fixed in the summary, but not reported.")
+	fs.Bool("fix-dry-run", false, "Print a unified diff of the changes --fix would make without modifying files, and exit with a non-zero exit code if there are any.")
+	fs.Bool("fix-unsafe", false, "Like --fix, and also apply unsafe fixes, such as changes to IDs, that may break references. With --fix-dry-run, they are included in the diff.")
+	fs.Bool("fix-references", false, "With --fix-unsafe, also rename the references in the linted files to the IDs that fixes change.")
+	fs.Bool("watch", false, "Keep running, and lint the files again whenever they change.")
+	fs.Bool("watch-poll", false, "With --watch, poll for changes instead of using file system notifications.")
+	fs.Bool("cache", false, fmt.Sprintf("Cache the problems found in each file in %s, so that unchanged files are skipped on later runs.", lint.CacheFileName))
+	fs.Bool("no-cache", false, "Do not use the cache, even if --cache is given.")
+	fs.Int("jobs", 0, "Number of files to lint concurrently. Defaults to the number of CPUs.")
 
 	// diagnostic flags
 	diagnostic.MustInstallFlags(fs)
 }
 
 // filesFromFlags returns a list of files from the flags. If both --env and
-// --paths are given, an error is returned.
-func filesFromFlags(fs *pflag.FlagSet) ([]string, error) {
+// --paths are given, an error is returned. If changes is not nil, only changed
+// files are returned, and neither --env nor --paths is required.
+func filesFromFlags(fs *pflag.FlagSet, changes *gitdiff.Changes) ([]string, error) {
+	paths, err := inputPathsFromFlags(fs)
+	if err != nil {
+		return nil, err
+	}
+	if paths == nil {
+		if changes == nil {
+			return nil, fmt.Errorf("one of --env, --paths, --changed-since, or --stdin must be used")
+		}
+		paths = changes.Files()
+	}
+
+	ignored, err := ignoreMatchersFromFlags(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	files := fshFilesFromPaths(paths, ignored)
+	if changes == nil {
+		return files, nil
+	}
+
+	var changed []string
+	for _, file := range files {
+		if changes.HasFile(file) {
+			changed = append(changed, file)
+		}
+	}
+	return changed, nil
+}
+
+// inputPathsFromFlags returns the paths given by --env or --paths, or nil if
+// neither is given. If both are given, an error is returned.
+func inputPathsFromFlags(fs *pflag.FlagSet) ([]string, error) {
 	envGiven := fs.Changed("env")
 	pathsGiven := fs.Changed("paths")
 
 	if envGiven && pathsGiven {
 		return nil, fmt.Errorf("only one of --env or --paths must be used")
 	}
-	if !envGiven && !pathsGiven {
-		return nil, fmt.Errorf("one of --env or --paths must be used")
-	}
 
-	var paths []string
 	if envGiven {
 		envName := fs.Lookup("env").Value.String()
 		env := os.Getenv(envName)
 		if env == "" {
 			return nil, fmt.Errorf("environment variable %s is not set or empty", envName)
 		}
-		paths = pathsFromString(env, "\n")
-	} else if pathsGiven {
-		paths = pathsFromString(fs.Lookup("paths").Value.String(), ",")
+		return pathsFromString(env, "\n"), nil
+	}
+	if pathsGiven {
+		return pathsFromString(fs.Lookup("paths").Value.String(), ","), nil
+	}
+	return nil, nil
+}
+
+// ignoreMatchersFromFlags returns the matchers of files to exclude from
+// linting, built from the ignore file in the working directory, the
+// configuration's exclude patterns, and the --exclude flag.
+func ignoreMatchersFromFlags(fs *pflag.FlagSet) (ignore.Matchers, error) {
+	fromFile, err := ignore.Load(ignore.FileName)
+	if err != nil {
+		return nil, fmt.Errorf("reading %s: %w", ignore.FileName, err)
+	}
+	matchers := ignore.Matchers{fromFile}
+
+	// The configuration's exclude patterns are relative to the directory of the
+	// configuration file, rather than the working directory.
+	if Linter.Config != nil {
+		matchers = append(matchers, ignore.New(Linter.Config.Dir, Linter.Config.Exclude...))
 	}
 
-	return fshFilesFromPaths(paths), nil
+	exclude, err := fs.GetStringSlice("exclude")
+	if err != nil {
+		return nil, err
+	}
+	matchers = append(matchers, ignore.New(".", exclude...))
+	return matchers, nil
 }
 
 // pathsFromString returns a list of paths from the given string. The string is
@@ -116,10 +588,11 @@ func pathsFromString(s string, delim string) []string {
 
 // fshFilesFromPaths returns a list of files from the given paths. If a path is a directory,
 // it will walk the directory and return all files with the .fsh extension. If a path is a file,
-// it will return the file if it has the .fsh extension.
-func fshFilesFromPaths(paths []string) []string {
+// it will return the file if it has the .fsh extension. Paths may be doublestar glob patterns,
+// which are expanded before being resolved. Any file or directory ignored by the matchers is skipped.
+func fshFilesFromPaths(paths []string, ignored ignore.Matchers) []string {
 	fileSet := make(map[string]struct{})
-	for _, path := range paths {
+	for _, path := range expandGlobs(paths) {
 		info, err := os.Stat(path)
 		if err != nil {
 			log.Fatalf("Error accessing path %s: %v", path, err)
@@ -132,6 +605,12 @@ func fshFilesFromPaths(paths []string) []string {
 					log.Fatalf("Error walking path %s: %v", p, err)
 					return nil
 				}
+				if ignored.Ignored(p, d.IsDir()) {
+					if d.IsDir() {
+						return filepath.SkipDir
+					}
+					return nil
+				}
 				if !d.IsDir() && filepath.Ext(d.Name()) == ".fsh" {
 					fileSet[p] = struct{}{}
 				}
@@ -140,7 +619,7 @@ func fshFilesFromPaths(paths []string) []string {
 			if err != nil {
 				log.Fatalf("Error walking directory %s: %v", path, err)
 			}
-		} else if filepath.Ext(info.Name()) == ".fsh" {
+		} else if filepath.Ext(info.Name()) == ".fsh" && !ignored.Ignored(path, false) {
 			fileSet[path] = struct{}{}
 		}
 	}
@@ -149,5 +628,34 @@ func fshFilesFromPaths(paths []string) []string {
 	for file := range fileSet {
 		files = append(files, file)
 	}
+	sort.Strings(files)
 	return files
 }
+
+// expandGlobs returns the given paths with any doublestar glob patterns replaced
+// by the paths they match. Paths without glob meta characters are kept as-is so
+// that missing paths are still reported as errors.
+func expandGlobs(paths []string) []string {
+	var result []string
+	for _, path := range paths {
+		if !hasGlobMeta(path) {
+			result = append(result, path)
+			continue
+		}
+
+		matches, err := doublestar.FilepathGlob(path)
+		if err != nil {
+			log.Fatalf("Error expanding glob %s: %v", path, err)
+		}
+		if len(matches) == 0 {
+			log.Printf("No files match the pattern %s", path)
+		}
+		result = append(result, matches...)
+	}
+	return result
+}
+
+// hasGlobMeta returns true if the path contains any glob meta characters.
+func hasGlobMeta(path string) bool {
+	return strings.ContainsAny(path, "*?[{")
+}
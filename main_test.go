@@ -0,0 +1,216 @@
+package main
+
+import (
+	"bytes"
+	"os"
+	"path/filepath"
+	"strings"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/ast"
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic/diagnostictest"
+	"github.com/verily-src/fsh-lint/internal/gitdiff"
+	"github.com/verily-src/fsh-lint/lint"
+	"github.com/verily-src/fsh-lint/rules"
+)
+
+func TestRunLinterOnReader(t *testing.T) {
+	const input = "ValueSet: ExampleOne\nId: example-two\nTitle: \"Example One\"\n"
+
+	testCases := []struct {
+		name      string
+		fix       bool
+		fixUnsafe bool
+		wantOut   string
+		wantFiles []string
+	}{
+		{
+			name:      "reports problems with the virtual filename",
+			fix:       false,
+			wantOut:   "",
+			wantFiles: []string{"input/fsh/ExampleOne.fsh"},
+		}, {
+			name:      "writes fixed content to the writer",
+			fix:       true,
+			fixUnsafe: true,
+			wantOut:   "ValueSet: ExampleOne\nId: example-one\nTitle: \"Example One\"\n",
+			wantFiles: []string{"input/fsh/ExampleOne.fsh"},
+		}, {
+			name:      "does not apply unsafe fixes by default",
+			fix:       true,
+			wantOut:   input,
+			wantFiles: []string{"input/fsh/ExampleOne.fsh"},
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			reporter, printer := diagnostictest.NewFakeReporter()
+			linter := lint.NewLinter(nil, []lint.Rule{&rules.ValueSetNameMatchesIDRule{}})
+			linter.Reporter = reporter
+			linter.Fix = tc.fix
+			linter.FixUnsafe = tc.fixUnsafe
+			var out bytes.Buffer
+
+			err := RunLinterOnReader(linter, "input/fsh/ExampleOne.fsh", strings.NewReader(input), &out, false)
+			if err != nil {
+				t.Fatalf("RunLinterOnReader(): got error %v, want nil", err)
+			}
+
+			if diff := cmp.Diff(out.String(), tc.wantOut); diff != "" {
+				t.Errorf("RunLinterOnReader() output mismatch (-got +want):\n%s", diff)
+			}
+			var gotFiles []string
+			for _, message := range printer.Messages {
+				gotFiles = append(gotFiles, message.File)
+			}
+			if diff := cmp.Diff(gotFiles, tc.wantFiles); diff != "" {
+				t.Errorf("RunLinterOnReader() message files mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewCodeFilter(t *testing.T) {
+	const diff = "+++ b/input/fsh/Example.fsh\n@@ -2 +2 @@\n"
+	changes, err := gitdiff.Parse(strings.NewReader(diff))
+	if err != nil {
+		t.Fatalf("Parse(): got error %v, want nil", err)
+	}
+	filter := newCodeFilter(changes)
+
+	testCases := []struct {
+		name     string
+		path     string
+		location *ast.Location
+		want     bool
+	}{
+		{
+			name:     "problem on a changed line",
+			path:     "input/fsh/Example.fsh",
+			location: &ast.Location{Start: &ast.Position{LineNumber: 2}, End: &ast.Position{LineNumber: 2}},
+			want:     true,
+		}, {
+			name:     "problem on an unchanged line",
+			path:     "input/fsh/Example.fsh",
+			location: &ast.Location{Start: &ast.Position{LineNumber: 1}, End: &ast.Position{LineNumber: 1}},
+			want:     false,
+		}, {
+			name:     "problem spanning a changed line",
+			path:     "input/fsh/Example.fsh",
+			location: &ast.Location{Start: &ast.Position{LineNumber: 1}, End: &ast.Position{LineNumber: 3}},
+			want:     true,
+		}, {
+			name:     "problem without a location",
+			path:     "input/fsh/Example.fsh",
+			location: nil,
+			want:     true,
+		}, {
+			name:     "problem in an unchanged file",
+			path:     "input/fsh/Other.fsh",
+			location: &ast.Location{Start: &ast.Position{LineNumber: 2}, End: &ast.Position{LineNumber: 2}},
+			want:     false,
+		},
+	}
+
+	for _, tc := range testCases {
+		t.Run(tc.name, func(t *testing.T) {
+			got := filter(tc.path, &lint.Problem{Location: tc.location})
+
+			if got != tc.want {
+				t.Errorf("newCodeFilter(): got %t, want %t", got, tc.want)
+			}
+		})
+	}
+}
+
+func TestWriteFixDiffs(t *testing.T) {
+	dir := t.TempDir()
+	wrong := filepath.Join(dir, "ExampleOne.fsh")
+	const wrongData = "ValueSet: ExampleOne\nId: example-two\nTitle: \"Example One\"\n"
+	fixed := filepath.Join(dir, "ExampleTwo.fsh")
+	const fixedData = "ValueSet: ExampleTwo\nId: example-two\nTitle: \"Example Two\"\n"
+	for path, data := range map[string]string{wrong: wrongData, fixed: fixedData} {
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reporter, _ := diagnostictest.NewFakeReporter()
+	linter := lint.NewLinter(nil, []lint.Rule{&rules.ValueSetNameMatchesIDRule{}})
+	linter.Reporter = reporter
+	linter.FixUnsafe = true
+	cachePath := filepath.Join(dir, lint.CacheFileName)
+	linter.Cache = lint.LoadCache(cachePath, "test")
+	var out bytes.Buffer
+
+	changed, err := writeFixDiffs(linter, []string{fixed, wrong}, &out)
+	if err != nil {
+		t.Fatalf("writeFixDiffs(): got error %v, want nil", err)
+	}
+	if !changed {
+		t.Error("writeFixDiffs(): got no changes, want changes")
+	}
+
+	path := filepath.ToSlash(wrong)
+	want := "--- a/" + path + "\n+++ b/" + path + "\n" +
+		"@@ -1,3 +1,3 @@\n ValueSet: ExampleOne\n-Id: example-two\n+Id: example-one\n Title: \"Example One\"\n"
+	if diff := cmp.Diff(out.String(), want); diff != "" {
+		t.Errorf("writeFixDiffs() output mismatch (-got +want):\n%s", diff)
+	}
+
+	// The files are not changed.
+	got, err := os.ReadFile(wrong)
+	if err != nil {
+		t.Fatal(err)
+	}
+	if diff := cmp.Diff(string(got), wrongData); diff != "" {
+		t.Errorf("writeFixDiffs() changed the file (-got +want):\n%s", diff)
+	}
+	// Nothing is counted as fixed, and the cache is not saved.
+	if summary := reporter.Summary(); summary.Fixed != 0 || summary.Fixable != 1 {
+		t.Errorf("writeFixDiffs() got %d fixed and %d fixable problems, want 0 and 1", summary.Fixed, summary.Fixable)
+	}
+	if _, err := os.Stat(cachePath); !os.IsNotExist(err) {
+		t.Errorf("writeFixDiffs() saved the cache, got error %v, want not exist", err)
+	}
+}
+
+func TestWriteFixDiffs_RenameReferences(t *testing.T) {
+	dir := t.TempDir()
+	valueSet := filepath.Join(dir, "ExampleOne.fsh")
+	profile := filepath.Join(dir, "ExampleProfile.fsh")
+	files := map[string]string{
+		valueSet: "ValueSet: ExampleOne\nId: example-two\nTitle: \"Example One\"\n",
+		profile:  "Profile: ExampleProfile\nParent: Patient\n* gender from example-two (required)\n* maritalStatus from example-twos\n",
+	}
+	for path, data := range files {
+		if err := os.WriteFile(path, []byte(data), 0644); err != nil {
+			t.Fatal(err)
+		}
+	}
+
+	reporter, _ := diagnostictest.NewFakeReporter()
+	linter := lint.NewLinter(nil, []lint.Rule{&rules.ValueSetNameMatchesIDRule{}})
+	linter.Reporter = reporter
+	linter.FixUnsafe = true
+	linter.RenameReferences = true
+	var out bytes.Buffer
+
+	if _, err := writeFixDiffs(linter, []string{profile, valueSet}, &out); err != nil {
+		t.Fatalf("writeFixDiffs(): got error %v, want nil", err)
+	}
+
+	for _, want := range []string{
+		"-Id: example-two\n+Id: example-one\n",
+		"-* gender from example-two (required)\n+* gender from example-one (required)\n",
+	} {
+		if !strings.Contains(out.String(), want) {
+			t.Errorf("writeFixDiffs() got output %q, want it to contain %q", out.String(), want)
+		}
+	}
+	if strings.Contains(out.String(), "example-ones") {
+		t.Errorf("writeFixDiffs() got output %q, want other names unchanged", out.String())
+	}
+}
@@ -0,0 +1,101 @@
+// Package parse parses FHIR Shorthand (FSH) into the types of the ast package.
+//
+// This package and the ast package are the supported way to parse FSH outside
+// of this module, and follow its semantic versioning.
+package parse
+
+import (
+	"errors"
+	"fmt"
+	"os"
+
+	"github.com/verily-src/fsh-lint/ast"
+	"github.com/verily-src/fsh-lint/internal/fsh"
+)
+
+// SyntaxError is an error in the syntax of FSH, at a 1-based line and a 0-based
+// column.
+type SyntaxError = fsh.SyntaxError
+
+// SyntaxTree is the lossless concrete syntax tree of FSH, which keeps the
+// whitespace and comments that the document drops, attached to its nodes.
+// Printing an unmodified tree with its String method reproduces the FSH byte for
+// byte.
+type SyntaxTree = fsh.SyntaxTree
+
+// Node is an entity declaration, or a metadata or rule of an entity, of a
+// SyntaxTree.
+type Node = fsh.Node
+
+// NodeKind is the kind of a Node.
+type NodeKind = fsh.NodeKind
+
+// Kinds of nodes.
+const (
+	NodeEntity   = fsh.NodeEntity
+	NodeMetadata = fsh.NodeMetadata
+	NodeRule     = fsh.NodeRule
+	NodeOther    = fsh.NodeOther
+)
+
+// Token is a token of FSH, including whitespace and comments. Its Kind is the
+// name of the token in the FSH lexer grammar, or one of the token kinds below.
+type Token = fsh.Token
+
+// Kinds of tokens that are not named by the FSH lexer grammar.
+const (
+	TokenLineComment  = fsh.TokenLineComment
+	TokenBlockComment = fsh.TokenBlockComment
+	TokenNewline      = fsh.TokenNewline
+	TokenWhitespace   = fsh.TokenWhitespace
+	TokenUnknown      = fsh.TokenUnknown
+)
+
+// Parse parses FSH into a document. If the FSH has syntax errors, the returned
+// error joins one *SyntaxError for each of them, which can be listed with
+// SyntaxErrors.
+func Parse(data []byte) (*ast.FSHDocument, error) {
+	return fsh.Parse(string(data))
+}
+
+// ParseSyntaxTree parses FSH into its syntax tree, whose Document is the
+// document returned by Parse. Returns the errors of Parse.
+func ParseSyntaxTree(data []byte) (*SyntaxTree, error) {
+	return fsh.ParseSyntaxTree(string(data))
+}
+
+// ParseFile reads the FSH file at the given path and parses it into a document.
+// Errors are prefixed with the path, and wrap the errors returned by Parse.
+func ParseFile(path string) (*ast.FSHDocument, error) {
+	data, err := os.ReadFile(path)
+	if err != nil {
+		return nil, err
+	}
+	doc, err := Parse(data)
+	if err != nil {
+		return nil, fmt.Errorf("%s: %w", path, err)
+	}
+	return doc, nil
+}
+
+// SyntaxErrors returns the syntax errors in the tree of errors wrapped by err,
+// in order.
+func SyntaxErrors(err error) []*SyntaxError {
+	var result []*SyntaxError
+	var walk func(error)
+	walk = func(err error) {
+		switch e := err.(type) {
+		case nil:
+		case *SyntaxError:
+			result = append(result, e)
+		case interface{ Unwrap() []error }:
+			for _, inner := range e.Unwrap() {
+				walk(inner)
+			}
+		default:
+			walk(errors.Unwrap(err))
+		}
+	}
+	walk(err)
+	return result
+}
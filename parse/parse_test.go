@@ -0,0 +1,94 @@
+package parse_test
+
+import (
+	"os"
+	"path/filepath"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/parse"
+)
+
+func TestParse(t *testing.T) {
+	tests := []struct {
+		name          string
+		data          string
+		wantProfiles  []string
+		wantSyntaxErr []*parse.SyntaxError
+	}{
+		{
+			name:         "valid",
+			data:         "Profile: MyProfile\nParent: Patient\n",
+			wantProfiles: []string{"MyProfile"},
+		},
+		{
+			name: "syntax errors",
+			data: "Profile: MyProfile\nParent: Patient\n* name 1..\"\n",
+			wantSyntaxErr: []*parse.SyntaxError{
+				{Line: 3, Column: 7, Message: "extraneous input '1..\"' expecting {<EOF>, KW_ALIAS, KW_PROFILE, KW_EXTENSION, KW_INSTANCE, KW_INVARIANT, KW_VALUESET, KW_CODESYSTEM, KW_RULESET, KW_MAPPING, KW_LOGICAL, KW_RESOURCE}"},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			doc, err := parse.Parse([]byte(tt.data))
+			if diff := cmp.Diff(parse.SyntaxErrors(err), tt.wantSyntaxErr); diff != "" {
+				t.Errorf("Parse() syntax errors mismatch (-got +want):\n%s", diff)
+			}
+			if err != nil {
+				return
+			}
+			var got []string
+			for _, p := range doc.Profiles {
+				got = append(got, p.Name.Value)
+			}
+			if diff := cmp.Diff(got, tt.wantProfiles); diff != "" {
+				t.Errorf("Parse() profiles mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestParseFile(t *testing.T) {
+	path := filepath.Join(t.TempDir(), "Broken.fsh")
+	if err := os.WriteFile(path, []byte("Profile: MyProfile\nParent: Patient\n* name 1..\"\n"), 0644); err != nil {
+		t.Fatal(err)
+	}
+
+	_, err := parse.ParseFile(path)
+	if err == nil {
+		t.Fatal("ParseFile() returned no error, want a syntax error")
+	}
+	if got := parse.SyntaxErrors(err); len(got) != 1 || got[0].Line != 3 {
+		t.Errorf("SyntaxErrors() = %v, want one error on line 3", got)
+	}
+}
+
+func TestParseSyntaxTree(t *testing.T) {
+	paths, err := filepath.Glob(filepath.Join("..", "testdata", "*.fsh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	resources, err := filepath.Glob(filepath.Join("..", "internal", "fsh", "test", "resources", "*.fsh"))
+	if err != nil {
+		t.Fatal(err)
+	}
+	for _, path := range append(paths, resources...) {
+		t.Run(filepath.Base(path), func(t *testing.T) {
+			data, err := os.ReadFile(path)
+			if err != nil {
+				t.Fatal(err)
+			}
+			tree, err := parse.ParseSyntaxTree(data)
+			if err != nil {
+				t.Fatalf("ParseSyntaxTree() error = %v", err)
+			}
+			if diff := cmp.Diff(string(data), tree.String()); diff != "" {
+				t.Errorf("ParseSyntaxTree().String() mismatch (-want +got):\n%s", diff)
+			}
+			if got, want := len(tree.Nodes), len(tree.Document.Profiles)+len(tree.Document.ValueSets)+len(tree.Document.CodeSystems); got < want {
+				t.Errorf("ParseSyntaxTree() has %d nodes, want at least %d", got, want)
+			}
+		})
+	}
+}
@@ -0,0 +1,82 @@
+package main
+
+import (
+	"fmt"
+	"io"
+	"strings"
+	"text/tabwriter"
+
+	"github.com/spf13/pflag"
+	"github.com/verily-src/fsh-lint/ast"
+	"github.com/verily-src/fsh-lint/internal/query"
+)
+
+// queryMatch is the JSON representation of an element selected by the query
+// command.
+type queryMatch struct {
+	File string `json:"file"`
+	*query.Result
+}
+
+// runQuery prints the elements of the selected files that match the query, in
+// text or JSON format.
+func runQuery(w io.Writer, args []string) error {
+	fs := pflag.NewFlagSet("query", pflag.ContinueOnError)
+	installSelectionFlags(fs)
+	format := fs.String("format", "text", "The format to print the matching elements in, one of: text, json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("query requires exactly one query, such as 'Profiles[Title == nil]'")
+	}
+	if *format != "text" && *format != "json" {
+		return fmt.Errorf("invalid format: %s", *format)
+	}
+
+	q, err := query.Parse(fs.Arg(0))
+	if err != nil {
+		return fmt.Errorf("invalid query: %w", err)
+	}
+	files, err := parsedFilesFromFlags(fs)
+	if err != nil {
+		return err
+	}
+
+	matches := []*queryMatch{}
+	tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+	for _, f := range files {
+		lines := strings.Split(string(f.data), "\n")
+		for _, result := range q.Eval(f.doc) {
+			matches = append(matches, &queryMatch{File: f.path, Result: result})
+			if *format == "text" {
+				_, _ = fmt.Fprintf(tw, "%s\t%s\t%s\n", resultLocation(f.path, result), result.Path, resultText(result, lines))
+			}
+		}
+	}
+
+	if *format == "json" {
+		return writeJSON(w, matches)
+	}
+	return tw.Flush()
+}
+
+// resultLocation returns the path and line of a matching element.
+func resultLocation(path string, result *query.Result) string {
+	if result.Location == nil {
+		return path
+	}
+	return fmt.Sprintf("%s:%d", path, result.Location.Start.LineNumber)
+}
+
+// resultText returns the value of a matching string element, or otherwise the
+// source line the element starts on.
+func resultText(result *query.Result, lines []string) string {
+	if element, ok := result.Value.(*ast.ParsedElement[string]); ok {
+		return element.Value
+	}
+	if result.Location == nil || result.Location.Start.LineNumber > len(lines) {
+		return ""
+	}
+	return strings.TrimSpace(lines[result.Location.Start.LineNumber-1])
+}
@@ -0,0 +1,101 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"slices"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/verily-src/fsh-lint/internal/atomicfile"
+	"github.com/verily-src/fsh-lint/internal/symbols"
+	"github.com/verily-src/fsh-lint/internal/textdiff"
+	"github.com/verily-src/fsh-lint/lint"
+)
+
+// runRename renames the entity with the given name or ID in its definition and
+// in every reference to it in the selected files. All files are renamed in
+// memory before any is written, so that nothing is written if a reference
+// cannot be renamed, and are written with writeRenamed, so that either all or
+// none of them are renamed.
+func runRename(w io.Writer, args []string) error {
+	fs := pflag.NewFlagSet("rename", pflag.ContinueOnError)
+	installSelectionFlags(fs)
+	dryRun := fs.Bool("dry-run", false, "Print a unified diff of the changes without modifying files.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 2 {
+		return fmt.Errorf("rename requires an entity name or ID and its new value")
+	}
+	old, new := fs.Arg(0), fs.Arg(1)
+	if new == "" || strings.ContainsAny(new, " \t\r\n\"") {
+		return fmt.Errorf("invalid new name or ID %q", new)
+	}
+
+	files, err := parsedFilesFromFlags(fs)
+	if err != nil {
+		return err
+	}
+	data := make(map[string][]byte)
+	idx := symbols.NewIndex()
+	for _, f := range files {
+		data[f.path] = f.data
+		idx.Add(f.path, f.doc)
+	}
+
+	definitions := idx.Lookup(old)
+	switch {
+	case len(definitions) == 0:
+		return fmt.Errorf("%s is not defined in the selected files", old)
+	case len(definitions) > 1:
+		return fmt.Errorf("%s is defined more than once, in %s and %s", old, definitions[0].Path, definitions[1].Path)
+	case len(idx.Lookup(new)) > 0:
+		return fmt.Errorf("%s is already defined in the selected files", new)
+	}
+
+	renamed, err := lint.Rename(data, idx, definitions[0], old, new)
+	if err != nil {
+		return err
+	}
+	paths := slices.Sorted(maps.Keys(renamed))
+	if *dryRun {
+		for _, path := range paths {
+			if _, err := io.WriteString(w, textdiff.Unified(path, data[path], renamed[path])); err != nil {
+				return err
+			}
+		}
+		return nil
+	}
+
+	if err := writeRenamed(paths, data, renamed); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "Renamed %s to %s in %d files\n", old, new, len(paths))
+	return err
+}
+
+// writeRenamed replaces the files at the given paths, whose contents were
+// original when they were read, with their renamed contents. Nothing is written
+// if a file changed on disk since it was read. If a write fails, the files that
+// were already written are restored to their original contents, so that no
+// reference is left to a name that is not defined.
+func writeRenamed(paths []string, original, renamed map[string][]byte) error {
+	for _, path := range paths {
+		if err := atomicfile.Check(path, original[path]); err != nil {
+			return err
+		}
+	}
+	for i, path := range paths {
+		if err := atomicfile.Write(path, original[path], renamed[path]); err != nil {
+			err = fmt.Errorf("writing %s: %w", path, err)
+			for _, written := range paths[:i] {
+				err = errors.Join(err, atomicfile.Write(written, renamed[written], original[written]))
+			}
+			return err
+		}
+	}
+	return nil
+}
@@ -5,7 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/lint"
 )
 
@@ -29,6 +29,32 @@ func (r *CodeSystemNameMatchesFilenameRule) Message() string {
 	return fmt.Sprintf("Code system name must match filename (where filename does not include %s).", r.NameSuffix)
 }
 
+// Metadata() returns the documentation metadata for this rule.
+func (*CodeSystemNameMatchesFilenameRule) Metadata() *lint.Metadata {
+	return &lint.Metadata{
+		Category: lint.CategoryCodeSystem,
+		Description: "Code system name must match filename (where filename does not include the name suffix, e.g. `_CS`). This match is case sensitive, and the file extension must be `.fsh`.\n\n" +
+			"For example, code system name `ExampleOne_CS` **matches** filename `ExampleOne.fsh`, but **does not match** `ExampleOne_CS.fsh`, `exampleone.fsh`, or `ExampleOne.txt`.",
+		Examples: []lint.Example{
+			{
+				Description: "In a file named `ExampleOne.fsh`:",
+				FSH: `CodeSystem: ExampleOne_CS
+Id: example-one
+Title: "Example One"`,
+				Good: true,
+			},
+			{
+				Description: "In a file named `exampleone.fsh`:",
+				FSH: `CodeSystem: ExampleOne_CS
+Id: example-one
+Title: "Example One"`,
+				Good: false,
+			},
+		},
+		EntityKinds: []lint.EntityKind{lint.EntityCodeSystem},
+	}
+}
+
 // Validate returns a *lint.Problem for each code system name that does not match the filename.
 func (r *CodeSystemNameMatchesFilenameRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
 	var problems []*lint.Problem
@@ -46,7 +72,7 @@ func (r *CodeSystemNameMatchesFilenameRule) Validate(fc *lint.FileContext) ([]*l
 
 // CodeSystemNameMatchesFilenameViolation returns nil if the code system name with the NameSuffix removed
 // matches the filename, and a *lint.Problem otherwise.
-func CodeSystemNameMatchesFilenameViolation(cs *types.CodeSystem, fp string, nameSuffix string, id string, msg string) (*lint.Problem, error) {
+func CodeSystemNameMatchesFilenameViolation(cs *ast.CodeSystem, fp string, nameSuffix string, id string, msg string) (*lint.Problem, error) {
 	filename := filepath.Base(fp)
 	trimmedName := strings.TrimSuffix(cs.Name.Value, nameSuffix)
 	if strings.TrimSuffix(filename, ".fsh") != trimmedName {
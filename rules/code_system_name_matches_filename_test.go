@@ -4,7 +4,7 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/lint"
 	"github.com/verily-src/fsh-lint/rules"
 )
@@ -13,12 +13,12 @@ func TestLintCodeSystemNameMatchesFilename(t *testing.T) {
 	sut := rules.CodeSystemNameMatchesFilenameRule{NameSuffix: "_CS"}
 	ruleName := "CodeSystemNameMatchesFilenameRule"
 
-	testLocation := &types.Location{
-		Start: &types.Position{
+	testLocation := &ast.Location{
+		Start: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 2,
 		},
-		End: &types.Position{
+		End: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 4,
 		},
@@ -32,8 +32,8 @@ func TestLintCodeSystemNameMatchesFilename(t *testing.T) {
 		{
 			name: "no code systems",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					CodeSystems: []*types.CodeSystem{}},
+				ParsedFSH: &ast.FSHDocument{
+					CodeSystems: []*ast.CodeSystem{}},
 				Path: "path/to/CodeSystem.fsh",
 			},
 			want: nil,
@@ -41,10 +41,10 @@ func TestLintCodeSystemNameMatchesFilename(t *testing.T) {
 		{
 			name: "exact match",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					CodeSystems: []*types.CodeSystem{
+				ParsedFSH: &ast.FSHDocument{
+					CodeSystems: []*ast.CodeSystem{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleCodeSystem",
 								Location: testLocation,
 							},
@@ -58,10 +58,10 @@ func TestLintCodeSystemNameMatchesFilename(t *testing.T) {
 		{
 			name: "correct match no path",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					CodeSystems: []*types.CodeSystem{
+				ParsedFSH: &ast.FSHDocument{
+					CodeSystems: []*ast.CodeSystem{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleCodeSystem_CS",
 								Location: testLocation,
 							},
@@ -75,10 +75,10 @@ func TestLintCodeSystemNameMatchesFilename(t *testing.T) {
 		{
 			name: "correct match, path in root",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					CodeSystems: []*types.CodeSystem{
+				ParsedFSH: &ast.FSHDocument{
+					CodeSystems: []*ast.CodeSystem{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleCodeSystem_CS",
 								Location: testLocation,
 							},
@@ -92,10 +92,10 @@ func TestLintCodeSystemNameMatchesFilename(t *testing.T) {
 		{
 			name: "not match",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					CodeSystems: []*types.CodeSystem{
+				ParsedFSH: &ast.FSHDocument{
+					CodeSystems: []*ast.CodeSystem{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleCodeSystemOne_CS",
 								Location: testLocation,
 							},
@@ -121,10 +121,10 @@ func TestLintCodeSystemNameMatchesFilename(t *testing.T) {
 		{
 			name: "empty name",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					CodeSystems: []*types.CodeSystem{
+				ParsedFSH: &ast.FSHDocument{
+					CodeSystems: []*ast.CodeSystem{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "",
 								Location: testLocation,
 							},
@@ -150,16 +150,16 @@ func TestLintCodeSystemNameMatchesFilename(t *testing.T) {
 		{
 			name: "one matching, one non matching",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					CodeSystems: []*types.CodeSystem{
+				ParsedFSH: &ast.FSHDocument{
+					CodeSystems: []*ast.CodeSystem{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleCodeSystemOne_CS",
 								Location: testLocation,
 							},
 						},
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleCodeSystemTwo_CS",
 								Location: testLocation,
 							},
@@ -185,16 +185,16 @@ func TestLintCodeSystemNameMatchesFilename(t *testing.T) {
 		{
 			name: "two non matching",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					CodeSystems: []*types.CodeSystem{
+				ParsedFSH: &ast.FSHDocument{
+					CodeSystems: []*ast.CodeSystem{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleCodeSystemOne",
 								Location: testLocation,
 							},
 						},
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleCodeSystemOne_CS",
 								Location: testLocation,
 							},
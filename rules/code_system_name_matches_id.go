@@ -5,7 +5,7 @@ import (
 	"strings"
 
 	"github.com/iancoleman/strcase"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/internal/match"
 	"github.com/verily-src/fsh-lint/lint"
 )
@@ -30,12 +30,42 @@ func (r *CodeSystemNameMatchesIDRule) Message() string {
 	return fmt.Sprintf("Code system name (PascalCase) must match code system ID in kebab-case without the %s suffix.", r.NameSuffix)
 }
 
+// Metadata() returns the documentation metadata for this rule.
+func (*CodeSystemNameMatchesIDRule) Metadata() *lint.Metadata {
+	return &lint.Metadata{
+		Category: lint.CategoryCodeSystem,
+		Description: "Code system name (PascalCase) must match code system id in kebab-case without the name suffix (e.g. `_CS`).\n\n" +
+			"If there are numbers in the code system name, **any grouping with its adjacent alphabetic characters is acceptable**. In other words, hyphens in the id are optional when adjacent to a number. This flexible hyphenation applies only to numbers.\n\n" +
+			"If there are acronyms in the code system name, the acronym should be grouped as one word. Note, that the first letter of a word following an acronym should be capitalized in the PascalCase name.\n\n" +
+			"Note, the \"Want\" value that is generated, is one of the correct configurations where each group of numbers is grouped as their own separate word. This is the behavior of [strcase.ToDelimited](https://pkg.go.dev/github.com/iancoleman/strcase#ToDelimited).\n\n" +
+			"For example:\n\n" +
+			"- Code system name `ExampleOne_CS` **matches** id `example-one`, but **does not match** `example-one-cs` or `ExampleOne`.\n" +
+			"- Code system name `A123Example_CS` **matches** id `a-123-example`, `a123example`, `a1-23example`, and several others.\n" +
+			"- Code system name `ABCExample_CS` **matches** id `abc-example`, but **does not match** `a-b-c-example` or `abce-xample`.",
+		Examples: []lint.Example{
+			{
+				FSH: `CodeSystem: ExampleOne_CS
+Id: example-one`,
+				Good: true,
+			},
+			{
+				FSH: `CodeSystem: ExampleOne_CS
+Id: example-one-cs`,
+				Good: false,
+			},
+		},
+		EntityKinds: []lint.EntityKind{lint.EntityCodeSystem},
+		Fixable:     true,
+		UnsafeFix:   true,
+	}
+}
+
 // Validate returns a *lint.Problem for each code system name that does not match
 // its corresponding ID without the NameSuffix.
 func (r *CodeSystemNameMatchesIDRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
 	var problems []*lint.Problem
 	for _, cs := range fc.ParsedFSH.CodeSystems {
-		p, err := codeSystemNameMatchesIDViolation(cs, r.NameSuffix, r.ID(), r.Message())
+		p, err := codeSystemNameMatchesIDViolation(fc.Data, cs, r.NameSuffix, r.ID(), r.Message())
 		if err != nil {
 			return nil, err
 		}
@@ -48,7 +78,7 @@ func (r *CodeSystemNameMatchesIDRule) Validate(fc *lint.FileContext) ([]*lint.Pr
 
 // codeSystemNameMatchesIDViolation returns nil if the code system name
 // matches ID in kebab-case without the NameSuffix, and a *lint.Problem otherwise.
-func codeSystemNameMatchesIDViolation(cs *types.CodeSystem, nameSuffix string, id string, msg string) (*lint.Problem, error) {
+func codeSystemNameMatchesIDViolation(data []byte, cs *ast.CodeSystem, nameSuffix string, id string, msg string) (*lint.Problem, error) {
 	trimmedName := strings.TrimSuffix(cs.Name.Value, nameSuffix)
 	if !match.IsNameKebabMatchWithID(trimmedName, cs.ID.Value, true) {
 		// Code System Name does not match ID
@@ -58,7 +88,7 @@ func codeSystemNameMatchesIDViolation(cs *types.CodeSystem, nameSuffix string, i
 			FieldName: "Code System ID",
 		}
 
-		return lint.NewProblem(id, msg, cs.ID.Location, diff, true)
+		return renameValueProblem(data, id, msg, cs.ID, diff)
 	}
 	return nil, nil
 }
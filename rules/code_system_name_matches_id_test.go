@@ -3,7 +3,7 @@ package rules_test
 import (
 	"testing"
 
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/lint"
 	"github.com/verily-src/fsh-lint/rules"
 
@@ -15,12 +15,12 @@ func TestLintCodeSystemNameMatchesID(t *testing.T) {
 	sut := rules.CodeSystemNameMatchesIDRule{NameSuffix: "_CS"}
 	ruleName := "CodeSystemNameMatchesIDRule"
 
-	testLocation := &types.Location{
-		Start: &types.Position{
+	testLocation := &ast.Location{
+		Start: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 2,
 		},
-		End: &types.Position{
+		End: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 4,
 		},
@@ -28,7 +28,7 @@ func TestLintCodeSystemNameMatchesID(t *testing.T) {
 
 	tests := []struct {
 		name        string
-		codeSystems []*types.CodeSystem
+		codeSystems []*ast.CodeSystem
 		want        []*lint.Problem
 	}{
 		{
@@ -38,28 +38,28 @@ func TestLintCodeSystemNameMatchesID(t *testing.T) {
 		},
 		{
 			name: "name with _CS matches id",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("CodeSystem_CS", "code-system", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "name without _CS matches id",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("CodeSystem", "code-system", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "one word match",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("Example", "example", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "id different text",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("CodeSystemOne", "code-system-two", testLocation),
 			},
 			want: []*lint.Problem{
@@ -72,13 +72,12 @@ func TestLintCodeSystemNameMatchesID(t *testing.T) {
 						Want:      "code-system-one",
 						FieldName: "Code System ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "id incorrect case",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("ExampleCodeSystem", "Example-Code-System", testLocation),
 			},
 			want: []*lint.Problem{
@@ -91,13 +90,12 @@ func TestLintCodeSystemNameMatchesID(t *testing.T) {
 						Want:      "example-code-system",
 						FieldName: "Code System ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "id incorrect kebab style",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("ExampleCodeSystem", "examplecode-system", testLocation),
 			},
 			want: []*lint.Problem{
@@ -110,13 +108,12 @@ func TestLintCodeSystemNameMatchesID(t *testing.T) {
 						Want:      "example-code-system",
 						FieldName: "Code System ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "empty id",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("ExampleCodeSystem_CS", "", testLocation),
 			},
 			want: []*lint.Problem{
@@ -129,13 +126,12 @@ func TestLintCodeSystemNameMatchesID(t *testing.T) {
 						Want:      "example-code-system",
 						FieldName: "Code System ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "one matching, one non matching",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("CodeSystemOne_CS", "code-system-one", testLocation),
 				createTestCodeSystemWithNameAndID("CodeSystemOne_CS", "code-system-one-cs", testLocation),
 			},
@@ -149,13 +145,12 @@ func TestLintCodeSystemNameMatchesID(t *testing.T) {
 						Want:      "code-system-one",
 						FieldName: "Code System ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "two non matching",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("CodeSystemOne_CS", "code-system-three", testLocation),
 				createTestCodeSystemWithNameAndID("CodeSystemTwo_CS", "code-system-three", testLocation),
 			},
@@ -169,7 +164,6 @@ func TestLintCodeSystemNameMatchesID(t *testing.T) {
 						Want:      "code-system-one",
 						FieldName: "Code System ID",
 					},
-					IsFixable: true,
 				},
 				{
 					RuleID:   sut.ID(),
@@ -180,69 +174,68 @@ func TestLintCodeSystemNameMatchesID(t *testing.T) {
 						Want:      "code-system-two",
 						FieldName: "Code System ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "no hyphen after number",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("123Abc", "123abc", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no hyphen before number",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("Abc123", "abc123", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no hyphens around numbers",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("A123B", "a123b", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no hyphen numbers before or after",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("123Abc456", "123abc456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "one hyphen after none before",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("123Abc456", "123-abc456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "one hyphen before none after",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("123Abc456", "123abc-456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "hyphens before and after",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("123Abc456", "123-abc-456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "extra hyphens between numbers",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("1234abc", "1-2-34abc", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "double hyphen should not pass",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("12", "1--2", testLocation),
 			},
 			want: []*lint.Problem{
@@ -255,13 +248,12 @@ func TestLintCodeSystemNameMatchesID(t *testing.T) {
 						Want:      "12",
 						FieldName: "Code System ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "should not start with a hyphen",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("1Abc", "-1abc", testLocation),
 			},
 			want: []*lint.Problem{
@@ -274,13 +266,12 @@ func TestLintCodeSystemNameMatchesID(t *testing.T) {
 						Want:      "1-abc",
 						FieldName: "Code System ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "should not end with a hyphen",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndID("Abc1", "Abc1-", testLocation),
 			},
 			want: []*lint.Problem{
@@ -293,7 +284,6 @@ func TestLintCodeSystemNameMatchesID(t *testing.T) {
 						Want:      "abc-1",
 						FieldName: "Code System ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
@@ -301,8 +291,9 @@ func TestLintCodeSystemNameMatchesID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// The problems are not fixable without the data of the file.
 			fc := &lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
+				ParsedFSH: &ast.FSHDocument{
 					CodeSystems: tt.codeSystems},
 			}
 
@@ -317,13 +308,13 @@ func TestLintCodeSystemNameMatchesID(t *testing.T) {
 	}
 }
 
-func createTestCodeSystemWithNameAndID(name string, id string, testLocation *types.Location) *types.CodeSystem {
-	return &types.CodeSystem{
-		Name: &types.ParsedElement[string]{
+func createTestCodeSystemWithNameAndID(name string, id string, testLocation *ast.Location) *ast.CodeSystem {
+	return &ast.CodeSystem{
+		Name: &ast.ParsedElement[string]{
 			Value:    name,
 			Location: testLocation,
 		},
-		ID: &types.ParsedElement[string]{
+		ID: &ast.ParsedElement[string]{
 			Value:    id,
 			Location: testLocation,
 		},
@@ -5,10 +5,8 @@ import (
 	"strings"
 
 	"github.com/iancoleman/strcase"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/internal/match"
 	"github.com/verily-src/fsh-lint/lint"
 )
@@ -33,12 +31,41 @@ func (r *CodeSystemNameMatchesTitleRule) Message() string {
 	return fmt.Sprintf("Code system name (PascalCase) must match code system title in Title Case without the %s suffix.", r.NameSuffix)
 }
 
+// Metadata() returns the documentation metadata for this rule.
+func (*CodeSystemNameMatchesTitleRule) Metadata() *lint.Metadata {
+	return &lint.Metadata{
+		Category: lint.CategoryCodeSystem,
+		Description: "Code System name without the name suffix (e.g. `_CS`) in PascalCase must match Code System title in Title Case (space separated). This check is **case insensitive**.\n\n" +
+			"If there are numbers in the code system name, **any grouping with its adjacent alphabetic characters is acceptable**. In other words, the space is optional in the title when adjacent to a number. This flexibility applies only to numbers.\n\n" +
+			"If there are acronyms in the code system name, the acronym should be grouped as one word. Note, that the first letter of a word following an acronym should be capitalized in the PascalCase name.\n\n" +
+			"Note, the \"Want\" value that is generated, is one of the correct configurations where each group of numbers is grouped as their own separate word. This is the behavior of [strcase.ToDelimited](https://pkg.go.dev/github.com/iancoleman/strcase#ToDelimited).\n\n" +
+			"For example:\n\n" +
+			"- Code system name `ExampleOne_CS` **matches** title `Example One` and `example one`, but **does not match** `Example One CS` or `ExampleOne`.\n" +
+			"- Code system name `A123Example_CS` **matches** title `A 123 Example`, `A123example`, `A1 23example`, and several others.\n" +
+			"- Code system name `ABCExample_CS` **matches** title `ABC Example`, but **does not match** `A B C Example` or `Abce Xample`.",
+		Examples: []lint.Example{
+			{
+				FSH: `CodeSystem: ExampleOne_CS
+Title: "Example One"`,
+				Good: true,
+			},
+			{
+				FSH: `CodeSystem: ExampleOne_CS
+Title: "ExampleOne"`,
+				Good: false,
+			},
+		},
+		EntityKinds: []lint.EntityKind{lint.EntityCodeSystem},
+		Fixable:     true,
+	}
+}
+
 // Validate returns a *lint.Problem for each code system name without the NameSuffix
 // that does not match its corresponding title.
 func (r *CodeSystemNameMatchesTitleRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
 	var problems []*lint.Problem
 	for _, cs := range fc.ParsedFSH.CodeSystems {
-		p, err := codeSystemNameMatchesTitleViolation(cs, r.NameSuffix, r.ID(), r.Message())
+		p, err := codeSystemNameMatchesTitleViolation(fc.Data, cs, r.NameSuffix, r.ID(), r.Message())
 		if err != nil {
 			return nil, err
 		}
@@ -51,19 +78,18 @@ func (r *CodeSystemNameMatchesTitleRule) Validate(fc *lint.FileContext) ([]*lint
 
 // codeSystemNameMatchesTitleViolation returns nil if the code system name without the NameSuffix matches title in
 // Title Case, and a *lint.Problem otherwise.
-func codeSystemNameMatchesTitleViolation(cs *types.CodeSystem, nameSuffix string, id string, msg string) (*lint.Problem, error) {
+func codeSystemNameMatchesTitleViolation(data []byte, cs *ast.CodeSystem, nameSuffix string, id string, msg string) (*lint.Problem, error) {
 	trimmedName := strings.TrimSuffix(cs.Name.Value, nameSuffix)
 	spaceSeparatedName := strcase.ToDelimited(trimmedName, ' ')
 
 	if !match.IsNameMatchWithTitle(spaceSeparatedName, cs.Title.Value, true) {
-		caser := cases.Title(language.AmericanEnglish)
 		diff := &lint.Diff{
 			Got:       cs.Title.Value,
-			Want:      caser.String(spaceSeparatedName),
+			Want:      match.NameToTitle(trimmedName),
 			FieldName: "Code System Title",
 		}
 
-		return lint.NewProblem(id, msg, cs.Title.Location, diff, true)
+		return replaceValueProblem(data, id, msg, cs.Title, diff)
 	}
 	return nil, nil
 }
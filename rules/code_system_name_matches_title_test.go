@@ -5,7 +5,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/lint"
 	"github.com/verily-src/fsh-lint/rules"
 )
@@ -14,12 +14,12 @@ func TestLintCodeSystemNameMatchesTitle(t *testing.T) {
 	sut := rules.CodeSystemNameMatchesTitleRule{NameSuffix: "_CS"}
 	ruleName := "CodeSystemNameMatchesTitleRule"
 
-	testLocation := &types.Location{
-		Start: &types.Position{
+	testLocation := &ast.Location{
+		Start: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 2,
 		},
-		End: &types.Position{
+		End: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 4,
 		},
@@ -27,38 +27,38 @@ func TestLintCodeSystemNameMatchesTitle(t *testing.T) {
 
 	tests := []struct {
 		name        string
-		codeSystems []*types.CodeSystem
+		codeSystems []*ast.CodeSystem
 		want        []*lint.Problem
 	}{
 		{
 			name:        "no code systems",
-			codeSystems: []*types.CodeSystem{},
+			codeSystems: []*ast.CodeSystem{},
 			want:        nil,
 		},
 		{
 			name: "name matches title with _CS",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("ExampleTest_CS", "Example Test", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "name matches title without _CS",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("ExampleTest", "Example Test", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "name matches title with acronym",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("ExampleTestABC_CS", "Example Test ABC", nil),
 			},
 			want: nil,
 		},
 		{
 			name: "title is the same but adds '_CS' at the end",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("ExampleName_CS", "Example Name CS", testLocation),
 			},
 			want: []*lint.Problem{
@@ -71,13 +71,12 @@ func TestLintCodeSystemNameMatchesTitle(t *testing.T) {
 						Want:      "Example Name",
 						FieldName: "Code System Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "incorrect title spacing",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("ExampleName", "Example   Name", testLocation),
 			},
 			want: []*lint.Problem{
@@ -90,13 +89,12 @@ func TestLintCodeSystemNameMatchesTitle(t *testing.T) {
 						Want:      "Example Name",
 						FieldName: "Code System Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "empty title",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("ExampleName", "", testLocation),
 			},
 			want: []*lint.Problem{
@@ -109,13 +107,12 @@ func TestLintCodeSystemNameMatchesTitle(t *testing.T) {
 						Want:      "Example Name",
 						FieldName: "Code System Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "one matching, one non matching",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("ExampleOne", "Example Two", testLocation),
 				createTestCodeSystemWithNameAndTitle("ExampleTwo", "Example Two", testLocation),
 			},
@@ -129,13 +126,12 @@ func TestLintCodeSystemNameMatchesTitle(t *testing.T) {
 						Want:      "Example One",
 						FieldName: "Code System Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "two non matching",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("ExampleOne", "Example Three", testLocation),
 				createTestCodeSystemWithNameAndTitle("ExampleTwo", "Example Three", testLocation),
 			},
@@ -149,7 +145,6 @@ func TestLintCodeSystemNameMatchesTitle(t *testing.T) {
 						Want:      "Example One",
 						FieldName: "Code System Title",
 					},
-					IsFixable: true,
 				},
 				{
 					RuleID:   sut.ID(),
@@ -160,76 +155,75 @@ func TestLintCodeSystemNameMatchesTitle(t *testing.T) {
 						Want:      "Example Two",
 						FieldName: "Code System Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "different case ignored",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("CodeSystemName", "Code system nAME", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no space after number",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("123Abc", "123Abc", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no space before number",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("Abc123", "Abc123", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no spaces around numbers",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("A123B", "A123b", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no space numbers before or after",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("123Abc456", "123abc456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "one space after none before",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("123Abc456", "123 abc456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "one space before none after",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("123Abc456", "123abc 456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "spaces before and after",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("123Abc456", "123 ABC 456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "extra spaces between numbers",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("1234abc", "1 2 34ABC", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "double space should not pass",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("12", "1  2", testLocation),
 			},
 			want: []*lint.Problem{
@@ -242,13 +236,12 @@ func TestLintCodeSystemNameMatchesTitle(t *testing.T) {
 						Want:      "12",
 						FieldName: "Code System Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "should not start with a space",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("1Abc", " 1abc", testLocation),
 			},
 			want: []*lint.Problem{
@@ -261,13 +254,12 @@ func TestLintCodeSystemNameMatchesTitle(t *testing.T) {
 						Want:      "1 Abc",
 						FieldName: "Code System Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "should not end with a space",
-			codeSystems: []*types.CodeSystem{
+			codeSystems: []*ast.CodeSystem{
 				createTestCodeSystemWithNameAndTitle("Abc1", "Abc1 ", testLocation),
 			},
 			want: []*lint.Problem{
@@ -280,7 +272,6 @@ func TestLintCodeSystemNameMatchesTitle(t *testing.T) {
 						Want:      "Abc 1",
 						FieldName: "Code System Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
@@ -288,8 +279,9 @@ func TestLintCodeSystemNameMatchesTitle(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// The problems are not fixable without the data of the file.
 			fileContext := lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
+				ParsedFSH: &ast.FSHDocument{
 					CodeSystems: tt.codeSystems,
 				},
 			}
@@ -304,13 +296,13 @@ func TestLintCodeSystemNameMatchesTitle(t *testing.T) {
 	}
 }
 
-func createTestCodeSystemWithNameAndTitle(name string, title string, testLocation *types.Location) *types.CodeSystem {
-	return &types.CodeSystem{
-		Name: &types.ParsedElement[string]{
+func createTestCodeSystemWithNameAndTitle(name string, title string, testLocation *ast.Location) *ast.CodeSystem {
+	return &ast.CodeSystem{
+		Name: &ast.ParsedElement[string]{
 			Value:    name,
 			Location: testLocation,
 		},
-		Title: &types.ParsedElement[string]{
+		Title: &ast.ParsedElement[string]{
 			Value:    title,
 			Location: testLocation,
 		},
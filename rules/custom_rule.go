@@ -0,0 +1,311 @@
+package rules
+
+import (
+	"fmt"
+	"reflect"
+	"regexp"
+	"strings"
+
+	"github.com/verily-src/fsh-lint/ast"
+	"github.com/verily-src/fsh-lint/internal/config"
+	"github.com/verily-src/fsh-lint/internal/query"
+	"github.com/verily-src/fsh-lint/lint"
+)
+
+// customRuleEntities are the fields of ast.FSHDocument holding the entities of
+// each kind that custom rules can check.
+var customRuleEntities = map[lint.EntityKind]string{
+	lint.EntityCodeSystem: "CodeSystems",
+	lint.EntityExtension:  "Extensions",
+	lint.EntityInstance:   "Instances",
+	lint.EntityProfile:    "Profiles",
+	lint.EntityValueSet:   "ValueSets",
+}
+
+// customRuleCategories are the categories custom rules are listed under.
+var customRuleCategories = map[lint.EntityKind]lint.Category{
+	lint.EntityCodeSystem: lint.CategoryCodeSystem,
+	lint.EntityExtension:  lint.CategoryExtension,
+	lint.EntityInstance:   lint.CategoryInstance,
+	lint.EntityProfile:    lint.CategoryProfile,
+	lint.EntityValueSet:   lint.CategoryValueSet,
+}
+
+// caretRulesFields are the fields of the entities that have caret value rules.
+var caretRulesFields = map[lint.EntityKind][]string{
+	lint.EntityExtension: {"ExtensionRules", "CaretValueRules"},
+	lint.EntityProfile:   {"ProfileRules", "CaretValueRules"},
+	lint.EntityValueSet:  {"ValueSetRules", "CaretValueRules"},
+}
+
+// CustomRule is a rule defined in the configuration, which checks that a field
+// of every entity of a kind is present, matches a pattern, or equals a value,
+// or reports every element selected by a query.
+type CustomRule struct {
+	def     *config.CustomRule
+	entity  lint.EntityKind
+	pattern *regexp.Regexp
+	query   *query.Query
+	docsURL string
+
+	// fieldPath is the path of Go field names from the entity to the field. For
+	// caret paths, it is the path to the caret value rules of the entity.
+	fieldPath []string
+
+	// caretElement is the element of the caret value rules whose values are
+	// checked, or empty if the field is not a caret path.
+	caretElement string
+}
+
+// CustomRules returns the rules defined in the configuration. Returns an error
+// if a rule checks an unknown entity kind or field.
+func CustomRules(cfg *config.Config) ([]lint.Rule, error) {
+	if cfg == nil {
+		return nil, nil
+	}
+	var result []lint.Rule
+	for _, def := range cfg.CustomRules {
+		rule, err := NewCustomRule(def)
+		if err != nil {
+			return nil, err
+		}
+		if rule.docsURL == "" {
+			rule.docsURL = cfg.Path
+		}
+		result = append(result, rule)
+	}
+	return result, nil
+}
+
+// NewCustomRule returns the rule defined by def. Returns an error if def checks
+// an unknown entity kind or field, or if its query is invalid.
+func NewCustomRule(def *config.CustomRule) (*CustomRule, error) {
+	r := &CustomRule{def: def, docsURL: def.DocsURL}
+	if def.Query != "" {
+		q, err := query.Parse(def.Query)
+		if err != nil {
+			return nil, fmt.Errorf("custom rule %s: invalid query: %w", def.ID, err)
+		}
+		r.query = q
+		for kind, field := range customRuleEntities {
+			if field == q.Root() {
+				r.entity = kind
+			}
+		}
+		return r, nil
+	}
+
+	for kind := range customRuleEntities {
+		if strings.EqualFold(string(kind), def.Entity) {
+			r.entity = kind
+		}
+	}
+	if r.entity == "" {
+		return nil, fmt.Errorf("custom rule %s: unknown entity %q, must be one of: %v", def.ID, def.Entity, lint.EntityKinds)
+	}
+
+	var err error
+	if r.pattern, err = regexp.Compile(def.Pattern); err != nil {
+		return nil, fmt.Errorf("custom rule %s: invalid pattern: %w", def.ID, err)
+	}
+
+	if element, ok := strings.CutPrefix(def.Field, "^"); ok {
+		fields, ok := caretRulesFields[r.entity]
+		if !ok || element == "" {
+			return nil, fmt.Errorf("custom rule %s: %s entities do not have caret path %q", def.ID, r.entity, def.Field)
+		}
+		r.fieldPath, r.caretElement = fields, element
+		return r, nil
+	}
+
+	entityType := reflect.TypeFor[ast.FSHDocument]()
+	entityField, _ := entityType.FieldByName(customRuleEntities[r.entity])
+	if r.fieldPath, err = resolveJSONPath(entityField.Type, strings.Split(def.Field, ".")); err != nil {
+		return nil, fmt.Errorf("custom rule %s: invalid field %q of %s: %w", def.ID, def.Field, r.entity, err)
+	}
+	return r, nil
+}
+
+// ID returns the rule ID.
+func (r *CustomRule) ID() string {
+	return r.def.ID
+}
+
+// Message returns the configured message, or a message built from the checks.
+func (r *CustomRule) Message() string {
+	if r.def.Message != "" {
+		return r.def.Message
+	}
+	if r.query != nil {
+		return fmt.Sprintf("Element matches the query %q.", r.def.Query)
+	}
+	var checks []string
+	if r.def.Required {
+		checks = append(checks, "be set")
+	}
+	if r.def.Pattern != "" {
+		checks = append(checks, fmt.Sprintf("match %q", r.def.Pattern))
+	}
+	if r.def.Equals != "" {
+		checks = append(checks, fmt.Sprintf("equal %q", r.def.Equals))
+	}
+	return fmt.Sprintf("%s %s must %s.", r.entity, r.def.Field, strings.Join(checks, " and "))
+}
+
+// Metadata returns the documentation metadata for this rule. Rules whose query
+// does not select entities of a single kind are special rules.
+func (r *CustomRule) Metadata() *lint.Metadata {
+	md := &lint.Metadata{
+		Category:    lint.CategorySpecial,
+		Description: r.Message(),
+		Severity:    r.def.Severity.Severity(),
+		DocsURL:     r.docsURL,
+	}
+	if r.entity != "" {
+		md.Category = customRuleCategories[r.entity]
+		md.EntityKinds = []lint.EntityKind{r.entity}
+	}
+	return md
+}
+
+// String returns the definition of the rule, so that changing it invalidates
+// cached problems.
+func (r *CustomRule) String() string {
+	return fmt.Sprintf("%+v", *r.def)
+}
+
+// Validate returns a *lint.Problem for each entity of the kind that does not
+// set a required field, and for each value of the field that does not match
+// the pattern or is not equal to the expected value. For a query, it returns a
+// *lint.Problem for each selected element.
+func (r *CustomRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
+	var problems []*lint.Problem
+	if r.query != nil {
+		for _, result := range r.query.Eval(fc.ParsedFSH) {
+			p, err := lint.NewProblem(r.ID(), r.Message(), result.Location, nil, false)
+			if err != nil {
+				return nil, err
+			}
+			problems = append(problems, p)
+		}
+		return problems, nil
+	}
+
+	entities := reflect.ValueOf(fc.ParsedFSH).Elem().FieldByName(customRuleEntities[r.entity])
+	for i := range entities.Len() {
+		entity := entities.Index(i)
+		values := r.values(entity)
+
+		if r.def.Required && len(values) == 0 {
+			var location *ast.Location
+			if name, ok := entity.Elem().FieldByName("Name").Interface().(*ast.ParsedElement[string]); ok && name != nil {
+				location = name.Location
+			}
+			p, err := lint.NewProblem(r.ID(), r.Message(), location, nil, false)
+			if err != nil {
+				return nil, err
+			}
+			problems = append(problems, p)
+		}
+
+		for _, value := range values {
+			if r.pattern.MatchString(value.Value) && (r.def.Equals == "" || value.Value == r.def.Equals) {
+				continue
+			}
+			p, err := lint.NewProblem(r.ID(), r.Message(), value.Location, nil, false)
+			if err != nil {
+				return nil, err
+			}
+			problems = append(problems, p)
+		}
+	}
+	return problems, nil
+}
+
+// values returns the non-empty values of the field of the entity.
+func (r *CustomRule) values(entity reflect.Value) []*ast.ParsedElement[string] {
+	var result []*ast.ParsedElement[string]
+	for _, v := range collectValues(entity, r.fieldPath) {
+		if r.caretElement == "" {
+			if element, ok := v.Interface().(*ast.ParsedElement[string]); ok && !isNilOrEmpty(v) {
+				result = append(result, element)
+			}
+			continue
+		}
+		rule, ok := v.Interface().(*ast.CaretValueRule)
+		if !ok || rule.Element == nil || rule.Element.Value != r.caretElement || !isNilOrEmpty(reflect.ValueOf(rule.ElementInProfile)) {
+			continue
+		}
+		if !isNilOrEmpty(reflect.ValueOf(rule.Value)) {
+			result = append(result, rule.Value)
+		}
+	}
+	return result
+}
+
+// collectValues returns the values at the path of Go field names from val,
+// following pointers and the elements of slices. Nil pointers are skipped.
+func collectValues(val reflect.Value, path []string) []reflect.Value {
+	switch val.Kind() {
+	case reflect.Ptr:
+		if val.IsNil() {
+			return nil
+		}
+		if len(path) == 0 {
+			return []reflect.Value{val}
+		}
+		return collectValues(val.Elem(), path)
+	case reflect.Slice:
+		var result []reflect.Value
+		for i := range val.Len() {
+			result = append(result, collectValues(val.Index(i), path)...)
+		}
+		return result
+	case reflect.Struct:
+		if len(path) == 0 {
+			return nil
+		}
+		return collectValues(val.FieldByName(path[0]), path[1:])
+	default:
+		return nil
+	}
+}
+
+// resolveJSONPath returns the Go field names of the path of JSON field names in
+// the type t, which must end at a string element.
+func resolveJSONPath(t reflect.Type, path []string) ([]string, error) {
+	var result []string
+	for _, name := range path {
+		for t.Kind() == reflect.Ptr || t.Kind() == reflect.Slice {
+			t = t.Elem()
+		}
+		if t.Kind() != reflect.Struct || t == parsedElementStringType.Elem() {
+			return nil, fmt.Errorf("%w: %q has no fields", ErrNotRecursable, name)
+		}
+		field, ok := jsonField(t, name)
+		if !ok {
+			return nil, fmt.Errorf("%w: %q is not a field of %s", ErrInvalidField, name, t.Name())
+		}
+		result = append(result, field.Name)
+		t = field.Type
+	}
+	if t.Kind() == reflect.Slice {
+		t = t.Elem()
+	}
+	if t != parsedElementStringType {
+		return nil, fmt.Errorf("%w: the field is not a string element", ErrNotSupported)
+	}
+	return result, nil
+}
+
+// jsonField returns the field of the struct type t with the given JSON name.
+func jsonField(t reflect.Type, name string) (reflect.StructField, bool) {
+	for i := range t.NumField() {
+		field := t.Field(i)
+		tag, _, _ := strings.Cut(field.Tag.Get("json"), ",")
+		if tag == name {
+			return field, true
+		}
+	}
+	return reflect.StructField{}, false
+}
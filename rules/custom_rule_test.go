@@ -0,0 +1,138 @@
+package rules_test
+
+import (
+	"fmt"
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/config"
+	"github.com/verily-src/fsh-lint/internal/fsh"
+	"github.com/verily-src/fsh-lint/lint"
+	"github.com/verily-src/fsh-lint/rules"
+)
+
+func TestCustomRule(t *testing.T) {
+	const data = `Profile: VerilyPatient
+Parent: Patient
+Title: "Verily Patient"
+* ^url = "https://verily.com/fhir/StructureDefinition/verily-patient"
+* name ^short = "Name"
+
+Profile: OtherPatient
+Parent: Patient
+* ^url = "http://example.org/other-patient"
+`
+	tests := []struct {
+		name string
+		rule *config.CustomRule
+		want []string
+	}{
+		{
+			name: "pattern",
+			rule: &config.CustomRule{ID: "verily-prefix", Entity: "Profile", Field: "name", Pattern: "^Verily"},
+			want: []string{"7: Profile name must match \"^Verily\"."},
+		},
+		{
+			name: "required",
+			rule: &config.CustomRule{ID: "title-required", Entity: "profile", Field: "title", Required: true, Message: "Profiles need a title."},
+			want: []string{"7: Profiles need a title."},
+		},
+		{
+			name: "equals",
+			rule: &config.CustomRule{ID: "parent-patient", Entity: "Profile", Field: "parent", Equals: "Patient"},
+		},
+		{
+			name: "caret path",
+			rule: &config.CustomRule{ID: "verily-url", Entity: "Profile", Field: "^url", Required: true, Pattern: "^https://verily\\.com/"},
+			want: []string{"9: Profile ^url must be set and match \"^https://verily\\\\.com/\"."},
+		},
+		{
+			name: "caret path of an element is not checked",
+			rule: &config.CustomRule{ID: "short-required", Entity: "Profile", Field: "^short", Required: true},
+			want: []string{"1: Profile ^short must be set.", "7: Profile ^short must be set."},
+		},
+		{
+			name: "nested field",
+			rule: &config.CustomRule{ID: "caret-values", Entity: "Profile", Field: "profileRules.caretValueRules.element", Pattern: "^(url|short)$"},
+		},
+		{
+			name: "query",
+			rule: &config.CustomRule{ID: "untitled-with-url", Query: `Profiles[Title == nil && ProfileRules.CaretValueRules.Element == "url"]`, Message: "Profiles with a URL need a title."},
+			want: []string{"7: Profiles with a URL need a title."},
+		},
+		{
+			name: "no entities of the kind",
+			rule: &config.CustomRule{ID: "value-set-title", Entity: "ValueSet", Field: "title", Required: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			parsedFSH, err := fsh.Parse(data)
+			if err != nil {
+				t.Fatalf("error parsing fsh: %s", err)
+			}
+
+			rule, err := rules.NewCustomRule(tt.rule)
+			if err != nil {
+				t.Fatalf("NewCustomRule() error: %v", err)
+			}
+			problems, err := rule.Validate(&lint.FileContext{ParsedFSH: parsedFSH})
+			if err != nil {
+				t.Fatalf("Validate() error: %v", err)
+			}
+
+			var got []string
+			for _, p := range problems {
+				got = append(got, fmt.Sprintf("%d: %s", p.StartPosition().LineNumber, p.Message))
+			}
+			if diff := cmp.Diff(got, tt.want); diff != "" {
+				t.Errorf("Validate() mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestNewCustomRule_Invalid(t *testing.T) {
+	tests := []struct {
+		name string
+		rule *config.CustomRule
+	}{
+		{
+			name: "unknown entity",
+			rule: &config.CustomRule{ID: "x", Entity: "Mapping", Field: "name", Required: true},
+		},
+		{
+			name: "unknown field",
+			rule: &config.CustomRule{ID: "x", Entity: "Profile", Field: "version", Required: true},
+		},
+		{
+			name: "Go field name",
+			rule: &config.CustomRule{ID: "x", Entity: "Profile", Field: "Name", Required: true},
+		},
+		{
+			name: "field is not a string",
+			rule: &config.CustomRule{ID: "x", Entity: "Profile", Field: "profileRules", Required: true},
+		},
+		{
+			name: "field of a string",
+			rule: &config.CustomRule{ID: "x", Entity: "Profile", Field: "name.value", Required: true},
+		},
+		{
+			name: "invalid query",
+			rule: &config.CustomRule{ID: "x", Query: "Profiles[Version == nil]"},
+		},
+		{
+			name: "caret path of an entity without caret rules",
+			rule: &config.CustomRule{ID: "x", Entity: "CodeSystem", Field: "^url", Required: true},
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			if _, err := rules.NewCustomRule(tt.rule); err == nil {
+				t.Errorf("NewCustomRule(%+v) returned no error", tt.rule)
+			}
+		})
+	}
+}
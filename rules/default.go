@@ -0,0 +1,48 @@
+package rules
+
+import "github.com/verily-src/fsh-lint/lint"
+
+// Required returns the rules that check that the fields the other rules rely on
+// are present. They are the required rules of the default linter.
+func Required() []lint.Rule {
+	return []lint.Rule{
+		&RequiredFieldPresentRule{FieldPath: "Profiles.Name", FieldName: "Profile Name"},
+		&RequiredFieldPresentRule{FieldPath: "Profiles.ID", FieldName: "Profile ID"},
+		&RequiredFieldPresentRule{FieldPath: "Profiles.Title", FieldName: "Profile Title"},
+		&ProfileAssignmentPresentRule{Element: "status", FixValue: "#draft"},
+		&ProfileAssignmentPresentRule{
+			Element:           "abstract",
+			AssignmentExample: "* ^abstract = true or * ^abstract = false",
+			FixValue:          "false",
+		},
+
+		&RequiredFieldPresentRule{FieldPath: "ValueSets.Name", FieldName: "Value Set Name"},
+		&RequiredFieldPresentRule{FieldPath: "ValueSets.ID", FieldName: "Value Set ID"},
+		&RequiredFieldPresentRule{FieldPath: "ValueSets.Title", FieldName: "Value Set Title"},
+
+		&RequiredFieldPresentRule{FieldPath: "CodeSystems.Name", FieldName: "Code System Name"},
+		&RequiredFieldPresentRule{FieldPath: "CodeSystems.ID", FieldName: "Code System ID"},
+		&RequiredFieldPresentRule{FieldPath: "CodeSystems.Title", FieldName: "Code System Title"},
+	}
+}
+
+// Default returns the rules the default linter runs after the required rules.
+func Default() []lint.Rule {
+	return []lint.Rule{
+		&ProfileNameFormatRule{},
+		&ProfileNameMatchesFilenameRule{},
+		&ProfileNameMatchesIDRule{},
+		&ProfileNameMatchesTitleRule{},
+
+		&ValueSetNameMatchesFilenameRule{},
+		&ValueSetNameMatchesIDRule{},
+		&ValueSetNameMatchesTitleRule{},
+
+		&CodeSystemNameMatchesFilenameRule{},
+		&CodeSystemNameMatchesIDRule{},
+		&CodeSystemNameMatchesTitleRule{},
+
+		&EntityMetadataOrderRule{},
+		&OneEntityPerFileRule{},
+	}
+}
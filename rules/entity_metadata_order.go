@@ -0,0 +1,272 @@
+package rules
+
+import (
+	"bytes"
+	"cmp"
+	"fmt"
+	"slices"
+	"strings"
+
+	"github.com/verily-src/fsh-lint/ast"
+	"github.com/verily-src/fsh-lint/internal/config"
+	"github.com/verily-src/fsh-lint/internal/fsh"
+	"github.com/verily-src/fsh-lint/lint"
+)
+
+// EntityMetadataOrderRule checks that the metadata of each entity come in a
+// fixed order, and that the caret value rules of the entity, such as
+// * ^status = #draft, come before its other rules.
+type EntityMetadataOrderRule struct {
+	// Keywords is the order of the metadata keywords, such as Parent, Id, Title,
+	// and Description, of every kind of entity. Metadata with other keywords
+	// come after them, in any order. Optional, the canonical order of
+	// metadataOrder is used for the entities that have one when empty.
+	Keywords []string
+
+	// IgnoreRuleOrder disables the check that caret value rules come before
+	// the other rules. Optional.
+	IgnoreRuleOrder bool
+}
+
+// Configure sets the order of the rule from the configuration, or resets it to
+// the canonical order if the configuration is nil.
+func (r *EntityMetadataOrderRule) Configure(cfg *config.MetadataOrder) {
+	*r = EntityMetadataOrderRule{}
+	if cfg != nil {
+		r.Keywords = cfg.Keywords
+		r.IgnoreRuleOrder = cfg.IgnoreRuleOrder
+	}
+}
+
+// String returns the configured order, which is part of the cache key of the
+// rule's problems.
+func (r *EntityMetadataOrderRule) String() string {
+	return fmt.Sprintf("keywords=%s ignoreRuleOrder=%t", strings.Join(r.Keywords, ","), r.IgnoreRuleOrder)
+}
+
+// ID() returns the rule ID.
+func (*EntityMetadataOrderRule) ID() string {
+	return "entity-metadata-order"
+}
+
+// Message() returns the appropriate lint error message for this rule.
+func (*EntityMetadataOrderRule) Message() string {
+	return "The metadata of entities must come in the canonical order, and their caret value rules before their other rules."
+}
+
+// Metadata() returns the documentation metadata for this rule.
+func (*EntityMetadataOrderRule) Metadata() *lint.Metadata {
+	return &lint.Metadata{
+		Category: lint.CategorySpecial,
+		Description: "The metadata of each entity should come in the same order, right after its declaration, so that entities are easy to review. By default, the order is:\n\n" +
+			"- Profiles and extensions: `Parent`, `Id`, `Title`, `Description`, and for extensions, `Context`\n" +
+			"- Instances: `InstanceOf`, `Title`, `Description`, `Usage`\n" +
+			"- Value sets and code systems: `Id`, `Title`, `Description`\n\n" +
+			"The caret value rules of the entity itself, such as `* ^status = #draft`, should come before its other rules. A rule and the indented rules after it are moved together.\n\n" +
+			"Problems are located at each metadata or caret value rule that comes after one it should precede. They are fixed by reordering the lines of the entity, keeping the comments before each line with it. The fix is unsafe if it moves a caret value rule before an `insert` rule, since the inserted rule set may assign the same value, and the last assignment wins.",
+		Examples: []lint.Example{
+			{
+				FSH: `Profile: Example
+Parent: Patient
+Id: example
+Title: "Example Profile"
+* ^status = #draft
+* name 1..1`,
+				Good: true,
+			},
+			{
+				Description: "A profile whose ID comes before its parent, and whose caret value rule comes after an element rule:",
+				FSH: `Profile: Example
+Id: example
+Parent: Patient
+Title: "Example Profile"
+* name 1..1
+* ^status = #draft`,
+				Good: false,
+			},
+		},
+		EntityKinds: lint.EntityKinds,
+		Fixable:     true,
+		UnsafeFix:   true,
+	}
+}
+
+// orderedUnit is a metadata, or a rule and the indented rules after it, that is
+// reordered as a whole.
+type orderedUnit struct {
+	nodes []*fsh.Node
+	rank  int
+
+	// name describes the unit in messages, such as "Id", "caret rule ^status",
+	// or "the other rules".
+	name string
+
+	// insert is true if the unit has an insert rule, whose rule set may assign
+	// the same values as the caret value rules after it.
+	insert bool
+}
+
+// Validate returns a *lint.Problem for each metadata or caret value rule that
+// comes after one it should precede.
+func (r *EntityMetadataOrderRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
+	tree := fsh.NewSyntaxTree(fsh.Tokens(string(fc.Data)))
+
+	var problems []*lint.Problem
+	for _, entity := range tree.Nodes {
+		if entity.Kind != fsh.NodeEntity || len(entity.Children) == 0 {
+			continue
+		}
+		units := r.units(entity)
+		var edits []*lint.TextEdit
+		previous := units[0]
+		for _, unit := range units[1:] {
+			if unit.rank >= previous.rank {
+				previous = unit
+				continue
+			}
+			if edits == nil {
+				edits = []*lint.TextEdit{reorderEdit(fc.Data, entity, units)}
+			}
+			first := unit.nodes[0].Tokens
+			start := first[0].Start
+			end := first[len(first)-1].End
+			location := &ast.Location{Start: lint.Position(fc.Data, start), End: lint.Position(fc.Data, end)}
+			message := fmt.Sprintf("%s should come before %s in %s %s.", upperFirst(unit.name), previous.name, entity.Keyword(), entity.Name())
+			p, err := lint.NewProblemWithEdits(r.ID(), message, location, nil, edits)
+			if err != nil {
+				return nil, err
+			}
+			p.UnsafeFix = crossesInsert(units)
+			problems = append(problems, p)
+		}
+	}
+	return problems, nil
+}
+
+// units returns the units of the metadata and rules of the entity, ranked by
+// the order they should come in.
+func (r *EntityMetadataOrderRule) units(entity *fsh.Node) []*orderedUnit {
+	keywords := r.Keywords
+	if len(keywords) == 0 {
+		for _, field := range metadataOrder[entity.Keyword()+"s"] {
+			if keyword, ok := metadataKeywords[field]; ok {
+				keywords = append(keywords, keyword)
+			}
+		}
+	}
+	caretRank, ruleRank := len(keywords)+1, len(keywords)+2
+	if r.IgnoreRuleOrder {
+		ruleRank = caretRank
+	}
+
+	var units []*orderedUnit
+	for _, node := range entity.Children {
+		switch {
+		case node.Kind == fsh.NodeMetadata:
+			rank := slices.Index(keywords, node.Keyword())
+			if rank < 0 {
+				rank = len(keywords)
+			}
+			units = append(units, &orderedUnit{nodes: []*fsh.Node{node}, rank: rank, name: node.Keyword()})
+		case len(units) > 0 && indentation(node) != "" && units[len(units)-1].rank >= caretRank:
+			// Indented rules belong to the rule before them.
+			last := units[len(units)-1]
+			last.nodes = append(last.nodes, node)
+			last.insert = last.insert || isInsertRule(node)
+		case firstToken(node.Tokens[1:]).Kind == "CARET_SEQUENCE":
+			name := "caret rule " + firstToken(node.Tokens[1:]).Text
+			units = append(units, &orderedUnit{nodes: []*fsh.Node{node}, rank: caretRank, name: name})
+		default:
+			units = append(units, &orderedUnit{nodes: []*fsh.Node{node}, rank: ruleRank, name: "the other rules", insert: isInsertRule(node)})
+		}
+	}
+	return units
+}
+
+// crossesInsert returns true if sorting the units by rank moves a unit before
+// an insert rule, which changes the meaning of the entity if the inserted rule
+// set assigns a value that the unit assigns as well, since the last assignment
+// wins.
+func crossesInsert(units []*orderedUnit) bool {
+	for i, unit := range units {
+		if !unit.insert {
+			continue
+		}
+		for _, later := range units[i+1:] {
+			if later.rank < unit.rank {
+				return true
+			}
+		}
+	}
+	return false
+}
+
+// isInsertRule returns true if the rule inserts a rule set, such as
+// * insert Metadata or * name insert Required.
+func isInsertRule(node *fsh.Node) bool {
+	return slices.ContainsFunc(node.Tokens, func(t *fsh.Token) bool {
+		return t.Kind == "KW_INSERT"
+	})
+}
+
+// reorderEdit returns the edit that sorts the units of the entity by rank,
+// keeping the order of units of the same rank.
+func reorderEdit(data []byte, entity *fsh.Node, units []*orderedUnit) *lint.TextEdit {
+	newline := "\n"
+	if bytes.Contains(data, []byte("\r\n")) {
+		newline = "\r\n"
+	}
+	sorted := slices.Clone(units)
+	slices.SortStableFunc(sorted, func(a, b *orderedUnit) int {
+		return cmp.Compare(a.rank, b.rank)
+	})
+
+	var b strings.Builder
+	for i, unit := range sorted {
+		var text strings.Builder
+		for _, node := range unit.nodes {
+			text.WriteString(node.String())
+		}
+		b.WriteString(text.String())
+		// The last line of the entity may have no line ending.
+		if i < len(sorted)-1 && !strings.HasSuffix(text.String(), "\n") {
+			b.WriteString(newline)
+		}
+	}
+	return &lint.TextEdit{
+		Start:   entity.Children[0].Start(),
+		End:     entity.End(),
+		NewText: b.String(),
+	}
+}
+
+// indentation returns the whitespace before the "*" of a rule.
+func indentation(node *fsh.Node) string {
+	var leading strings.Builder
+	for _, t := range node.Leading {
+		leading.WriteString(t.Text)
+	}
+	text := leading.String()
+	return text[strings.LastIndexAny(text, "\r\n")+1:]
+}
+
+// firstToken returns the first of the tokens that is not whitespace or a
+// comment, or an empty token if there is none.
+func firstToken(tokens []*fsh.Token) *fsh.Token {
+	for _, t := range tokens {
+		switch t.Kind {
+		case fsh.TokenWhitespace, fsh.TokenNewline, fsh.TokenLineComment, fsh.TokenBlockComment:
+		default:
+			return t
+		}
+	}
+	return &fsh.Token{}
+}
+
+// upperFirst returns s with its first letter in upper case.
+func upperFirst(s string) string {
+	if s == "" {
+		return s
+	}
+	return strings.ToUpper(s[:1]) + s[1:]
+}
@@ -0,0 +1,144 @@
+package rules_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic/diagnostictest"
+	"github.com/verily-src/fsh-lint/lint"
+	"github.com/verily-src/fsh-lint/rules"
+)
+
+func TestEntityMetadataOrder(t *testing.T) {
+	type problem struct {
+		Message string
+		Line    int
+	}
+	tests := []struct {
+		name string
+		rule *rules.EntityMetadataOrderRule
+		fsh  string
+		want []problem
+	}{
+		{
+			name: "ordered",
+			rule: &rules.EntityMetadataOrderRule{},
+			fsh:  "Alias: $a = http://a\n\nProfile: Example\nParent: Patient\nId: example\nTitle: \"Example\"\n* ^status = #draft\n* name 1..1\n  * ^short = \"Name\"\n",
+		},
+		{
+			name: "caret rule after other rules",
+			rule: &rules.EntityMetadataOrderRule{},
+			fsh:  "Profile: Example\nParent: Patient\nId: example\nTitle: \"Example\"\n* ^status = #draft\n* name 1..1\n  * given MS\n* ^abstract = false\n",
+			want: []problem{{Message: "Caret rule ^abstract should come before the other rules in Profile Example.", Line: 8}},
+		},
+		{
+			name: "metadata out of order",
+			rule: &rules.EntityMetadataOrderRule{},
+			fsh:  "Profile: Example\nTitle: \"Example\"\nId: example\nParent: Patient\n",
+			want: []problem{
+				{Message: "Id should come before Title in Profile Example.", Line: 3},
+				{Message: "Parent should come before Title in Profile Example.", Line: 4},
+			},
+		},
+		{
+			name: "instance order",
+			rule: &rules.EntityMetadataOrderRule{},
+			fsh:  "Instance: Example\nUsage: #example\nInstanceOf: Patient\n* active = true\n",
+			want: []problem{{Message: "InstanceOf should come before Usage in Instance Example.", Line: 3}},
+		},
+		{
+			name: "other keywords come last",
+			rule: &rules.EntityMetadataOrderRule{},
+			fsh:  "Extension: Example\nContext: Patient\nId: example\n",
+			want: []problem{{Message: "Id should come before Context in Extension Example.", Line: 3}},
+		},
+		{
+			name: "configured keywords",
+			rule: &rules.EntityMetadataOrderRule{Keywords: []string{"Title", "Id"}},
+			fsh:  "ValueSet: Example\nId: example\nTitle: \"Example\"\n",
+			want: []problem{{Message: "Title should come before Id in ValueSet Example.", Line: 3}},
+		},
+		{
+			name: "ignored rule order",
+			rule: &rules.EntityMetadataOrderRule{IgnoreRuleOrder: true},
+			fsh:  "CodeSystem: Example\n* #a \"A\"\n* ^caseSensitive = true\n",
+		},
+		{
+			name: "rule sets only check rules",
+			rule: &rules.EntityMetadataOrderRule{},
+			fsh:  "RuleSet: Example\n* name 1..1\n* ^status = #draft\n",
+			want: []problem{{Message: "Caret rule ^status should come before the other rules in RuleSet Example.", Line: 3}},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fc, err := lint.NewFileContextFromData("Example.fsh", []byte(tt.fsh))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			problems, err := tt.rule.Validate(fc)
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			var got []problem
+			for _, p := range problems {
+				got = append(got, problem{Message: p.Message, Line: p.StartPosition().LineNumber})
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Validate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestEntityMetadataOrder_Fix(t *testing.T) {
+	tests := []struct {
+		name      string
+		fsh       string
+		fixUnsafe bool
+		want      string
+	}{
+		{
+			name: "metadata and rules are reordered",
+			fsh:  "Profile: Example\nId: example\n// The parent.\nParent: Patient\n\n* name 1..1\n  * given MS\n* ^status = #draft // Draft.\n\nProfile: Other\nParent: Patient\n",
+			want: "Profile: Example\n// The parent.\nParent: Patient\nId: example\n* ^status = #draft // Draft.\n\n* name 1..1\n  * given MS\n\nProfile: Other\nParent: Patient\n",
+		},
+		{
+			name: "CRLF line endings",
+			fsh:  "Profile: Example\r\nId: example\r\nParent: Patient\r\n\r\n* name 1..1\r\n* ^status = #draft\r\n",
+			want: "Profile: Example\r\nParent: Patient\r\nId: example\r\n* ^status = #draft\r\n\r\n* name 1..1\r\n",
+		},
+		{
+			name: "last line without line ending",
+			fsh:  "ValueSet: Example\r\n* include codes from system Example\r\n* ^status = #draft",
+			want: "ValueSet: Example\r\n* ^status = #draft\r\n* include codes from system Example",
+		},
+		{
+			name: "caret rules are not moved before insert rules",
+			fsh:  "Profile: Example\nId: example\nParent: Patient\n* insert Draft\n* ^status = #active\n",
+			want: "Profile: Example\nId: example\nParent: Patient\n* insert Draft\n* ^status = #active\n",
+		},
+		{
+			name:      "caret rules are moved before insert rules with unsafe fixes",
+			fsh:       "Profile: Example\nParent: Patient\n* name insert Required\n* ^status = #active\n",
+			fixUnsafe: true,
+			want:      "Profile: Example\nParent: Patient\n* ^status = #active\n* name insert Required\n",
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter, _ := diagnostictest.NewFakeReporter()
+			linter := lint.NewLinter(nil, []lint.Rule{&rules.EntityMetadataOrderRule{}})
+			linter.Reporter = reporter
+			linter.Fix = true
+			linter.FixUnsafe = tt.fixUnsafe
+
+			got := linter.LintData("Example.fsh", []byte(tt.fsh))
+			if diff := cmp.Diff(tt.want, string(got)); diff != "" {
+				t.Errorf("LintData() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
@@ -0,0 +1,146 @@
+package rules
+
+import (
+	"bytes"
+	"reflect"
+	"strconv"
+	"strings"
+
+	"github.com/verily-src/fsh-lint/ast"
+	"github.com/verily-src/fsh-lint/internal/match"
+	"github.com/verily-src/fsh-lint/lint"
+)
+
+// metadataOrder is the canonical order of the metadata of each kind of entity,
+// by the fields of ast.FSHDocument holding the entities and the Go field names
+// of the entities. The name is declared by the entity header.
+var metadataOrder = map[string][]string{
+	"CodeSystems": {"Name", "ID", "Title", "Description"},
+	"Extensions":  {"Name", "Parent", "ID", "Title", "Description", "Contexts"},
+	"Instances":   {"Name", "InstanceOf", "Title", "Description", "Usage"},
+	"Profiles":    {"Name", "Parent", "ID", "Title", "Description"},
+	"ValueSets":   {"Name", "ID", "Title", "Description"},
+}
+
+// metadataKeywords are the FSH keywords of the metadata fields.
+var metadataKeywords = map[string]string{
+	"Contexts":    "Context",
+	"Description": "Description",
+	"ID":          "Id",
+	"InstanceOf":  "InstanceOf",
+	"Parent":      "Parent",
+	"Title":       "Title",
+	"Usage":       "Usage",
+}
+
+// metadataValues derive the values of the metadata fields that fixes can insert
+// from the kind and name of the entity. They are the values that the
+// *-name-matches-id and *-name-matches-title rules expect, without a
+// NameSuffix, so that the inserted values are not fixed again.
+var metadataValues = map[string]func(kind, name string) string{
+	"ID": func(_, name string) string {
+		return match.NameToID(name)
+	},
+	"Title": func(kind, name string) string {
+		if kind == "Profiles" {
+			return strconv.Quote(profileTitle(name))
+		}
+		return strconv.Quote(match.NameToTitle(name))
+	},
+}
+
+// lastLine returns the last line of the element, including the lines of
+// multi-line strings, or 0 if it has no location.
+func lastLine(element *ast.ParsedElement[string]) int {
+	if element == nil || element.Location == nil || element.Location.End == nil {
+		return 0
+	}
+	return element.Location.End.LineNumber + strings.Count(element.Value, "\n")
+}
+
+// metadataEnd returns the last line of the metadata of the entity, a pointer to
+// an entity of the given kind, that come before field in the canonical order,
+// or of all of its metadata if field is empty. The entity header comes first.
+func metadataEnd(entity reflect.Value, kind, field string) int {
+	end := 0
+	for _, name := range metadataOrder[kind] {
+		if name == field {
+			break
+		}
+		for _, v := range collectValues(entity, []string{name}) {
+			if element, ok := v.Interface().(*ast.ParsedElement[string]); ok {
+				end = max(end, lastLine(element))
+			}
+		}
+	}
+	return end
+}
+
+// insertLineEdit returns the edit that inserts text as a new line after the
+// given 1-based line of data, with the indentation and line ending of that
+// line, or of the file for the last line. Returns false if data has no such
+// line.
+func insertLineEdit(data []byte, line int, text string) (*lint.TextEdit, bool) {
+	start, ok := lint.Offset(data, &ast.Position{LineNumber: line})
+	if !ok {
+		return nil, false
+	}
+	end := len(data)
+	if i := bytes.IndexByte(data[start:], '\n'); i >= 0 {
+		end = start + i
+	}
+	newline := "\n"
+	if end > start && data[end-1] == '\r' {
+		end--
+		newline = "\r\n"
+	} else if end == len(data) && bytes.Contains(data, []byte("\r\n")) {
+		// The last line has no line ending, so use the one of the file.
+		newline = "\r\n"
+	}
+	content := data[start:end]
+	indent := content[:len(content)-len(bytes.TrimLeft(content, " \t"))]
+	return &lint.TextEdit{Start: end, End: end, NewText: newline + string(indent) + text}, true
+}
+
+// replaceValueEdit returns the edit that replaces the value of the element with
+// value. The value is the last token of the element, such as the name of an ID
+// or the string of a title, after its opening quote. Returns false if the value
+// is not at the location of the element in data.
+func replaceValueEdit(data []byte, element *ast.ParsedElement[string], value string) (*lint.TextEdit, bool) {
+	if element == nil || element.Location == nil || element.Value == "" {
+		return nil, false
+	}
+	start, ok := lint.Offset(data, element.Location.End)
+	if !ok {
+		return nil, false
+	}
+	if start < len(data) && data[start] == '"' {
+		start++
+	}
+	if !bytes.HasPrefix(data[start:], []byte(element.Value)) {
+		return nil, false
+	}
+	return &lint.TextEdit{Start: start, End: start + len(element.Value), NewText: value}, true
+}
+
+// replaceValueProblem returns a problem at the element, whose fix replaces its
+// value with diff.Want. The problem is not fixable if the value is not at the
+// location of the element in data, such as for a document that was not parsed
+// from it.
+func replaceValueProblem(data []byte, id, msg string, element *ast.ParsedElement[string], diff *lint.Diff) (*lint.Problem, error) {
+	edit, ok := replaceValueEdit(data, element, diff.Want)
+	if !ok {
+		return lint.NewProblem(id, msg, element.Location, diff, false)
+	}
+	return lint.NewProblemWithEdits(id, msg, element.Location, diff, []*lint.TextEdit{edit})
+}
+
+// renameValueProblem is like replaceValueProblem, for an element that is the
+// name or ID of an entity, whose fix renames it.
+func renameValueProblem(data []byte, id, msg string, element *ast.ParsedElement[string], diff *lint.Diff) (*lint.Problem, error) {
+	edit, ok := replaceValueEdit(data, element, diff.Want)
+	if !ok {
+		return lint.NewProblem(id, msg, element.Location, diff, false)
+	}
+	return lint.NewRenameProblem(id, msg, element.Location, diff, []*lint.TextEdit{edit})
+}
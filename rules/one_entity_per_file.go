@@ -0,0 +1,77 @@
+package rules
+
+import (
+	"fmt"
+	"path/filepath"
+
+	"github.com/verily-src/fsh-lint/ast"
+	"github.com/verily-src/fsh-lint/internal/fsh"
+	"github.com/verily-src/fsh-lint/internal/split"
+	"github.com/verily-src/fsh-lint/lint"
+)
+
+// OneEntityPerFileRule checks that each file defines at most one profile,
+// extension, instance, value set, or code system, as the filename rules expect.
+type OneEntityPerFileRule struct{}
+
+// ID() returns the rule ID.
+func (*OneEntityPerFileRule) ID() string {
+	return "one-entity-per-file"
+}
+
+// Message() returns the appropriate lint error message for this rule.
+func (*OneEntityPerFileRule) Message() string {
+	return "Each file must define at most one profile, extension, instance, value set, or code system."
+}
+
+// Metadata() returns the documentation metadata for this rule.
+func (*OneEntityPerFileRule) Metadata() *lint.Metadata {
+	return &lint.Metadata{
+		Category: lint.CategorySpecial,
+		Description: "Each file should define at most one profile, extension, instance, value set, or code system, in a file named after it, which the `*-name-matches-filename` rules check. Aliases, rule sets, and invariants may be defined in any file.\n\n" +
+			"Problems are located at the name of each entity that should be moved into a file of its own, which is every such entity of the file except the one named like the file. " +
+			"They are not fixed by `--fix`, since fixing them creates files. Instead, `fsh-lint split` moves each entity into a file named after it, without overwriting existing files.\n\n" +
+			"The rule is opt-in, since many projects define related entities together, and only runs when its severity is set in the configuration, such as `one-entity-per-file: warning`.",
+		Examples: []lint.Example{
+			{
+				Description: "A file named `ExampleProfile.fsh` defining the profile, and the rule set it uses:",
+				FSH: `RuleSet: Draft
+* ^status = #draft
+
+Profile: ExampleProfile
+Parent: Patient
+* insert Draft`,
+				Good: true,
+			},
+			{
+				Description: "A file named `ExampleProfile.fsh` also defining a value set:",
+				FSH: `Profile: ExampleProfile
+Parent: Patient
+
+ValueSet: ExampleCodes
+* include codes from system http://example.org`,
+				Good: false,
+			},
+		},
+		EntityKinds: lint.EntityKinds,
+		OptIn:       true,
+	}
+}
+
+// Validate returns a *lint.Problem for each entity that should be moved into a
+// file of its own.
+func (r *OneEntityPerFileRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
+	var problems []*lint.Problem
+	for _, entity := range split.Moved(fc.Path, fsh.NewSyntaxTree(fsh.Tokens(string(fc.Data)))) {
+		name := firstToken(entity.Tokens[1:])
+		location := &ast.Location{Start: lint.Position(fc.Data, name.Start), End: lint.Position(fc.Data, name.End)}
+		message := fmt.Sprintf("%s %s should be defined in a file of its own, %s. It can be moved with fsh-lint split.",
+			entity.Keyword(), entity.Name(), filepath.Base(split.Path(fc.Path, entity)))
+		p, err := lint.NewProblem(r.ID(), message, location, nil, false)
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, p)
+	}
+	return problems, nil
+}
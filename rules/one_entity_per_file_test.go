@@ -0,0 +1,101 @@
+package rules_test
+
+import (
+	"testing"
+
+	"github.com/google/go-cmp/cmp"
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic/diagnostictest"
+	"github.com/verily-src/fsh-lint/internal/config"
+	"github.com/verily-src/fsh-lint/lint"
+	"github.com/verily-src/fsh-lint/rules"
+)
+
+func TestOneEntityPerFile(t *testing.T) {
+	type problem struct {
+		Message string
+		Line    int
+	}
+	tests := []struct {
+		name string
+		path string
+		fsh  string
+		want []problem
+	}{
+		{
+			name: "one entity with rule sets and aliases",
+			path: "input/fsh/Example.fsh",
+			fsh:  "Alias: $a = http://a\n\nRuleSet: Draft\n* ^status = #draft\n\nProfile: Example\nParent: Patient\n* insert Draft\n",
+		},
+		{
+			name: "several entities",
+			path: "input/fsh/Example.fsh",
+			fsh:  "ValueSet: Codes\n* include codes from system Example\n\nProfile: Example\nParent: Patient\n\nInstance: ExamplePatient\nInstanceOf: Example\n",
+			want: []problem{
+				{Message: "ValueSet Codes should be defined in a file of its own, Codes.fsh. It can be moved with fsh-lint split.", Line: 1},
+				{Message: "Instance ExamplePatient should be defined in a file of its own, ExamplePatient.fsh. It can be moved with fsh-lint split.", Line: 7},
+			},
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			fc, err := lint.NewFileContextFromData(tt.path, []byte(tt.fsh))
+			if err != nil {
+				t.Fatal(err)
+			}
+
+			problems, err := (&rules.OneEntityPerFileRule{}).Validate(fc)
+			if err != nil {
+				t.Fatalf("Validate() error = %v", err)
+			}
+
+			var got []problem
+			for _, p := range problems {
+				got = append(got, problem{Message: p.Message, Line: p.StartPosition().LineNumber})
+			}
+			if diff := cmp.Diff(tt.want, got); diff != "" {
+				t.Errorf("Validate() mismatch (-want +got):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestOneEntityPerFile_OptIn(t *testing.T) {
+	const fsh = "ValueSet: Codes\n* include codes from system Example\n\nProfile: Example\nParent: Patient\n"
+	tests := []struct {
+		name   string
+		config string
+		want   int
+	}{
+		{
+			name: "not configured",
+			want: 0,
+		},
+		{
+			name:   "severity set",
+			config: "rules:\n  one-entity-per-file: warning\n",
+			want:   1,
+		},
+		{
+			name:   "turned off",
+			config: "rules:\n  one-entity-per-file: off\n",
+			want:   0,
+		},
+	}
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			cfg, err := config.Parse([]byte(tt.config))
+			if err != nil {
+				t.Fatal(err)
+			}
+			reporter, printer := diagnostictest.NewFakeReporter()
+			linter := lint.NewLinter(nil, []lint.Rule{&rules.OneEntityPerFileRule{}})
+			linter.Reporter = reporter
+			linter.Config = cfg
+
+			linter.LintData("Example.fsh", []byte(fsh))
+			if got := len(printer.Messages); got != tt.want {
+				t.Errorf("LintData() reported %d problems, want %d: %v", got, tt.want, printer.Messages)
+			}
+		})
+	}
+}
@@ -2,7 +2,9 @@ package rules
 
 import (
 	"fmt"
+	"reflect"
 
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/lint"
 )
 
@@ -16,6 +18,10 @@ type ProfileAssignmentPresentRule struct {
 	// AssignmentExample will be appended to the lint message to guide the user on
 	// how to add a caret value rule that sets the value of Element. Optional.
 	AssignmentExample string
+
+	// FixValue is the value that fixes assign to Element, such as #draft for
+	// status. Optional, problems are not fixable if it is empty.
+	FixValue string
 }
 
 // ID() returns the rule ID.
@@ -31,6 +37,38 @@ func (r *ProfileAssignmentPresentRule) Message() string {
 	return fmt.Sprintf("Profile field '%s' must be set. Example: %s", r.Element, r.AssignmentExample)
 }
 
+// Metadata() returns the documentation metadata for this rule.
+func (*ProfileAssignmentPresentRule) Metadata() *lint.Metadata {
+	return &lint.Metadata{
+		Category: lint.CategorySpecial,
+		Description: "All profiles should have the configured fields set in an assignment rule (caret value rule). By default, these fields are:\n\n" +
+			"- status\n" +
+			"- abstract\n\n" +
+			"Problems are located at the name of the profile. They are fixed by inserting a caret value rule after the metadata and the other caret value rules of the profile, which sets status to `#draft` and abstract to `false` by default.",
+		Examples: []lint.Example{
+			{
+				Description: "A profile with both status and abstract fields correctly set:",
+				FSH: `Profile: Example
+* ^status = #retired
+* ^abstract = false`,
+				Good: true,
+			},
+			{
+				Description: "A profile missing the abstract field:",
+				FSH: `Profile: Example
+* ^status = #retired`,
+				Good: false,
+			},
+		},
+		EntityKinds: []lint.EntityKind{lint.EntityProfile},
+		Fixable:     true,
+		Resources: []string{
+			"`abstract` must be set to `true` or `false`.",
+			"`status` must be set to one of the statuses defined [here](https://build.fhir.org/structuredefinition-definitions.html#:~:text=the%20root%20element.-,StructureDefinition.status,-Element%20Id).",
+		},
+	}
+}
+
 // Validate returns a *lint.Problem for each profile found that does not contain an assignment rule
 // (caret value rule) that sets the value of ProfileAssignmentPresentRule.Element.
 func (r *ProfileAssignmentPresentRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
@@ -40,24 +78,58 @@ func (r *ProfileAssignmentPresentRule) Validate(fc *lint.FileContext) ([]*lint.P
 	}
 
 	var problems []*lint.Problem
-	for _, p := range fc.ParsedFSH.Profiles {
+	for _, profile := range fc.ParsedFSH.Profiles {
 		hasElement := false
-		for _, rule := range p.ProfileRules.CaretValueRules {
+		for _, rule := range profile.ProfileRules.CaretValueRules {
 			if rule.Element != nil && rule.Element.Value == r.Element && rule.Value != nil && rule.Value.Value != "" {
 				hasElement = true
 				break
 			}
 		}
+		if hasElement {
+			continue
+		}
 
-		if !hasElement {
-			p, err := lint.NewProblem(r.ID(), r.Message(), nil, nil, false)
-
-			if err != nil {
-				return nil, err
-			}
-			problems = append(problems, p)
+		var location *ast.Location
+		if profile.Name != nil {
+			location = profile.Name.Location
+		}
+		p, err := lint.NewProblem(r.ID(), r.Message(), location, nil, false)
+		if edit, text, ok := r.insertionFix(fc.Data, profile); ok {
+			diff := &lint.Diff{Want: text, FieldName: fmt.Sprintf("Profile %s", r.Element)}
+			p, err = lint.NewProblemWithEdits(r.ID(), r.Message(), location, diff, []*lint.TextEdit{edit})
 		}
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, p)
 	}
 
 	return problems, nil
 }
+
+// insertionFix returns the edit that inserts a caret value rule assigning
+// FixValue to Element after the metadata and the other caret value rules of the
+// profile, and the inserted rule. Returns false if FixValue is empty, or if the
+// profile's Element is assigned an empty value, which is not replaced.
+func (r *ProfileAssignmentPresentRule) insertionFix(data []byte, profile *ast.Profile) (*lint.TextEdit, string, bool) {
+	if r.FixValue == "" {
+		return nil, "", false
+	}
+	line := metadataEnd(reflect.ValueOf(profile), "Profiles", "")
+	for _, rule := range profile.ProfileRules.CaretValueRules {
+		if rule.ElementInProfile != nil && rule.ElementInProfile.Value != "" {
+			continue
+		}
+		if rule.Element != nil && rule.Element.Value == r.Element {
+			return nil, "", false
+		}
+		line = max(line, lastLine(rule.Value))
+	}
+	if line == 0 {
+		return nil, "", false
+	}
+	text := fmt.Sprintf("* ^%s = %s", r.Element, r.FixValue)
+	edit, ok := insertLineEdit(data, line, text)
+	return edit, text, ok
+}
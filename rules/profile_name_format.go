@@ -4,7 +4,7 @@ import (
 	"fmt"
 	"regexp"
 
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/lint"
 )
 
@@ -31,6 +31,27 @@ func (r *ProfileNameFormatRule) Message() string {
 	return fmt.Sprintf("Profile name must have format: %s", r.FormatDescription)
 }
 
+// Metadata() returns the documentation metadata for this rule.
+func (*ProfileNameFormatRule) Metadata() *lint.Metadata {
+	return &lint.Metadata{
+		Category:    lint.CategoryProfile,
+		Description: "Profile name must match the configured regular expression. When no regular expression is configured, all profile names are valid.",
+		Examples: []lint.Example{
+			{
+				Description: "With the format `^[A-Z][A-Za-z0-9]*$`:",
+				FSH:         `Profile: ExampleProfile`,
+				Good:        true,
+			},
+			{
+				Description: "With the format `^[A-Z][A-Za-z0-9]*$`:",
+				FSH:         `Profile: example_profile`,
+				Good:        false,
+			},
+		},
+		EntityKinds: []lint.EntityKind{lint.EntityProfile},
+	}
+}
+
 // Validate returns a *lint.Problem for each profile name that does not match the configured RegexFormat.
 func (r *ProfileNameFormatRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
 	var problems []*lint.Problem
@@ -48,7 +69,7 @@ func (r *ProfileNameFormatRule) Validate(fc *lint.FileContext) ([]*lint.Problem,
 
 // profileNameFormatViolation returns nil if the profile name matches the configured RegexFormat,
 // and a *lint.Problem otherwise.
-func profileNameFormatViolation(p *types.Profile, id string, msg string, regex *regexp.Regexp) (*lint.Problem, error) {
+func profileNameFormatViolation(p *ast.Profile, id string, msg string, regex *regexp.Regexp) (*lint.Problem, error) {
 	if regex != nil && !regex.MatchString(p.Name.Value) {
 		return lint.NewProblem(id, msg, p.Name.Location, nil, false)
 	} else {
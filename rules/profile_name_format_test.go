@@ -6,7 +6,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/lint"
 	"github.com/verily-src/fsh-lint/rules"
 )
@@ -17,12 +17,12 @@ func TestLintProfileNameFormat(t *testing.T) {
 	sut := rules.ProfileNameFormatRule{RegexFormat: profileRegex}
 	ruleName := "ProfileNameFormatRule"
 
-	testLocation := &types.Location{
-		Start: &types.Position{
+	testLocation := &ast.Location{
+		Start: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 2,
 		},
-		End: &types.Position{
+		End: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 4,
 		},
@@ -30,26 +30,26 @@ func TestLintProfileNameFormat(t *testing.T) {
 
 	tests := []struct {
 		name        string
-		fshDocument *types.FSHDocument
+		fshDocument *ast.FSHDocument
 		want        []*lint.Problem
 	}{
 		{
 			name:        "no profiles",
-			fshDocument: &types.FSHDocument{},
+			fshDocument: &ast.FSHDocument{},
 			want:        []*lint.Problem{},
 		},
 		{
 			name: "multiple valid profiles",
-			fshDocument: &types.FSHDocument{
-				Profiles: []*types.Profile{
+			fshDocument: &ast.FSHDocument{
+				Profiles: []*ast.Profile{
 					{
-						Name: &types.ParsedElement[string]{
+						Name: &ast.ParsedElement[string]{
 							Value:    "PrefixOneSuffix",
 							Location: testLocation,
 						},
 					},
 					{
-						Name: &types.ParsedElement[string]{
+						Name: &ast.ParsedElement[string]{
 							Value:    "PrefixTwoSuffix",
 							Location: testLocation,
 						},
@@ -60,16 +60,16 @@ func TestLintProfileNameFormat(t *testing.T) {
 		},
 		{
 			name: "multiple invalid profiles",
-			fshDocument: &types.FSHDocument{
-				Profiles: []*types.Profile{
+			fshDocument: &ast.FSHDocument{
+				Profiles: []*ast.Profile{
 					{
-						Name: &types.ParsedElement[string]{
+						Name: &ast.ParsedElement[string]{
 							Value:    "NotPrefixOneSuffix",
 							Location: testLocation,
 						},
 					},
 					{
-						Name: &types.ParsedElement[string]{
+						Name: &ast.ParsedElement[string]{
 							Value:    "PrefixTwoSuffixNot",
 							Location: testLocation,
 						},
@@ -4,7 +4,7 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/lint"
 )
 
@@ -23,6 +23,30 @@ func (*ProfileNameMatchesFilenameRule) Message() string {
 	return ProfileNameMatchesFilenameMessage
 }
 
+// Metadata() returns the documentation metadata for this rule.
+func (*ProfileNameMatchesFilenameRule) Metadata() *lint.Metadata {
+	return &lint.Metadata{
+		Category: lint.CategoryProfile,
+		Description: "Profile name must match filename. This match is case sensitive, and the file extension must be `.fsh`.\n\n" +
+			"For example, profile name `Example` **matches** filename `Example.fsh`, but **does not match** `example.fsh` or `Example.txt`.",
+		Examples: []lint.Example{
+			{
+				Description: "In a file named `Example.fsh`:",
+				FSH: `Profile: Example
+Parent: Patient`,
+				Good: true,
+			},
+			{
+				Description: "In a file named `example.fsh`:",
+				FSH: `Profile: Example
+Parent: Patient`,
+				Good: false,
+			},
+		},
+		EntityKinds: []lint.EntityKind{lint.EntityProfile},
+	}
+}
+
 // Validate returns a *lint.Problem for each profile name
 // that does not match the filename.
 func (*ProfileNameMatchesFilenameRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
@@ -41,7 +65,7 @@ func (*ProfileNameMatchesFilenameRule) Validate(fc *lint.FileContext) ([]*lint.P
 
 // ProfileNameMatchesFilenameViolation returns nil if the profile name
 // matches filename exactly, and a *lint.Problem otherwise.
-func profileNameMatchesFilenameViolation(p *types.Profile, fp string) (*lint.Problem, error) {
+func profileNameMatchesFilenameViolation(p *ast.Profile, fp string) (*lint.Problem, error) {
 	filename := filepath.Base(fp)
 	if strings.TrimSuffix(filename, ".fsh") != p.Name.Value {
 		// Profile Name does not match filename
@@ -5,18 +5,18 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/lint"
 	"github.com/verily-src/fsh-lint/rules"
 )
 
 func TestLintProfileNameMatchesFilename(t *testing.T) {
-	testLocation := &types.Location{
-		Start: &types.Position{
+	testLocation := &ast.Location{
+		Start: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 2,
 		},
-		End: &types.Position{
+		End: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 4,
 		},
@@ -30,8 +30,8 @@ func TestLintProfileNameMatchesFilename(t *testing.T) {
 		{
 			name: "no profiles",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					Profiles: []*types.Profile{}},
+				ParsedFSH: &ast.FSHDocument{
+					Profiles: []*ast.Profile{}},
 				Path: "path/to/Profile.fsh",
 			},
 			want: []*lint.Problem{},
@@ -39,10 +39,10 @@ func TestLintProfileNameMatchesFilename(t *testing.T) {
 		{
 			name: "exact match",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					Profiles: []*types.Profile{
+				ParsedFSH: &ast.FSHDocument{
+					Profiles: []*ast.Profile{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleProfile",
 								Location: testLocation,
 							},
@@ -56,10 +56,10 @@ func TestLintProfileNameMatchesFilename(t *testing.T) {
 		{
 			name: "exact match no path",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					Profiles: []*types.Profile{
+				ParsedFSH: &ast.FSHDocument{
+					Profiles: []*ast.Profile{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleProfile",
 								Location: testLocation,
 							},
@@ -73,10 +73,10 @@ func TestLintProfileNameMatchesFilename(t *testing.T) {
 		{
 			name: "exact match, path in root",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					Profiles: []*types.Profile{
+				ParsedFSH: &ast.FSHDocument{
+					Profiles: []*ast.Profile{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleProfile",
 								Location: testLocation,
 							},
@@ -90,10 +90,10 @@ func TestLintProfileNameMatchesFilename(t *testing.T) {
 		{
 			name: "not match",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					Profiles: []*types.Profile{
+				ParsedFSH: &ast.FSHDocument{
+					Profiles: []*ast.Profile{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleProfileOne",
 								Location: testLocation,
 							},
@@ -119,10 +119,10 @@ func TestLintProfileNameMatchesFilename(t *testing.T) {
 		{
 			name: "empty profile name",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					Profiles: []*types.Profile{
+				ParsedFSH: &ast.FSHDocument{
+					Profiles: []*ast.Profile{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "",
 								Location: testLocation,
 							},
@@ -148,16 +148,16 @@ func TestLintProfileNameMatchesFilename(t *testing.T) {
 		{
 			name: "one matching, one non matching profile",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					Profiles: []*types.Profile{
+				ParsedFSH: &ast.FSHDocument{
+					Profiles: []*ast.Profile{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleProfileOne",
 								Location: testLocation,
 							},
 						},
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleProfileTwo",
 								Location: testLocation,
 							},
@@ -183,16 +183,16 @@ func TestLintProfileNameMatchesFilename(t *testing.T) {
 		{
 			name: "two non matching profiles",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					Profiles: []*types.Profile{
+				ParsedFSH: &ast.FSHDocument{
+					Profiles: []*ast.Profile{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleProfileOne",
 								Location: testLocation,
 							},
 						},
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleProfileTwo",
 								Location: testLocation,
 							},
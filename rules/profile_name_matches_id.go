@@ -2,7 +2,7 @@ package rules
 
 import (
 	"github.com/iancoleman/strcase"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/internal/match"
 	"github.com/verily-src/fsh-lint/lint"
 )
@@ -22,12 +22,42 @@ func (*ProfileNameMatchesIDRule) Message() string {
 	return ProfileNameMatchesIDMessage
 }
 
+// Metadata() returns the documentation metadata for this rule.
+func (*ProfileNameMatchesIDRule) Metadata() *lint.Metadata {
+	return &lint.Metadata{
+		Category: lint.CategoryProfile,
+		Description: "Profile name (PascalCase) must match profile id in kebab-case.\n\n" +
+			"If there are numbers in the profile name, **any grouping with its adjacent alphabetic characters is acceptable**. In other words, hyphens in the id are optional when adjacent to a number. This flexible hyphenation applies only to numbers.\n\n" +
+			"If there are acronyms in the profile name, the acronym should be grouped as one word. Note, that the first letter of a word following an acronym should be capitalized in the PascalCase name.\n\n" +
+			"Note, the \"Want\" value that is generated, is one of the correct configurations where each group of numbers is grouped as their own separate word. This is the behavior of [strcase.ToDelimited](https://pkg.go.dev/github.com/iancoleman/strcase#ToDelimited).\n\n" +
+			"For example:\n\n" +
+			"- Profile name `ExampleOne` **matches** id `example-one`, but **does not match** `exampleone` or `ExampleOne`.\n" +
+			"- Profile name `A123Example` **matches** id `a-123-example`, `a123example`, `a1-23example`, and several others.\n" +
+			"- Profile name `ABCExample` **matches** id `abc-example`, but **does not match** `a-b-c-example` or `abce-xample`.",
+		Examples: []lint.Example{
+			{
+				FSH: `Profile: ExampleOne
+Id: example-one`,
+				Good: true,
+			},
+			{
+				FSH: `Profile: ExampleOne
+Id: exampleone`,
+				Good: false,
+			},
+		},
+		EntityKinds: []lint.EntityKind{lint.EntityProfile},
+		Fixable:     true,
+		UnsafeFix:   true,
+	}
+}
+
 // Validate returns a *lint.Problem for each profile name
 // that does not match its corresponding ID.
 func (*ProfileNameMatchesIDRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
 	var problems []*lint.Problem
 	for _, profile := range fc.ParsedFSH.Profiles {
-		p, err := profileNameMatchesIDViolation(profile)
+		p, err := profileNameMatchesIDViolation(fc.Data, profile)
 		if err != nil {
 			return nil, err
 		}
@@ -40,7 +70,7 @@ func (*ProfileNameMatchesIDRule) Validate(fc *lint.FileContext) ([]*lint.Problem
 
 // ProfileNameMatchesIDViolation returns nil if the profile name
 // matches id exactly, and a *lint.Problem otherwise.
-func profileNameMatchesIDViolation(p *types.Profile) (*lint.Problem, error) {
+func profileNameMatchesIDViolation(data []byte, p *ast.Profile) (*lint.Problem, error) {
 	if !match.IsNameKebabMatchWithID(p.Name.Value, p.ID.Value, true) {
 		// Profile Name does not match ID
 		diff := &lint.Diff{
@@ -49,7 +79,7 @@ func profileNameMatchesIDViolation(p *types.Profile) (*lint.Problem, error) {
 			FieldName: "Profile ID",
 		}
 
-		return lint.NewProblem(ProfileNameMatchesIDID, ProfileNameMatchesIDMessage, p.ID.Location, diff, true)
+		return renameValueProblem(data, ProfileNameMatchesIDID, ProfileNameMatchesIDMessage, p.ID, diff)
 	}
 	return nil, nil
 }
@@ -5,18 +5,18 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/lint"
 	"github.com/verily-src/fsh-lint/rules"
 )
 
 func TestLintProfileNameMatchesID(t *testing.T) {
-	testLocation := &types.Location{
-		Start: &types.Position{
+	testLocation := &ast.Location{
+		Start: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 2,
 		},
-		End: &types.Position{
+		End: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 4,
 		},
@@ -24,31 +24,31 @@ func TestLintProfileNameMatchesID(t *testing.T) {
 
 	tests := []struct {
 		name     string
-		profiles []*types.Profile
+		profiles []*ast.Profile
 		want     []*lint.Problem
 	}{
 		{
 			name:     "no profiles",
-			profiles: []*types.Profile{},
+			profiles: []*ast.Profile{},
 			want:     nil,
 		},
 		{
 			name: "name matches id",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("ExampleProfile", "example-profile", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "one word match",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("Example", "example", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "id different text",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("ExampleProfileOne", "example-profile-two", testLocation),
 			},
 			want: []*lint.Problem{
@@ -61,13 +61,12 @@ func TestLintProfileNameMatchesID(t *testing.T) {
 						Want:      "example-profile-one",
 						FieldName: "Profile ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "id incorrect case",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("ExampleProfileName", "Example-Profile-Name", testLocation),
 			},
 			want: []*lint.Problem{{
@@ -79,12 +78,11 @@ func TestLintProfileNameMatchesID(t *testing.T) {
 					Want:      "example-profile-name",
 					FieldName: "Profile ID",
 				},
-				IsFixable: true,
 			}},
 		},
 		{
 			name: "id incorrect kebab style",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("ExampleProfileName", "exampleprofile-name", testLocation),
 			},
 			want: []*lint.Problem{{
@@ -96,12 +94,11 @@ func TestLintProfileNameMatchesID(t *testing.T) {
 					Want:      "example-profile-name",
 					FieldName: "Profile ID",
 				},
-				IsFixable: true,
 			}},
 		},
 		{
 			name: "empty id",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("ExampleProfileName", "", testLocation),
 			},
 			want: []*lint.Problem{{
@@ -113,12 +110,11 @@ func TestLintProfileNameMatchesID(t *testing.T) {
 					Want:      "example-profile-name",
 					FieldName: "Profile ID",
 				},
-				IsFixable: true,
 			}},
 		},
 		{
 			name: "one matching, one non matching profile",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("ExampleProfileOne", "example-profile-one", testLocation),
 				createTestProfileWithNameAndID("ExampleProfileTwo", "example-profile-one", testLocation),
 			},
@@ -131,12 +127,11 @@ func TestLintProfileNameMatchesID(t *testing.T) {
 					Want:      "example-profile-two",
 					FieldName: "Profile ID",
 				},
-				IsFixable: true,
 			}},
 		},
 		{
 			name: "two non matching profiles",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("ExampleProfileOne", "example-profile-three", testLocation),
 				createTestProfileWithNameAndID("ExampleProfileTwo", "example-profile-three", testLocation),
 			},
@@ -150,7 +145,6 @@ func TestLintProfileNameMatchesID(t *testing.T) {
 						Want:      "example-profile-one",
 						FieldName: "Profile ID",
 					},
-					IsFixable: true,
 				},
 				{
 					RuleID:   rules.ProfileNameMatchesIDID,
@@ -161,69 +155,68 @@ func TestLintProfileNameMatchesID(t *testing.T) {
 						Want:      "example-profile-two",
 						FieldName: "Profile ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "no hyphen after number",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("123Abc", "123abc", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no hyphen before number",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("Abc123", "abc123", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no hyphens around numbers",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("A123B", "a123b", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no hyphen numbers before or after",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("123Abc456", "123abc456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "one hyphen after none before",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("123Abc456", "123-abc456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "one hyphen before none after",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("123Abc456", "123abc-456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "hyphens before and after",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("123Abc456", "123-abc-456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "extra hyphens between numbers",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("1234abc", "1-2-34abc", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "double hyphen should not pass",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("12", "1--2", testLocation),
 			},
 			want: []*lint.Problem{{
@@ -235,12 +228,11 @@ func TestLintProfileNameMatchesID(t *testing.T) {
 					Want:      "12",
 					FieldName: "Profile ID",
 				},
-				IsFixable: true,
 			}},
 		},
 		{
 			name: "should not start with a hyphen",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("1Abc", "-1abc", testLocation),
 			},
 			want: []*lint.Problem{{
@@ -252,12 +244,11 @@ func TestLintProfileNameMatchesID(t *testing.T) {
 					Want:      "1-abc",
 					FieldName: "Profile ID",
 				},
-				IsFixable: true,
 			}},
 		},
 		{
 			name: "should not end with a hyphen",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndID("Abc1", "Abc1-", testLocation),
 			},
 			want: []*lint.Problem{{
@@ -269,7 +260,6 @@ func TestLintProfileNameMatchesID(t *testing.T) {
 					Want:      "abc-1",
 					FieldName: "Profile ID",
 				},
-				IsFixable: true,
 			}},
 		},
 	}
@@ -279,8 +269,9 @@ func TestLintProfileNameMatchesID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// The problems are not fixable without the data of the file.
 			fc := &lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
+				ParsedFSH: &ast.FSHDocument{
 					Profiles: tt.profiles},
 			}
 
@@ -296,13 +287,13 @@ func TestLintProfileNameMatchesID(t *testing.T) {
 	}
 }
 
-func createTestProfileWithNameAndID(name string, id string, testLocation *types.Location) *types.Profile {
-	return &types.Profile{
-		Name: &types.ParsedElement[string]{
+func createTestProfileWithNameAndID(name string, id string, testLocation *ast.Location) *ast.Profile {
+	return &ast.Profile{
+		Name: &ast.ParsedElement[string]{
 			Value:    name,
 			Location: testLocation,
 		},
-		ID: &types.ParsedElement[string]{
+		ID: &ast.ParsedElement[string]{
 			Value:    id,
 			Location: testLocation,
 		},
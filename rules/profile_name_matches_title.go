@@ -4,10 +4,8 @@ import (
 	"strings"
 
 	"github.com/iancoleman/strcase"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
 
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/internal/match"
 	"github.com/verily-src/fsh-lint/lint"
 )
@@ -27,12 +25,41 @@ func (*ProfileNameMatchesTitleRule) Message() string {
 	return ProfileNameMatchesTitleMessage
 }
 
+// Metadata() returns the documentation metadata for this rule.
+func (*ProfileNameMatchesTitleRule) Metadata() *lint.Metadata {
+	return &lint.Metadata{
+		Category: lint.CategoryProfile,
+		Description: "Profile name (PascalCase) must match profile title in Title Case (space separated) and ending with \"Profile\". This check is **case insensitive**.\n\n" +
+			"If there are numbers in the profile name, **any grouping with its adjacent alphabetic characters is acceptable**. In other words, the space is optional in the title when adjacent to a number. This flexibility applies only to numbers.\n\n" +
+			"If there are acronyms in the profile name, the acronym should be grouped as one word. Note, that the first letter of a word following an acronym should be capitalized in the PascalCase name.\n\n" +
+			"Note, the \"Want\" value that is generated, is one of the correct configurations where each group of numbers is grouped as their own separate word. This is the behavior of [strcase.ToDelimited](https://pkg.go.dev/github.com/iancoleman/strcase#ToDelimited).\n\n" +
+			"For example:\n\n" +
+			"- Profile name `ExampleOne` **matches** title `Example One Profile`, but **does not match** `Example One`.\n" +
+			"- Profile name `A123Example` **matches** `A 123 Example Profile`, `A123example Profile`, `A1 23example Profile`, and several others.\n" +
+			"- Profile name `ABCExample` **matches** `ABC Example Profile`, but **does not match** `A B C Example Profile` or `Abce Xample Profile`.",
+		Examples: []lint.Example{
+			{
+				FSH: `Profile: ExampleOne
+Title: "Example One Profile"`,
+				Good: true,
+			},
+			{
+				FSH: `Profile: ExampleOne
+Title: "Example One"`,
+				Good: false,
+			},
+		},
+		EntityKinds: []lint.EntityKind{lint.EntityProfile},
+		Fixable:     true,
+	}
+}
+
 // Validate returns a *lint.Problem for each profile name
 // that does not match its corresponding title.
 func (*ProfileNameMatchesTitleRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
 	var problems []*lint.Problem
 	for _, profile := range fc.ParsedFSH.Profiles {
-		p, err := profileNameMatchesTitleViolation(profile)
+		p, err := profileNameMatchesTitleViolation(fc.Data, profile)
 		if err != nil {
 			return nil, err
 		}
@@ -45,7 +72,7 @@ func (*ProfileNameMatchesTitleRule) Validate(fc *lint.FileContext) ([]*lint.Prob
 
 // ProfileNameMatchesTitleViolation returns nil if the profile name matches title in
 // Title Case with "Profile" at the end, and a *lint.Problem otherwise.
-func profileNameMatchesTitleViolation(p *types.Profile) (*lint.Problem, error) {
+func profileNameMatchesTitleViolation(data []byte, p *ast.Profile) (*lint.Problem, error) {
 	const loweredProfileSuffix = " profile"
 
 	spaceSeparatedName := strcase.ToDelimited(p.Name.Value, ' ')
@@ -57,18 +84,23 @@ func profileNameMatchesTitleViolation(p *types.Profile) (*lint.Problem, error) {
 
 	if !match.IsNameMatchWithTitle(trimmedName, trimmedTitle, true) || !strings.HasSuffix(loweredTitle, " profile") {
 		// Profile Name does not match Title or missing "Profile" at the end
-		caser := cases.Title(language.AmericanEnglish)
 		diff := &lint.Diff{
 			Got:       p.Title.Value,
-			Want:      caser.String(spaceSeparatedName),
+			Want:      profileTitle(p.Name.Value),
 			FieldName: "Profile Title",
 		}
 
-		if !strings.HasSuffix(diff.Want, " Profile") {
-			diff.Want += " Profile"
-		}
-
-		return lint.NewProblem(ProfileNameMatchesTitleID, ProfileNameMatchesTitleMessage, p.Title.Location, diff, true)
+		return replaceValueProblem(data, ProfileNameMatchesTitleID, ProfileNameMatchesTitleMessage, p.Title, diff)
 	}
 	return nil, nil
 }
+
+// profileTitle returns the title that matches the profile name, in Title Case
+// and ending with "Profile".
+func profileTitle(name string) string {
+	title := match.NameToTitle(name)
+	if !strings.HasSuffix(title, " Profile") {
+		title += " Profile"
+	}
+	return title
+}
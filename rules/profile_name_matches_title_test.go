@@ -5,18 +5,19 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic/diagnostictest"
 	"github.com/verily-src/fsh-lint/lint"
 	"github.com/verily-src/fsh-lint/rules"
 )
 
 func TestLintProfileNameMatchesTitle(t *testing.T) {
-	testLocation := &types.Location{
-		Start: &types.Position{
+	testLocation := &ast.Location{
+		Start: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 2,
 		},
-		End: &types.Position{
+		End: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 4,
 		},
@@ -24,38 +25,38 @@ func TestLintProfileNameMatchesTitle(t *testing.T) {
 
 	tests := []struct {
 		name     string
-		profiles []*types.Profile
+		profiles []*ast.Profile
 		want     []*lint.Problem
 	}{
 		{
 			name:     "no profiles",
-			profiles: []*types.Profile{},
+			profiles: []*ast.Profile{},
 			want:     nil,
 		},
 		{
 			name: "name matches title",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("ExampleTest", "Example Test Profile", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "name matches title with acronym",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("ExampleTestABC", "Example Test ABC Profile", nil),
 			},
 			want: nil,
 		},
 		{
 			name: "if profile is already in the name, don't need another 'Profile' suffix",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("ExampleProfile", "Example Profile", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "title is the same but missing 'Profile' at the end",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("ExampleName", "Example Name", testLocation),
 			},
 			want: []*lint.Problem{
@@ -68,13 +69,12 @@ func TestLintProfileNameMatchesTitle(t *testing.T) {
 						Want:      "Example Name Profile",
 						FieldName: "Profile Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "incorrect title spacing",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("ExampleName", "Example   Name Profile", testLocation),
 			},
 			want: []*lint.Problem{
@@ -87,13 +87,12 @@ func TestLintProfileNameMatchesTitle(t *testing.T) {
 						Want:      "Example Name Profile",
 						FieldName: "Profile Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "empty title",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("ExampleName", "", testLocation),
 			},
 			want: []*lint.Problem{
@@ -106,13 +105,12 @@ func TestLintProfileNameMatchesTitle(t *testing.T) {
 						Want:      "Example Name Profile",
 						FieldName: "Profile Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "one matching, one non matching profile",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("ExampleOne", "Example Two Profile", testLocation),
 				createTestProfileWithNameAndTitle("ExampleTwo", "Example Two Profile", testLocation),
 			},
@@ -126,13 +124,12 @@ func TestLintProfileNameMatchesTitle(t *testing.T) {
 						Want:      "Example One Profile",
 						FieldName: "Profile Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "two non matching profiles",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("ExampleOne", "Example Three", testLocation),
 				createTestProfileWithNameAndTitle("ExampleTwo", "Example Three", testLocation),
 			},
@@ -146,7 +143,6 @@ func TestLintProfileNameMatchesTitle(t *testing.T) {
 						Want:      "Example One Profile",
 						FieldName: "Profile Title",
 					},
-					IsFixable: true,
 				},
 				{
 					RuleID:   rules.ProfileNameMatchesTitleID,
@@ -157,76 +153,75 @@ func TestLintProfileNameMatchesTitle(t *testing.T) {
 						Want:      "Example Two Profile",
 						FieldName: "Profile Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "different case ignored",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("ProfileName", "Profile nAME Profile", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no space after number",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("123Abc", "123Abc Profile", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no space before number",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("Abc123", "Abc123 Profile", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no spaces around numbers",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("A123B", "A123b Profile", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no space numbers before or after",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("123Abc456", "123abc456 Profile", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "one space after none before",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("123Abc456", "123 abc456 Profile", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "one space before none after",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("123Abc456", "123abc 456 Profile", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "spaces before and after",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("123Abc456", "123 ABC 456 Profile", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "extra spaces between numbers",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("1234abc", "1 2 34ABC Profile", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "double space should not pass",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("12", "1  2 Profile", testLocation),
 			},
 			want: []*lint.Problem{
@@ -239,13 +234,12 @@ func TestLintProfileNameMatchesTitle(t *testing.T) {
 						Want:      "12 Profile",
 						FieldName: "Profile Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "should not start with a space",
-			profiles: []*types.Profile{
+			profiles: []*ast.Profile{
 				createTestProfileWithNameAndTitle("1Abc", " 1abc Profile", testLocation),
 			},
 			want: []*lint.Problem{
@@ -258,7 +252,6 @@ func TestLintProfileNameMatchesTitle(t *testing.T) {
 						Want:      "1 Abc Profile",
 						FieldName: "Profile Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
@@ -269,8 +262,9 @@ func TestLintProfileNameMatchesTitle(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// The problems are not fixable without the data of the file.
 			fileContext := lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
+				ParsedFSH: &ast.FSHDocument{
 					Profiles: tt.profiles,
 				},
 			}
@@ -285,15 +279,61 @@ func TestLintProfileNameMatchesTitle(t *testing.T) {
 	}
 }
 
-func createTestProfileWithNameAndTitle(name string, title string, testLocation *types.Location) *types.Profile {
-	return &types.Profile{
-		Name: &types.ParsedElement[string]{
+func createTestProfileWithNameAndTitle(name string, title string, testLocation *ast.Location) *ast.Profile {
+	return &ast.Profile{
+		Name: &ast.ParsedElement[string]{
 			Value:    name,
 			Location: testLocation,
 		},
-		Title: &types.ParsedElement[string]{
+		Title: &ast.ParsedElement[string]{
 			Value:    title,
 			Location: testLocation,
 		},
 	}
 }
+
+func TestNameMatchesRules_Fix(t *testing.T) {
+	tests := []struct {
+		name string
+		fsh  string
+		want string
+	}{
+		{
+			name: "title that is also the keyword",
+			fsh:  "Profile: MyTitle\nId: my-title\nTitle: \"Title\"\n",
+			want: "Profile: MyTitle\nId: my-title\nTitle: \"My Title Profile\"\n",
+		},
+		{
+			name: "ID and title",
+			fsh:  "CodeSystem: ExampleCodes\r\nId:   Example-Codes\r\nTitle: \"Codes\" // The codes.\r\n",
+			want: "CodeSystem: ExampleCodes\r\nId:   example-codes\r\nTitle: \"Example Codes\" // The codes.\r\n",
+		},
+		{
+			name: "ID that occurs in the name",
+			fsh:  "ValueSet: ValueSetCodes\nId: value\nTitle: \"Value Set Codes\"\n",
+			want: "ValueSet: ValueSetCodes\nId: value-set-codes\nTitle: \"Value Set Codes\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter, _ := diagnostictest.NewFakeReporter()
+			linter := lint.NewLinter(nil, []lint.Rule{
+				&rules.ProfileNameMatchesIDRule{},
+				&rules.ProfileNameMatchesTitleRule{},
+				&rules.ValueSetNameMatchesIDRule{},
+				&rules.ValueSetNameMatchesTitleRule{},
+				&rules.CodeSystemNameMatchesIDRule{},
+				&rules.CodeSystemNameMatchesTitleRule{},
+			})
+			linter.Reporter = reporter
+			linter.Fix = true
+			linter.FixUnsafe = true
+
+			got := linter.LintData("Example.fsh", []byte(tt.fsh))
+			if diff := cmp.Diff(string(got), tt.want); diff != "" {
+				t.Errorf("LintData() mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
@@ -5,7 +5,7 @@ import (
 	"reflect"
 	"strings"
 
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/lint"
 )
 
@@ -16,7 +16,7 @@ var (
 	ErrNotRecursable  = fmt.Errorf("not recursable")
 	ErrNotSupported   = fmt.Errorf("kind not supported")
 
-	parsedElementStringType = reflect.TypeFor[*types.ParsedElement[string]]()
+	parsedElementStringType = reflect.TypeFor[*ast.ParsedElement[string]]()
 )
 
 // RequiredFieldPresentRule will check that the field given by the FieldPath value is non-nil
@@ -53,6 +53,43 @@ func (r *RequiredFieldPresentRule) Message() string {
 	return fmt.Sprintf("%s is missing.", r.FieldName)
 }
 
+// Metadata() returns the documentation metadata for this rule.
+func (*RequiredFieldPresentRule) Metadata() *lint.Metadata {
+	return &lint.Metadata{
+		Category: lint.CategorySpecial,
+		Description: "Required fields should not be missing. If this rule fails, **all other rules WILL NOT run**. This was made to simplify nil checks in all other rules that depend on the fact that required fields will not be nil. Required fields by default are:\n\n" +
+			"- Code System\n" +
+			"  - Name\n" +
+			"  - ID\n" +
+			"  - Title\n" +
+			"- Profile\n" +
+			"  - Name\n" +
+			"  - ID\n" +
+			"  - Title\n" +
+			"- Value Set\n" +
+			"  - Name\n" +
+			"  - ID\n" +
+			"  - Title\n\n" +
+			"Problems are located at the name of the entity missing the field. A missing ID or title is fixed by inserting one derived from the name, after the metadata that precede it in the canonical order. Inserting an ID is an unsafe fix, since it changes the canonical URL of the entity.",
+		Examples: []lint.Example{
+			{
+				FSH: `Profile: Example
+Id: example
+Title: "Example Profile"`,
+				Good: true,
+			},
+			{
+				FSH: `Profile: Example
+Title: "Example Profile"`,
+				Good: false,
+			},
+		},
+		EntityKinds: []lint.EntityKind{lint.EntityCodeSystem, lint.EntityExtension, lint.EntityInstance, lint.EntityProfile, lint.EntityValueSet},
+		Fixable:     true,
+		UnsafeFix:   true,
+	}
+}
+
 // Validate returns a *lint.Problem if the value at FieldPath is missing. An error will
 // be returned if the FieldPath is an invalid path.
 func (r *RequiredFieldPresentRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
@@ -63,6 +100,10 @@ func (r *RequiredFieldPresentRule) Validate(fc *lint.FileContext) ([]*lint.Probl
 
 	// Split the fieldPath into components.
 	pathParts := strings.Split(r.FieldPath, ".")
+	if _, ok := metadataOrder[pathParts[0]]; ok && len(pathParts) == 2 {
+		return r.validateEntities(fc, pathParts[0], pathParts[1])
+	}
+
 	fshDoc := *fc.ParsedFSH
 	val := reflect.ValueOf(fshDoc)
 	valid, err := checkFieldValidity(pathParts, val)
@@ -80,6 +121,64 @@ func (r *RequiredFieldPresentRule) Validate(fc *lint.FileContext) ([]*lint.Probl
 	return []*lint.Problem{p}, nil
 }
 
+// validateEntities returns a *lint.Problem located at the name of each entity
+// of the kind that is missing the field. Missing metadata that can be derived
+// from the name, such as the ID and title, is fixed by inserting it in the
+// canonical metadata order.
+func (r *RequiredFieldPresentRule) validateEntities(fc *lint.FileContext, kind, field string) ([]*lint.Problem, error) {
+	entities := reflect.ValueOf(fc.ParsedFSH).Elem().FieldByName(kind)
+	entityType := entities.Type().Elem().Elem()
+	if _, ok := entityType.FieldByName(field); !ok {
+		return nil, fmt.Errorf("%w: Field '%s' is not a field of struct '%s'", ErrInvalidField, field, entityType)
+	}
+
+	var problems []*lint.Problem
+	for i := range entities.Len() {
+		entity := entities.Index(i)
+		if entity.IsNil() || !isNilOrEmpty(entity.Elem().FieldByName(field)) {
+			continue
+		}
+
+		var location *ast.Location
+		name, _ := entity.Elem().FieldByName("Name").Interface().(*ast.ParsedElement[string])
+		if name != nil {
+			location = name.Location
+		}
+
+		p, err := lint.NewProblem(r.ID(), r.Message(), location, nil, false)
+		if edit, value, ok := r.insertionFix(fc.Data, entity, kind, field, name); ok {
+			diff := &lint.Diff{Want: value, FieldName: r.FieldName}
+			p, err = lint.NewProblemWithEdits(r.ID(), r.Message(), location, diff, []*lint.TextEdit{edit})
+			if err == nil {
+				// Setting the ID changes the canonical URL, which breaks references.
+				p.UnsafeFix = field == "ID"
+			}
+		}
+		if err != nil {
+			return nil, err
+		}
+		problems = append(problems, p)
+	}
+	return problems, nil
+}
+
+// insertionFix returns the edit that inserts the missing metadata field of the
+// entity after the metadata that precede it, and the inserted value. Returns
+// false if the field is set, or cannot be derived from the name.
+func (r *RequiredFieldPresentRule) insertionFix(data []byte, entity reflect.Value, kind, field string, name *ast.ParsedElement[string]) (*lint.TextEdit, string, bool) {
+	derive, ok := metadataValues[field]
+	if !ok || name == nil || name.Value == "" || !entity.Elem().FieldByName(field).IsNil() {
+		return nil, "", false
+	}
+	line := metadataEnd(entity, kind, field)
+	if line == 0 {
+		return nil, "", false
+	}
+	value := derive(kind, name.Value)
+	edit, ok := insertLineEdit(data, line, fmt.Sprintf("%s: %s", metadataKeywords[field], value))
+	return edit, value, ok
+}
+
 // checkFieldValidity returns true if the given field in pathParts, that is, the last
 // element in pathParts is not nil or empty. pathParts[0] should be the next field
 // to be accessed in val. If pathParts[i] is a slice/array or pointer, then checkFieldValidity
@@ -176,7 +275,7 @@ func isNilOrEmpty(val reflect.Value) bool {
 	//    &lint.RequiredFieldPresentRule{FieldPath: "Profiles.Description"} and
 	//    &lint.RequiredFieldPresentRule{FieldPath: "Profiles.Description.Value"}
 	if val.Type() == parsedElementStringType {
-		parsedElement := val.Interface().(*types.ParsedElement[string])
+		parsedElement := val.Interface().(*ast.ParsedElement[string])
 		return parsedElement.Value == ""
 	}
 
@@ -5,7 +5,8 @@ import (
 	"testing"
 
 	"github.com/google/go-cmp/cmp"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic/diagnostictest"
 	"github.com/verily-src/fsh-lint/lint"
 	"github.com/verily-src/fsh-lint/rules"
 )
@@ -14,16 +15,16 @@ func TestRequiredFieldPresent(t *testing.T) {
 	tests := []struct {
 		name        string
 		fieldPath   string
-		fshDocument *types.FSHDocument
+		fshDocument *ast.FSHDocument
 		wantProblem bool
 	}{
 		{
 			name:      "Path points to a *ParsedElement[string], and set to nil",
 			fieldPath: "Profiles.ID",
-			fshDocument: &types.FSHDocument{
-				Profiles: []*types.Profile{
+			fshDocument: &ast.FSHDocument{
+				Profiles: []*ast.Profile{
 					{
-						Name: &types.ParsedElement[string]{
+						Name: &ast.ParsedElement[string]{
 							Value: "Profile",
 						},
 					},
@@ -34,13 +35,13 @@ func TestRequiredFieldPresent(t *testing.T) {
 		{
 			name:      "Path points to a *ParsedElement[string], and not nil",
 			fieldPath: "Profiles.ID",
-			fshDocument: &types.FSHDocument{
-				Profiles: []*types.Profile{
+			fshDocument: &ast.FSHDocument{
+				Profiles: []*ast.Profile{
 					{
-						Name: &types.ParsedElement[string]{
+						Name: &ast.ParsedElement[string]{
 							Value: "Profile",
 						},
-						ID: &types.ParsedElement[string]{
+						ID: &ast.ParsedElement[string]{
 							Value: "profile",
 						},
 					},
@@ -52,13 +53,13 @@ func TestRequiredFieldPresent(t *testing.T) {
 		{
 			name:      "Path points to a *ParsedElement[string], and value is set to empty string",
 			fieldPath: "Profiles.ID",
-			fshDocument: &types.FSHDocument{
-				Profiles: []*types.Profile{
+			fshDocument: &ast.FSHDocument{
+				Profiles: []*ast.Profile{
 					{
-						Name: &types.ParsedElement[string]{
+						Name: &ast.ParsedElement[string]{
 							Value: "Profile",
 						},
-						ID: &types.ParsedElement[string]{
+						ID: &ast.ParsedElement[string]{
 							Value: "",
 						},
 					},
@@ -69,13 +70,13 @@ func TestRequiredFieldPresent(t *testing.T) {
 		{
 			name:      "Path points to a *ParsedElement[string], and value is set to non-empty string",
 			fieldPath: "Profiles.ID",
-			fshDocument: &types.FSHDocument{
-				Profiles: []*types.Profile{
+			fshDocument: &ast.FSHDocument{
+				Profiles: []*ast.Profile{
 					{
-						Name: &types.ParsedElement[string]{
+						Name: &ast.ParsedElement[string]{
 							Value: "Profile",
 						},
-						ID: &types.ParsedElement[string]{
+						ID: &ast.ParsedElement[string]{
 							Value: "profile",
 						},
 					},
@@ -86,10 +87,10 @@ func TestRequiredFieldPresent(t *testing.T) {
 		{
 			name:      "Path points to a string set to empty",
 			fieldPath: "ValueSets.Description.Value",
-			fshDocument: &types.FSHDocument{
-				ValueSets: []*types.ValueSet{
+			fshDocument: &ast.FSHDocument{
+				ValueSets: []*ast.ValueSet{
 					{
-						Description: &types.ParsedElement[string]{
+						Description: &ast.ParsedElement[string]{
 							Value: "",
 						},
 					},
@@ -100,10 +101,10 @@ func TestRequiredFieldPresent(t *testing.T) {
 		{
 			name:      "Path points to a string set to non-empty",
 			fieldPath: "ValueSets.Description.Value",
-			fshDocument: &types.FSHDocument{
-				ValueSets: []*types.ValueSet{
+			fshDocument: &ast.FSHDocument{
+				ValueSets: []*ast.ValueSet{
 					{
-						Description: &types.ParsedElement[string]{
+						Description: &ast.ParsedElement[string]{
 							Value: "Description.",
 						},
 					},
@@ -114,10 +115,10 @@ func TestRequiredFieldPresent(t *testing.T) {
 		{
 			name:      "Path points to an empty slice",
 			fieldPath: "ValueSets.IncludeComponents",
-			fshDocument: &types.FSHDocument{
-				ValueSets: []*types.ValueSet{
+			fshDocument: &ast.FSHDocument{
+				ValueSets: []*ast.ValueSet{
 					{
-						IncludeComponents: []*types.ValueSetComponent{},
+						IncludeComponents: []*ast.ValueSetComponent{},
 					},
 				},
 			},
@@ -126,10 +127,10 @@ func TestRequiredFieldPresent(t *testing.T) {
 		{
 			name:      "Path points to a non empty slice",
 			fieldPath: "ValueSets.IncludeComponents",
-			fshDocument: &types.FSHDocument{
-				ValueSets: []*types.ValueSet{
+			fshDocument: &ast.FSHDocument{
+				ValueSets: []*ast.ValueSet{
 					{
-						IncludeComponents: []*types.ValueSetComponent{
+						IncludeComponents: []*ast.ValueSetComponent{
 							{},
 						},
 					},
@@ -162,16 +163,16 @@ func TestRequiredFieldPresentConfigurationErrors(t *testing.T) {
 	tests := []struct {
 		name        string
 		fieldPath   string
-		fshDocument *types.FSHDocument
+		fshDocument *ast.FSHDocument
 		wantError   error
 	}{
 		{
 			name:      "empty field path",
 			fieldPath: "",
-			fshDocument: &types.FSHDocument{
-				Profiles: []*types.Profile{
+			fshDocument: &ast.FSHDocument{
+				Profiles: []*ast.Profile{
 					{
-						Name: &types.ParsedElement[string]{
+						Name: &ast.ParsedElement[string]{
 							Value: "Profile",
 						},
 					},
@@ -182,8 +183,8 @@ func TestRequiredFieldPresentConfigurationErrors(t *testing.T) {
 		{
 			name:      "invalid field",
 			fieldPath: "Profiles.NotARealField",
-			fshDocument: &types.FSHDocument{
-				Profiles: []*types.Profile{
+			fshDocument: &ast.FSHDocument{
+				Profiles: []*ast.Profile{
 					{},
 				},
 			},
@@ -192,10 +193,10 @@ func TestRequiredFieldPresentConfigurationErrors(t *testing.T) {
 		{
 			name:      "field not recursable anymore",
 			fieldPath: "Profiles.Name.Value.TooFarIntoRecursion",
-			fshDocument: &types.FSHDocument{
-				Profiles: []*types.Profile{
+			fshDocument: &ast.FSHDocument{
+				Profiles: []*ast.Profile{
 					{
-						Name: &types.ParsedElement[string]{
+						Name: &ast.ParsedElement[string]{
 							Value: "Profile",
 						},
 					},
@@ -217,3 +218,102 @@ func TestRequiredFieldPresentConfigurationErrors(t *testing.T) {
 		})
 	}
 }
+
+func TestRequiredRules_Fix(t *testing.T) {
+	tests := []struct {
+		name      string
+		fsh       string
+		fixUnsafe bool
+		want      string
+	}{
+		{
+			name:      "missing metadata and assignments are inserted in order",
+			fixUnsafe: true,
+			fsh:       "Profile: ExampleProfile\nParent: Patient\nDescription: \"\"\"Multi\nline\"\"\"\n* name 1..1\n",
+			want:      "Profile: ExampleProfile\nParent: Patient\nId: example-profile\nTitle: \"Example Profile\"\nDescription: \"\"\"Multi\nline\"\"\"\n* ^status = #draft\n* ^abstract = false\n* name 1..1\n",
+		},
+		{
+			name:      "assignments are inserted after other assignments",
+			fixUnsafe: true,
+			fsh:       "ValueSet: ExampleCodes\r\nTitle: \"Example Codes\"\r\n\r\nProfile: Example\r\nId: example\r\nTitle: \"Example\"\r\n* name 1..1\r\n* ^abstract = true",
+			want:      "ValueSet: ExampleCodes\r\nId: example-codes\r\nTitle: \"Example Codes\"\r\n\r\nProfile: Example\r\nId: example\r\nTitle: \"Example\"\r\n* name 1..1\r\n* ^abstract = true\r\n* ^status = #draft",
+		},
+		{
+			name: "IDs are only inserted with unsafe fixes",
+			fsh:  "ValueSet: ExampleCodes\n* include codes from system Example\n",
+			want: "ValueSet: ExampleCodes\nTitle: \"Example Codes\"\n* include codes from system Example\n",
+		},
+		{
+			name: "empty values are not fixed",
+			fsh:  "CodeSystem: Example\nId: \"\"\nTitle: \"Example\"\n",
+			want: "CodeSystem: Example\nId: \"\"\nTitle: \"Example\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter, _ := diagnostictest.NewFakeReporter()
+			linter := lint.NewLinter(rules.Required(), nil)
+			linter.Reporter = reporter
+			linter.Fix = true
+			linter.FixUnsafe = tt.fixUnsafe
+
+			got := linter.LintData("Example.fsh", []byte(tt.fsh))
+			if diff := cmp.Diff(string(got), tt.want); diff != "" {
+				t.Errorf("LintData() mismatch (-got +want):\n%s", diff)
+			}
+		})
+	}
+}
+
+func TestRequiredRules_FixMatchesNameRules(t *testing.T) {
+	tests := []struct {
+		name string
+		fsh  string
+		want string
+	}{
+		{
+			name: "profile",
+			fsh:  "Profile: ExampleThing\nParent: Patient\n* ^status = #draft\n* ^abstract = false\n",
+			want: "Profile: ExampleThing\nParent: Patient\nId: example-thing\nTitle: \"Example Thing Profile\"\n* ^status = #draft\n* ^abstract = false\n",
+		},
+		{
+			name: "acronym",
+			fsh:  "ValueSet: HTTPStatus\n",
+			want: "ValueSet: HTTPStatus\nId: http-status\nTitle: \"HTTP Status\"\n",
+		},
+		{
+			name: "acronym and number",
+			fsh:  "CodeSystem: USCoreFoo2\n",
+			want: "CodeSystem: USCoreFoo2\nId: us-core-foo-2\nTitle: \"US Core Foo 2\"\n",
+		},
+	}
+
+	for _, tt := range tests {
+		t.Run(tt.name, func(t *testing.T) {
+			reporter, _ := diagnostictest.NewFakeReporter()
+			linter := lint.NewLinter(rules.Required(), []lint.Rule{
+				&rules.ProfileNameMatchesIDRule{},
+				&rules.ProfileNameMatchesTitleRule{},
+				&rules.ValueSetNameMatchesIDRule{},
+				&rules.ValueSetNameMatchesTitleRule{},
+				&rules.CodeSystemNameMatchesIDRule{},
+				&rules.CodeSystemNameMatchesTitleRule{},
+			})
+			linter.Reporter = reporter
+			linter.Fix = true
+			linter.FixUnsafe = true
+
+			got := linter.LintData("Example.fsh", []byte(tt.fsh))
+			if diff := cmp.Diff(string(got), tt.want); diff != "" {
+				t.Errorf("LintData() mismatch (-got +want):\n%s", diff)
+			}
+			// The inserted values are final, so the name rules fix nothing.
+			for _, rs := range reporter.Summary().Rules {
+				if rs.Rule != "required-field-present" && rs.Fixed > 0 {
+					t.Errorf("LintData() fixed %d %s problems, want 0", rs.Fixed, rs.Rule)
+				}
+			}
+		})
+	}
+}
@@ -16,6 +16,18 @@ func (r *TemplateRule) Message() string {
 	return "Message displayed when this rule is violated."
 }
 
+// Metadata() returns the documentation metadata for this rule.
+func (r *TemplateRule) Metadata() *lint.Metadata {
+	return &lint.Metadata{
+		Category:    lint.CategoryProfile, // Change this to the category the rule belongs to
+		Description: "Markdown description of what this rule checks.",
+		Examples: []lint.Example{
+			{FSH: "Profile: Example", Good: true},
+		},
+		EntityKinds: []lint.EntityKind{lint.EntityProfile},
+	}
+}
+
 // Validate returns a *lint.Problem for each rule violation found.
 func (r *TemplateRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
 	var problems []*lint.Problem
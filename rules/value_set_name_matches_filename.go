@@ -5,7 +5,7 @@ import (
 	"path/filepath"
 	"strings"
 
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/lint"
 )
 
@@ -29,6 +29,32 @@ func (r *ValueSetNameMatchesFilenameRule) Message() string {
 	return fmt.Sprintf("Value set name must match filename (where filename does not include %s).", r.NameSuffix)
 }
 
+// Metadata() returns the documentation metadata for this rule.
+func (*ValueSetNameMatchesFilenameRule) Metadata() *lint.Metadata {
+	return &lint.Metadata{
+		Category: lint.CategoryValueSet,
+		Description: "Value Set name must match filename (where filename does not include the name suffix, e.g. `_VS`). This match is case sensitive, and the file extension must be `.fsh`.\n\n" +
+			"For example, value set name `Example_VS` **matches** `Example.fsh`, but **does not match** `Example_VS.fsh`, `example.fsh`, or `Example.txt`.",
+		Examples: []lint.Example{
+			{
+				Description: "In a file named `Example.fsh`:",
+				FSH: `ValueSet: Example_VS
+Id: example
+Title: "Example"`,
+				Good: true,
+			},
+			{
+				Description: "In a file named `Example_VS.fsh`:",
+				FSH: `ValueSet: Example_VS
+Id: example
+Title: "Example"`,
+				Good: false,
+			},
+		},
+		EntityKinds: []lint.EntityKind{lint.EntityValueSet},
+	}
+}
+
 // Validate returns a *lint.Problem for each value set name that does not match the filename.
 func (r *ValueSetNameMatchesFilenameRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
 	var problems []*lint.Problem
@@ -46,7 +72,7 @@ func (r *ValueSetNameMatchesFilenameRule) Validate(fc *lint.FileContext) ([]*lin
 
 // ValueSetNameMatchesFilenameViolation returns nil if the value set name with the NameSuffix removed
 // matches the filename, and a *lint.Problem otherwise.
-func ValueSetNameMatchesFilenameViolation(vs *types.ValueSet, fp string, nameSuffix string, id string, msg string) (*lint.Problem, error) {
+func ValueSetNameMatchesFilenameViolation(vs *ast.ValueSet, fp string, nameSuffix string, id string, msg string) (*lint.Problem, error) {
 	filename := filepath.Base(fp)
 	trimmedName := strings.TrimSuffix(vs.Name.Value, nameSuffix)
 	if strings.TrimSuffix(filename, ".fsh") != trimmedName {
@@ -5,7 +5,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/lint"
 	"github.com/verily-src/fsh-lint/rules"
 )
@@ -14,12 +14,12 @@ func TestLintValueSetNameMatchesFilename(t *testing.T) {
 	sut := rules.ValueSetNameMatchesFilenameRule{NameSuffix: "_VS"}
 	ruleName := "ValueSetNameMatchesFilenameRule"
 
-	testLocation := &types.Location{
-		Start: &types.Position{
+	testLocation := &ast.Location{
+		Start: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 2,
 		},
-		End: &types.Position{
+		End: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 4,
 		},
@@ -33,8 +33,8 @@ func TestLintValueSetNameMatchesFilename(t *testing.T) {
 		{
 			name: "no value sets",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					ValueSets: []*types.ValueSet{}},
+				ParsedFSH: &ast.FSHDocument{
+					ValueSets: []*ast.ValueSet{}},
 				Path: "path/to/ValueSet.fsh",
 			},
 			want: []*lint.Problem{},
@@ -42,10 +42,10 @@ func TestLintValueSetNameMatchesFilename(t *testing.T) {
 		{
 			name: "exact match",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					ValueSets: []*types.ValueSet{
+				ParsedFSH: &ast.FSHDocument{
+					ValueSets: []*ast.ValueSet{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleValueSet",
 								Location: testLocation,
 							},
@@ -59,10 +59,10 @@ func TestLintValueSetNameMatchesFilename(t *testing.T) {
 		{
 			name: "correct match no path",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					ValueSets: []*types.ValueSet{
+				ParsedFSH: &ast.FSHDocument{
+					ValueSets: []*ast.ValueSet{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleValueSet_VS",
 								Location: testLocation,
 							},
@@ -76,10 +76,10 @@ func TestLintValueSetNameMatchesFilename(t *testing.T) {
 		{
 			name: "correct match, path in root",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					ValueSets: []*types.ValueSet{
+				ParsedFSH: &ast.FSHDocument{
+					ValueSets: []*ast.ValueSet{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleValueSet_VS",
 								Location: testLocation,
 							},
@@ -93,10 +93,10 @@ func TestLintValueSetNameMatchesFilename(t *testing.T) {
 		{
 			name: "not match",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					ValueSets: []*types.ValueSet{
+				ParsedFSH: &ast.FSHDocument{
+					ValueSets: []*ast.ValueSet{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleValueSetOne_VS",
 								Location: testLocation,
 							},
@@ -122,10 +122,10 @@ func TestLintValueSetNameMatchesFilename(t *testing.T) {
 		{
 			name: "empty name",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					ValueSets: []*types.ValueSet{
+				ParsedFSH: &ast.FSHDocument{
+					ValueSets: []*ast.ValueSet{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "",
 								Location: testLocation,
 							},
@@ -151,16 +151,16 @@ func TestLintValueSetNameMatchesFilename(t *testing.T) {
 		{
 			name: "one matching, one non matching",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					ValueSets: []*types.ValueSet{
+				ParsedFSH: &ast.FSHDocument{
+					ValueSets: []*ast.ValueSet{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleValueSetOne_VS",
 								Location: testLocation,
 							},
 						},
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleValueSetTwo_VS",
 								Location: testLocation,
 							},
@@ -186,16 +186,16 @@ func TestLintValueSetNameMatchesFilename(t *testing.T) {
 		{
 			name: "two non matching",
 			fileContext: lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
-					ValueSets: []*types.ValueSet{
+				ParsedFSH: &ast.FSHDocument{
+					ValueSets: []*ast.ValueSet{
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleValueSetOne",
 								Location: testLocation,
 							},
 						},
 						{
-							Name: &types.ParsedElement[string]{
+							Name: &ast.ParsedElement[string]{
 								Value:    "ExampleValueSetOne_VS",
 								Location: testLocation,
 							},
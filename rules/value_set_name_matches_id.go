@@ -5,7 +5,7 @@ import (
 	"strings"
 
 	"github.com/iancoleman/strcase"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/internal/match"
 	"github.com/verily-src/fsh-lint/lint"
 )
@@ -30,12 +30,42 @@ func (r *ValueSetNameMatchesIDRule) Message() string {
 	return fmt.Sprintf("Value set name (PascalCase) must match value set ID in kebab-case without the %s suffix.", r.NameSuffix)
 }
 
+// Metadata() returns the documentation metadata for this rule.
+func (*ValueSetNameMatchesIDRule) Metadata() *lint.Metadata {
+	return &lint.Metadata{
+		Category: lint.CategoryValueSet,
+		Description: "Value set name (PascalCase) must match value set id in kebab-case without the name suffix (e.g. `_VS`).\n\n" +
+			"If there are numbers in the value set name, **any grouping with its adjacent alphabetic characters is acceptable**. In other words, hyphens in the id are optional when adjacent to a number. This flexible hyphenation applies only to numbers.\n\n" +
+			"If there are acronyms in the value set name, the acronym should be grouped as one word. Note, that the first letter of a word following an acronym should be capitalized in the PascalCase name.\n\n" +
+			"Note, the \"Want\" value that is generated, is one of the correct configurations where each group of numbers is grouped as their own separate word. This is the behavior of [strcase.ToDelimited](https://pkg.go.dev/github.com/iancoleman/strcase#ToDelimited).\n\n" +
+			"For example:\n\n" +
+			"- Value set name `Example_VS` **matches** id `example`, but **does not match** `example-vs` or `Example`.\n" +
+			"- Value set name `A123Example_VS` **matches** id `a-123-example`, `a123example`, `a1-23example`, and several others.\n" +
+			"- Value set name `ABCExample_VS` **matches** id `abc-example`, but **does not match** `a-b-c-example` or `abce-xample`.",
+		Examples: []lint.Example{
+			{
+				FSH: `ValueSet: Example_VS
+Id: example`,
+				Good: true,
+			},
+			{
+				FSH: `ValueSet: Example_VS
+Id: example-vs`,
+				Good: false,
+			},
+		},
+		EntityKinds: []lint.EntityKind{lint.EntityValueSet},
+		Fixable:     true,
+		UnsafeFix:   true,
+	}
+}
+
 // Validate returns a *lint.Problem for each value set name that does not match
 // its corresponding ID without the NameSuffix.
 func (r *ValueSetNameMatchesIDRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
 	var problems []*lint.Problem
 	for _, vs := range fc.ParsedFSH.ValueSets {
-		p, err := valueSetNameMatchesIDViolation(vs, r.NameSuffix, r.ID(), r.Message())
+		p, err := valueSetNameMatchesIDViolation(fc.Data, vs, r.NameSuffix, r.ID(), r.Message())
 		if err != nil {
 			return nil, err
 		}
@@ -48,7 +78,7 @@ func (r *ValueSetNameMatchesIDRule) Validate(fc *lint.FileContext) ([]*lint.Prob
 
 // valueSetNameMatchesIDViolation returns nil if the value set name with the NameSuffix removed
 // matches the ID in kebab-case, and a *lint.Problem otherwise.
-func valueSetNameMatchesIDViolation(vs *types.ValueSet, nameSuffix string, id string, msg string) (*lint.Problem, error) {
+func valueSetNameMatchesIDViolation(data []byte, vs *ast.ValueSet, nameSuffix string, id string, msg string) (*lint.Problem, error) {
 	trimmedName := strings.TrimSuffix(vs.Name.Value, nameSuffix)
 	if !match.IsNameKebabMatchWithID(trimmedName, vs.ID.Value, true) {
 		// Value Set Name does not match ID
@@ -58,7 +88,7 @@ func valueSetNameMatchesIDViolation(vs *types.ValueSet, nameSuffix string, id st
 			FieldName: "Value Set ID",
 		}
 
-		return lint.NewProblem(id, msg, vs.ID.Location, diff, true)
+		return renameValueProblem(data, id, msg, vs.ID, diff)
 	}
 	return nil, nil
 }
@@ -5,7 +5,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/lint"
 	"github.com/verily-src/fsh-lint/rules"
 )
@@ -14,12 +14,12 @@ func TestLintValueSetNameMatchesID(t *testing.T) {
 	sut := rules.ValueSetNameMatchesIDRule{NameSuffix: "_VS"}
 	ruleName := "ValueSetNameMatchesIDRule"
 
-	testLocation := &types.Location{
-		Start: &types.Position{
+	testLocation := &ast.Location{
+		Start: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 2,
 		},
-		End: &types.Position{
+		End: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 4,
 		},
@@ -27,38 +27,38 @@ func TestLintValueSetNameMatchesID(t *testing.T) {
 
 	tests := []struct {
 		name      string
-		valueSets []*types.ValueSet
+		valueSets []*ast.ValueSet
 		want      []*lint.Problem
 	}{
 		{
 			name:      "no value sets",
-			valueSets: []*types.ValueSet{},
+			valueSets: []*ast.ValueSet{},
 			want:      nil,
 		},
 		{
 			name: "name with _VS matches id",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("ValueSet_VS", "value-set", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "name without _VS matches id",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("ValueSet", "value-set", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "one word match",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("Example", "example", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "id different text",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("ValueSetOne", "value-set-two", testLocation),
 			},
 			want: []*lint.Problem{
@@ -71,13 +71,12 @@ func TestLintValueSetNameMatchesID(t *testing.T) {
 						Want:      "value-set-one",
 						FieldName: "Value Set ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "id incorrect case",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("ExampleValueSet", "Example-Value-Set", testLocation),
 			},
 			want: []*lint.Problem{
@@ -90,13 +89,12 @@ func TestLintValueSetNameMatchesID(t *testing.T) {
 						Want:      "example-value-set",
 						FieldName: "Value Set ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "id incorrect kebab style",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("ExampleValueSet", "examplevalue-set", testLocation),
 			},
 			want: []*lint.Problem{
@@ -109,13 +107,12 @@ func TestLintValueSetNameMatchesID(t *testing.T) {
 						Want:      "example-value-set",
 						FieldName: "Value Set ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "empty id",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("ExampleValueSet_VS", "", testLocation),
 			},
 			want: []*lint.Problem{
@@ -128,13 +125,12 @@ func TestLintValueSetNameMatchesID(t *testing.T) {
 						Want:      "example-value-set",
 						FieldName: "Value Set ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "one matching, one non matching",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("ValueSetOne_VS", "value-set-one", testLocation),
 				createTestValueSetWithNameAndID("ValueSetOne_VS", "value-set-one-vs", testLocation),
 			},
@@ -148,13 +144,12 @@ func TestLintValueSetNameMatchesID(t *testing.T) {
 						Want:      "value-set-one",
 						FieldName: "Value Set ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "two non matching",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("ValueSetOne_VS", "value-set-three", testLocation),
 				createTestValueSetWithNameAndID("ValueSetTwo_VS", "value-set-three", testLocation),
 			},
@@ -168,7 +163,6 @@ func TestLintValueSetNameMatchesID(t *testing.T) {
 						Want:      "value-set-one",
 						FieldName: "Value Set ID",
 					},
-					IsFixable: true,
 				},
 				{
 					RuleID:   sut.ID(),
@@ -179,69 +173,68 @@ func TestLintValueSetNameMatchesID(t *testing.T) {
 						Want:      "value-set-two",
 						FieldName: "Value Set ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "no hyphen after number",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("123Abc", "123abc", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no hyphen before number",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("Abc123", "abc123", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no hyphens around numbers",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("A123B", "a123b", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no hyphen numbers before or after",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("123Abc456", "123abc456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "one hyphen after none before",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("123Abc456", "123-abc456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "one hyphen before none after",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("123Abc456", "123abc-456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "hyphens before and after",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("123Abc456", "123-abc-456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "extra hyphens between numbers",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("1234abc", "1-2-34abc", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "double hyphen should not pass",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("12", "1--2", testLocation),
 			},
 			want: []*lint.Problem{
@@ -254,13 +247,12 @@ func TestLintValueSetNameMatchesID(t *testing.T) {
 						Want:      "12",
 						FieldName: "Value Set ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "should not start with a hyphen",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("1Abc", "-1abc", testLocation),
 			},
 			want: []*lint.Problem{
@@ -273,13 +265,12 @@ func TestLintValueSetNameMatchesID(t *testing.T) {
 						Want:      "1-abc",
 						FieldName: "Value Set ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "should not end with a hyphen",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndID("Abc1", "Abc1-", testLocation),
 			},
 			want: []*lint.Problem{
@@ -292,7 +283,6 @@ func TestLintValueSetNameMatchesID(t *testing.T) {
 						Want:      "abc-1",
 						FieldName: "Value Set ID",
 					},
-					IsFixable: true,
 				},
 			},
 		},
@@ -300,8 +290,9 @@ func TestLintValueSetNameMatchesID(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// The problems are not fixable without the data of the file.
 			fc := &lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
+				ParsedFSH: &ast.FSHDocument{
 					ValueSets: tt.valueSets},
 			}
 
@@ -317,13 +308,13 @@ func TestLintValueSetNameMatchesID(t *testing.T) {
 	}
 }
 
-func createTestValueSetWithNameAndID(name string, id string, testLocation *types.Location) *types.ValueSet {
-	return &types.ValueSet{
-		Name: &types.ParsedElement[string]{
+func createTestValueSetWithNameAndID(name string, id string, testLocation *ast.Location) *ast.ValueSet {
+	return &ast.ValueSet{
+		Name: &ast.ParsedElement[string]{
 			Value:    name,
 			Location: testLocation,
 		},
-		ID: &types.ParsedElement[string]{
+		ID: &ast.ParsedElement[string]{
 			Value:    id,
 			Location: testLocation,
 		},
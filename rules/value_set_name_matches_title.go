@@ -4,11 +4,7 @@ import (
 	"fmt"
 	"strings"
 
-	"github.com/iancoleman/strcase"
-	"golang.org/x/text/cases"
-	"golang.org/x/text/language"
-
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/internal/match"
 	"github.com/verily-src/fsh-lint/lint"
 )
@@ -33,12 +29,41 @@ func (r *ValueSetNameMatchesTitleRule) Message() string {
 	return fmt.Sprintf("Value set name (PascalCase) must match value set title in Title Case without the %s suffix.", r.NameSuffix)
 }
 
+// Metadata() returns the documentation metadata for this rule.
+func (*ValueSetNameMatchesTitleRule) Metadata() *lint.Metadata {
+	return &lint.Metadata{
+		Category: lint.CategoryValueSet,
+		Description: "Value Set name without the name suffix (e.g. `_VS`) in PascalCase must match Value Set title in Title Case (space separated). This check is **case insensitive**.\n\n" +
+			"If there are numbers in the value set name, **any grouping with its adjacent alphabetic characters is acceptable**. In other words, the space is optional in the title when adjacent to a number. This flexibility applies only to numbers.\n\n" +
+			"If there are acronyms in the value set name, the acronym should be grouped as one word. Note, that the first letter of a word following an acronym should be capitalized in the PascalCase name.\n\n" +
+			"Note, the \"Want\" value that is generated, is one of the correct configurations where each group of numbers is grouped as their own separate word. This is the behavior of [strcase.ToDelimited](https://pkg.go.dev/github.com/iancoleman/strcase#ToDelimited).\n\n" +
+			"For example:\n\n" +
+			"- Value set name `ExampleOne_VS` **matches** title `Example One` and `example one`, but **does not match** `Example One VS` or `ExampleOne`.\n" +
+			"- Value set name `A123Example_VS` **matches** title `A 123 Example`, `A123example`, `A1 23example`, and several others.\n" +
+			"- Value set name `ABCExample_VS` **matches** title `ABC Example`, but **does not match** `A B C Example` or `Abce Xample`.",
+		Examples: []lint.Example{
+			{
+				FSH: `ValueSet: ExampleOne_VS
+Title: "Example One"`,
+				Good: true,
+			},
+			{
+				FSH: `ValueSet: ExampleOne_VS
+Title: "Example One VS"`,
+				Good: false,
+			},
+		},
+		EntityKinds: []lint.EntityKind{lint.EntityValueSet},
+		Fixable:     true,
+	}
+}
+
 // Validate returns a *lint.Problem for each value set name without the NameSuffix
 // that does not match its corresponding title.
 func (r *ValueSetNameMatchesTitleRule) Validate(fc *lint.FileContext) ([]*lint.Problem, error) {
 	var problems []*lint.Problem
 	for _, vs := range fc.ParsedFSH.ValueSets {
-		p, err := ValueSetNameMatchesTitleViolation(vs, r.NameSuffix, r.ID(), r.Message())
+		p, err := ValueSetNameMatchesTitleViolation(fc.Data, vs, r.NameSuffix, r.ID(), r.Message())
 		if err != nil {
 			return nil, err
 		}
@@ -51,19 +76,17 @@ func (r *ValueSetNameMatchesTitleRule) Validate(fc *lint.FileContext) ([]*lint.P
 
 // ValueSetNameMatchesTitleViolation returns nil if the value set name without the NameSuffix matches title in
 // Title Case, and a *lint.Problem otherwise.
-func ValueSetNameMatchesTitleViolation(vs *types.ValueSet, nameSuffix string, id string, msg string) (*lint.Problem, error) {
+func ValueSetNameMatchesTitleViolation(data []byte, vs *ast.ValueSet, nameSuffix string, id string, msg string) (*lint.Problem, error) {
 	trimmedName := strings.TrimSuffix(vs.Name.Value, nameSuffix)
-	spaceSeparatedName := strcase.ToDelimited(trimmedName, ' ')
 
 	if !match.IsNameMatchWithTitle(trimmedName, vs.Title.Value, true) {
-		caser := cases.Title(language.AmericanEnglish)
 		diff := &lint.Diff{
 			Got:       vs.Title.Value,
-			Want:      caser.String(spaceSeparatedName),
+			Want:      match.NameToTitle(trimmedName),
 			FieldName: "Value Set Title",
 		}
 
-		return lint.NewProblem(id, msg, vs.Title.Location, diff, true)
+		return replaceValueProblem(data, id, msg, vs.Title, diff)
 	}
 	return nil, nil
 }
@@ -5,7 +5,7 @@ import (
 
 	"github.com/google/go-cmp/cmp"
 	"github.com/google/go-cmp/cmp/cmpopts"
-	"github.com/verily-src/fsh-lint/internal/fsh/types"
+	"github.com/verily-src/fsh-lint/ast"
 	"github.com/verily-src/fsh-lint/lint"
 	"github.com/verily-src/fsh-lint/rules"
 )
@@ -14,12 +14,12 @@ func TestLintValueSetNameMatchesTitle(t *testing.T) {
 	sut := rules.ValueSetNameMatchesTitleRule{NameSuffix: "_VS"}
 	ruleName := "ValueSetNameMatchesTitleRule"
 
-	testLocation := &types.Location{
-		Start: &types.Position{
+	testLocation := &ast.Location{
+		Start: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 2,
 		},
-		End: &types.Position{
+		End: &ast.Position{
 			LineNumber:   1,
 			ColumnNumber: 4,
 		},
@@ -27,38 +27,38 @@ func TestLintValueSetNameMatchesTitle(t *testing.T) {
 
 	tests := []struct {
 		name      string
-		valueSets []*types.ValueSet
+		valueSets []*ast.ValueSet
 		want      []*lint.Problem
 	}{
 		{
 			name:      "no value sets",
-			valueSets: []*types.ValueSet{},
+			valueSets: []*ast.ValueSet{},
 			want:      nil,
 		},
 		{
 			name: "name matches title with _VS",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("ExampleTest_VS", "Example Test", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "name matches title without _VS",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("ExampleTest", "Example Test", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "name matches title with acronym",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("ExampleTestABC_VS", "Example Test ABC", nil),
 			},
 			want: nil,
 		},
 		{
 			name: "title is the same but adds '_VS' at the end",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("ExampleName_VS", "Example Name VS", testLocation),
 			},
 			want: []*lint.Problem{
@@ -71,13 +71,12 @@ func TestLintValueSetNameMatchesTitle(t *testing.T) {
 						Want:      "Example Name",
 						FieldName: "Value Set Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "incorrect title spacing",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("ExampleName", "Example   Name", testLocation),
 			},
 			want: []*lint.Problem{
@@ -90,13 +89,12 @@ func TestLintValueSetNameMatchesTitle(t *testing.T) {
 						Want:      "Example Name",
 						FieldName: "Value Set Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "empty title",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("ExampleName", "", testLocation),
 			},
 			want: []*lint.Problem{
@@ -109,13 +107,12 @@ func TestLintValueSetNameMatchesTitle(t *testing.T) {
 						Want:      "Example Name",
 						FieldName: "Value Set Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "one matching, one non matching",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("ExampleOne", "Example Two", testLocation),
 				createTestValueSetWithNameAndTitle("ExampleTwo", "Example Two", testLocation),
 			},
@@ -129,13 +126,12 @@ func TestLintValueSetNameMatchesTitle(t *testing.T) {
 						Want:      "Example One",
 						FieldName: "Value Set Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "two non matching",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("ExampleOne", "Example Three", testLocation),
 				createTestValueSetWithNameAndTitle("ExampleTwo", "Example Three", testLocation),
 			},
@@ -149,7 +145,6 @@ func TestLintValueSetNameMatchesTitle(t *testing.T) {
 						Want:      "Example One",
 						FieldName: "Value Set Title",
 					},
-					IsFixable: true,
 				},
 				{
 					RuleID:   sut.ID(),
@@ -160,69 +155,68 @@ func TestLintValueSetNameMatchesTitle(t *testing.T) {
 						Want:      "Example Two",
 						FieldName: "Value Set Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "no space after number",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("123Abc", "123Abc", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no space before number",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("Abc123", "Abc123", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no spaces around numbers",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("A123B", "A123b", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "no space numbers before or after",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("123Abc456", "123abc456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "one space after none before",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("123Abc456", "123 abc456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "one space before none after",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("123Abc456", "123abc 456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "spaces before and after",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("123Abc456", "123 ABC 456", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "extra spaces between numbers",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("1234abc", "1 2 34ABC", testLocation),
 			},
 			want: nil,
 		},
 		{
 			name: "double space should not pass",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("12", "1  2", testLocation),
 			},
 			want: []*lint.Problem{
@@ -235,13 +229,12 @@ func TestLintValueSetNameMatchesTitle(t *testing.T) {
 						Want:      "12",
 						FieldName: "Value Set Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "should not start with a space",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("1Abc", " 1abc", testLocation),
 			},
 			want: []*lint.Problem{
@@ -254,13 +247,12 @@ func TestLintValueSetNameMatchesTitle(t *testing.T) {
 						Want:      "1 Abc",
 						FieldName: "Value Set Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
 		{
 			name: "should not end with a space",
-			valueSets: []*types.ValueSet{
+			valueSets: []*ast.ValueSet{
 				createTestValueSetWithNameAndTitle("Abc1", "Abc1 ", testLocation),
 			},
 			want: []*lint.Problem{
@@ -273,7 +265,6 @@ func TestLintValueSetNameMatchesTitle(t *testing.T) {
 						Want:      "Abc 1",
 						FieldName: "Value Set Title",
 					},
-					IsFixable: true,
 				},
 			},
 		},
@@ -281,8 +272,9 @@ func TestLintValueSetNameMatchesTitle(t *testing.T) {
 
 	for _, tt := range tests {
 		t.Run(tt.name, func(t *testing.T) {
+			// The problems are not fixable without the data of the file.
 			fileContext := lint.FileContext{
-				ParsedFSH: &types.FSHDocument{
+				ParsedFSH: &ast.FSHDocument{
 					ValueSets: tt.valueSets,
 				},
 			}
@@ -297,13 +289,13 @@ func TestLintValueSetNameMatchesTitle(t *testing.T) {
 	}
 }
 
-func createTestValueSetWithNameAndTitle(name string, title string, testLocation *types.Location) *types.ValueSet {
-	return &types.ValueSet{
-		Name: &types.ParsedElement[string]{
+func createTestValueSetWithNameAndTitle(name string, title string, testLocation *ast.Location) *ast.ValueSet {
+	return &ast.ValueSet{
+		Name: &ast.ParsedElement[string]{
 			Value:    name,
 			Location: testLocation,
 		},
-		Title: &types.ParsedElement[string]{
+		Title: &ast.ParsedElement[string]{
 			Value:    title,
 			Location: testLocation,
 		},
@@ -0,0 +1,150 @@
+package main
+
+import (
+	"errors"
+	"fmt"
+	"io"
+	"maps"
+	"os"
+	"slices"
+	"strings"
+
+	"github.com/spf13/pflag"
+	"github.com/verily-src/fsh-lint/internal/atomicfile"
+	"github.com/verily-src/fsh-lint/internal/split"
+	"github.com/verily-src/fsh-lint/internal/textdiff"
+)
+
+// splitFile is a file that is split into files of its own for its entities.
+type splitFile struct {
+	path string
+
+	// data is the contents of the file when it was read, and remaining is what
+	// remains of it once split, or nil if nothing does.
+	data, remaining []byte
+
+	// files are the contents of the files that its entities are moved into, by
+	// path.
+	files map[string][]byte
+}
+
+// runSplit moves the profiles, extensions, instances, value sets, and code
+// systems of the selected files that define more than one of them into files
+// of their own, named after them. Aliases, rule sets, and invariants stay where
+// they are, which SUSHI resolves across the files of a project. All files are
+// split in memory, and checked not to exist, before any is written, so that no
+// file is overwritten.
+func runSplit(w io.Writer, args []string) error {
+	fs := pflag.NewFlagSet("split", pflag.ContinueOnError)
+	installSelectionFlags(fs)
+	dryRun := fs.Bool("dry-run", false, "Print a unified diff of the changes without modifying files.")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() > 0 {
+		return fmt.Errorf("unexpected arguments: %v", fs.Args())
+	}
+
+	files, err := parsedFilesFromFlags(fs)
+	if err != nil {
+		return err
+	}
+	var splits []*splitFile
+	// sources are the paths of the files that the new files are split from.
+	sources := make(map[string]string)
+	for _, f := range files {
+		remaining, newFiles, err := split.File(f.path, f.data)
+		if err != nil {
+			return fmt.Errorf("splitting %s: %w", f.path, err)
+		}
+		if newFiles == nil {
+			continue
+		}
+		for path := range newFiles {
+			if source, ok := sources[path]; ok {
+				return fmt.Errorf("%s would be created from both %s and %s", path, source, f.path)
+			}
+			if _, err := os.Lstat(path); err == nil {
+				return fmt.Errorf("%s already exists, so %s cannot be split", path, f.path)
+			}
+			sources[path] = f.path
+		}
+		splits = append(splits, &splitFile{path: f.path, data: f.data, remaining: remaining, files: newFiles})
+	}
+	slices.SortFunc(splits, func(a, b *splitFile) int {
+		return strings.Compare(a.path, b.path)
+	})
+
+	if *dryRun {
+		for _, s := range splits {
+			if _, err := io.WriteString(w, textdiff.Unified(s.path, s.data, s.remaining)); err != nil {
+				return err
+			}
+		}
+		for _, s := range splits {
+			for _, path := range slices.Sorted(maps.Keys(s.files)) {
+				if _, err := io.WriteString(w, textdiff.Unified(path, nil, s.files[path])); err != nil {
+					return err
+				}
+			}
+		}
+		return nil
+	}
+
+	if err := writeSplits(splits); err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "Split %d files into %d new files\n", len(splits), len(sources))
+	return err
+}
+
+// writeSplits creates the new files of the splits, and then replaces or
+// removes the split files. Nothing is written if a split file changed on disk
+// since it was read. If a step fails, the new files of the files that were not
+// yet replaced are removed again, so that no entity is left defined twice.
+func writeSplits(splits []*splitFile) error {
+	for _, s := range splits {
+		if err := atomicfile.Check(s.path, s.data); err != nil {
+			return err
+		}
+	}
+
+	// undo removes the new files of the splits that were created.
+	var created []string
+	undo := func(splits []*splitFile, err error) error {
+		for _, s := range splits {
+			for _, path := range slices.Sorted(maps.Keys(s.files)) {
+				if slices.Contains(created, path) {
+					err = errors.Join(err, atomicfile.Remove(path, s.files[path]))
+				}
+			}
+		}
+		return err
+	}
+
+	for i, s := range splits {
+		mode := os.FileMode(0644)
+		if info, err := os.Stat(s.path); err == nil {
+			mode = info.Mode().Perm()
+		}
+		for _, path := range slices.Sorted(maps.Keys(s.files)) {
+			if err := atomicfile.Create(path, s.files[path], mode); err != nil {
+				return undo(splits[:i+1], fmt.Errorf("creating %s: %w", path, err))
+			}
+			created = append(created, path)
+		}
+	}
+
+	for i, s := range splits {
+		var err error
+		if s.remaining == nil {
+			err = atomicfile.Remove(s.path, s.data)
+		} else {
+			err = atomicfile.Write(s.path, s.data, s.remaining)
+		}
+		if err != nil {
+			return undo(splits[i:], fmt.Errorf("writing %s: %w", s.path, err))
+		}
+	}
+	return nil
+}
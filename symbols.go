@@ -0,0 +1,175 @@
+package main
+
+import (
+	"encoding/json"
+	"fmt"
+	"io"
+	"log"
+	"os"
+	"text/tabwriter"
+
+	"github.com/spf13/pflag"
+	"github.com/verily-src/fsh-lint/ast"
+	"github.com/verily-src/fsh-lint/internal/fsh"
+	"github.com/verily-src/fsh-lint/internal/symbols"
+)
+
+// runSymbols lists the entities defined in the selected files, in text or JSON
+// format.
+func runSymbols(w io.Writer, args []string) error {
+	fs := pflag.NewFlagSet("symbols", pflag.ContinueOnError)
+	installSelectionFlags(fs)
+	format := fs.String("format", "text", "The format to list the symbols in, one of: text, json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+
+	idx, err := indexFromFlags(fs)
+	if err != nil {
+		return err
+	}
+	syms := idx.AllSymbols()
+
+	switch *format {
+	case "json":
+		if syms == nil {
+			syms = []*symbols.Symbol{}
+		}
+		return writeJSON(w, syms)
+	case "text":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		_, _ = fmt.Fprintln(tw, "LOCATION\tKIND\tNAME\tID")
+		for _, s := range syms {
+			_, _ = fmt.Fprintf(tw, "%s:%d\t%s\t%s\t%s\n", s.Path, s.Location.Start.LineNumber, s.Kind, s.Name, s.ID)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("invalid format: %s", *format)
+	}
+}
+
+// refsResult is the JSON representation of the output of the refs command.
+type refsResult struct {
+	Definitions []*symbols.Symbol    `json:"definitions"`
+	References  []*symbols.Reference `json:"references"`
+}
+
+// runRefs prints the definitions of the entity with the given name or ID, and
+// every reference to it in the selected files, in text or JSON format.
+func runRefs(w io.Writer, args []string) error {
+	fs := pflag.NewFlagSet("refs", pflag.ContinueOnError)
+	installSelectionFlags(fs)
+	format := fs.String("format", "text", "The format to print the references in, one of: text, json")
+	if err := fs.Parse(args); err != nil {
+		return err
+	}
+	if fs.NArg() != 1 {
+		return fmt.Errorf("refs requires exactly one entity name or ID")
+	}
+
+	idx, err := indexFromFlags(fs)
+	if err != nil {
+		return err
+	}
+	name := fs.Arg(0)
+	result := &refsResult{
+		Definitions: idx.Lookup(name),
+		References:  idx.References(name),
+	}
+
+	switch *format {
+	case "json":
+		if result.Definitions == nil {
+			result.Definitions = []*symbols.Symbol{}
+		}
+		if result.References == nil {
+			result.References = []*symbols.Reference{}
+		}
+		return writeJSON(w, result)
+	case "text":
+		tw := tabwriter.NewWriter(w, 0, 0, 2, ' ', 0)
+		for _, s := range result.Definitions {
+			_, _ = fmt.Fprintf(tw, "%s:%d\tdefinition\t%s %s\n", s.Path, s.Location.Start.LineNumber, s.Kind, s.Name)
+		}
+		if len(result.Definitions) == 0 {
+			_, _ = fmt.Fprintf(tw, "-\tdefinition\t%s is not defined in the selected files\n", name)
+		}
+		for _, ref := range result.References {
+			_, _ = fmt.Fprintf(tw, "%s:%d\t%s\tin %s\n", ref.Path, ref.Location.Start.LineNumber, ref.Kind, ref.From)
+		}
+		return tw.Flush()
+	default:
+		return fmt.Errorf("invalid format: %s", *format)
+	}
+}
+
+// installSelectionFlags installs the flags that select the files to index or
+// query.
+func installSelectionFlags(fs *pflag.FlagSet) {
+	fs.String("env", "", "Read new line delimited list of files or directories from the given environment variable.")
+	fs.String("paths", "", "Read comma delimited list of files or directories. Defaults to the working directory.")
+	fs.StringSlice("exclude", nil, "Comma delimited list of patterns, with .gitignore semantics, of files or directories to skip.")
+}
+
+// parsedFile is a FSH file selected by the flags, and its parsed document.
+type parsedFile struct {
+	path string
+	data []byte
+	doc  *ast.FSHDocument
+}
+
+// parsedFilesFromFlags reads and parses the files selected by the flags. Files
+// that cannot be read or parsed are skipped with a warning, so that one broken
+// file does not prevent navigating or querying the others.
+func parsedFilesFromFlags(fs *pflag.FlagSet) ([]*parsedFile, error) {
+	paths, err := inputPathsFromFlags(fs)
+	if err != nil {
+		return nil, err
+	}
+	if paths == nil {
+		paths = []string{"."}
+	}
+	ignored, err := ignoreMatchersFromFlags(fs)
+	if err != nil {
+		return nil, err
+	}
+
+	var files []*parsedFile
+	for _, path := range fshFilesFromPaths(paths, ignored) {
+		data, err := os.ReadFile(path)
+		if err != nil {
+			log.Printf("Skipping %s: %v", path, err)
+			continue
+		}
+		doc, err := fsh.Parse(string(data))
+		if err != nil {
+			log.Printf("Skipping %s: %v", path, err)
+			continue
+		}
+		files = append(files, &parsedFile{path: path, data: data, doc: doc})
+	}
+	return files, nil
+}
+
+// indexFromFlags returns the symbol index of the files selected by the flags.
+func indexFromFlags(fs *pflag.FlagSet) (*symbols.Index, error) {
+	files, err := parsedFilesFromFlags(fs)
+	if err != nil {
+		return nil, err
+	}
+	idx := symbols.NewIndex()
+	for _, f := range files {
+		idx.Add(f.path, f.doc)
+	}
+	return idx, nil
+}
+
+// writeJSON writes v to w as indented JSON.
+func writeJSON(w io.Writer, v any) error {
+	data, err := json.MarshalIndent(v, "", "  ")
+	if err != nil {
+		return err
+	}
+	_, err = fmt.Fprintf(w, "%s\n", data)
+	return err
+}
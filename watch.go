@@ -0,0 +1,152 @@
+package main
+
+import (
+	"context"
+	"fmt"
+	"log"
+	"os"
+	"os/signal"
+	"path/filepath"
+	"time"
+
+	"github.com/bmatcuk/doublestar/v4"
+	"github.com/spf13/pflag"
+	"github.com/verily-src/fsh-lint/internal/config"
+	"github.com/verily-src/fsh-lint/internal/ignore"
+	"github.com/verily-src/fsh-lint/internal/watch"
+	"github.com/verily-src/fsh-lint/lint"
+)
+
+// clearScreen moves the cursor to the top left corner and clears the terminal.
+const clearScreen = "\x1b[H\x1b[2J"
+
+// runWatch lints the files selected by the flags, and lints them again whenever
+// a FSH file, the configuration, or the ignore file changes, until interrupted.
+// The files are selected again for every run, so that new files are linted. The
+// linter and its reporter are reused across runs, with an in-memory cache so
+// that only modified files are parsed again.
+func runWatch(fs *pflag.FlagSet, linter *lint.Linter) error {
+	poll, err := fs.GetBool("watch-poll")
+	if err != nil {
+		return err
+	}
+	roots, err := watchRootsFromFlags(fs)
+	if err != nil {
+		return err
+	}
+
+	if linter.Cache == nil {
+		linter.Cache = lint.NewCache(linterVersion())
+	}
+
+	ctx, stop := signal.NotifyContext(context.Background(), os.Interrupt)
+	defer stop()
+
+	watcher := watch.New(roots, isWatchedFile, poll)
+	defer func() { _ = watcher.Close() }()
+
+	mode := "file system notifications"
+	if watcher.Polling {
+		mode = fmt.Sprintf("polling every %s", watch.PollInterval)
+	}
+
+	for {
+		_, _ = fmt.Fprint(os.Stderr, clearScreen)
+		if err := lintOnce(fs, linter); err != nil {
+			linter.Reporter.Errorf("%v", err)
+			linter.Reporter.Flush()
+		}
+		log.Printf("[%s] Watching for changes using %s. Press Ctrl+C to exit.", time.Now().Format(time.TimeOnly), mode)
+
+		if !waitForChanges(ctx, watcher) {
+			return nil
+		}
+	}
+}
+
+// waitForChanges blocks until the watcher reports changes, returning true, or
+// the context is done, returning false.
+func waitForChanges(ctx context.Context, watcher *watch.Watcher) bool {
+	for {
+		select {
+		case <-ctx.Done():
+			return false
+		case err := <-watcher.Errors:
+			log.Printf("Error watching files: %v", err)
+		case <-watcher.Changes:
+			return true
+		}
+	}
+}
+
+// lintOnce selects the files from the flags and lints them, printing the
+// summary at the end.
+func lintOnce(fs *pflag.FlagSet, linter *lint.Linter) error {
+	linter.Reporter.Reset()
+	linter.HasErrors = false
+
+	cfg, err := configFromFlags(fs)
+	if err != nil {
+		return err
+	}
+	if err := applyConfig(linter, cfg); err != nil {
+		return err
+	}
+
+	changes, err := changesFromFlags(fs)
+	if err != nil {
+		return err
+	}
+	newCodeOnly, err := fs.GetBool("new-code-only")
+	if err != nil {
+		return err
+	}
+	// The filter is assigned on every run, so that one of an earlier run with
+	// other changes is not kept.
+	linter.Filter = nil
+	if newCodeOnly && changes != nil {
+		linter.Filter = newCodeFilter(changes)
+	}
+
+	files, err := filesFromFlags(fs, changes)
+	if err != nil {
+		return err
+	}
+
+	linter.LintFiles(files)
+	linter.Reporter.PrintSummary()
+	return nil
+}
+
+// watchRootsFromFlags returns the files and directories to watch for the input
+// paths. Glob patterns are watched from the directory before the first glob
+// meta character. Without input paths, the working directory is watched.
+func watchRootsFromFlags(fs *pflag.FlagSet) ([]string, error) {
+	paths, err := inputPathsFromFlags(fs)
+	if err != nil {
+		return nil, err
+	}
+	if paths == nil {
+		return []string{"."}, nil
+	}
+
+	var roots []string
+	for _, path := range paths {
+		if hasGlobMeta(path) {
+			base, _ := doublestar.SplitPattern(filepath.ToSlash(path))
+			path = filepath.FromSlash(base)
+		}
+		roots = append(roots, path)
+	}
+	return roots, nil
+}
+
+// isWatchedFile returns true if changes to the file at the given path should
+// trigger linting again.
+func isWatchedFile(path string) bool {
+	switch filepath.Base(path) {
+	case config.FileName, ignore.FileName:
+		return true
+	}
+	return filepath.Ext(path) == ".fsh"
+}
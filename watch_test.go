@@ -0,0 +1,31 @@
+package main
+
+import (
+	"path/filepath"
+	"testing"
+
+	"github.com/spf13/pflag"
+	"github.com/verily-src/fsh-lint/internal/cli/diagnostic/diagnostictest"
+	"github.com/verily-src/fsh-lint/lint"
+	"github.com/verily-src/fsh-lint/rules"
+)
+
+func TestLintOnce_ClearsFilter(t *testing.T) {
+	fs := pflag.NewFlagSet("lint", pflag.ContinueOnError)
+	installFlags(fs)
+	if err := fs.Parse([]string{"--paths", filepath.Join(t.TempDir(), "*.fsh")}); err != nil {
+		t.Fatal(err)
+	}
+	reporter, _ := diagnostictest.NewFakeReporter()
+	linter := lint.NewLinter(nil, []lint.Rule{&rules.ValueSetNameMatchesIDRule{}})
+	linter.Reporter = reporter
+	// A filter of an earlier run with --new-code-only.
+	linter.Filter = func(string, *lint.Problem) bool { return false }
+
+	if err := lintOnce(fs, linter); err != nil {
+		t.Fatalf("lintOnce() got error %v, want nil", err)
+	}
+	if linter.Filter != nil {
+		t.Error("lintOnce() kept the filter of an earlier run, want nil")
+	}
+}